@@ -0,0 +1,285 @@
+// Package client is a Go SDK for mini-dockerd, the CLI of tiny-docker is built on it as well.
+package client
+
+import (
+	"context"
+	"io"
+
+	"github.com/0x822a5b87/tiny-docker/src/conf"
+	"github.com/0x822a5b87/tiny-docker/src/constant"
+	"github.com/0x822a5b87/tiny-docker/src/entity"
+	"github.com/0x822a5b87/tiny-docker/src/handler"
+)
+
+type Client struct {
+	socketPath string
+}
+
+type Option func(*Client)
+
+// WithSocketPath makes the client talk to the mini-dockerd listening on path.
+func WithSocketPath(path string) Option {
+	return func(c *Client) {
+		c.socketPath = path
+	}
+}
+
+// New creates a client, the socket path defaults to the one of the current environment.
+func New(opts ...Option) *Client {
+	c := &Client{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.socketPath == "" {
+		c.socketPath = conf.RuntimeDockerdUdsFile.Get()
+	}
+	return c
+}
+
+// FromConfig creates a client from the config.yaml in the working directory.
+func FromConfig() *Client {
+	conf.LoadBasicCommand()
+	return New()
+}
+
+func (c *Client) SocketPath() string {
+	return c.socketPath
+}
+
+func (c *Client) ContainerList(ctx context.Context, all bool) ([]entity.Container, error) {
+	return call[conf.PsCommand, []entity.Container](ctx, c, constant.Ps, conf.PsCommand{All: all})
+}
+
+func (c *Client) ContainerInspect(ctx context.Context, id entity.ContainerId) (*entity.Container, error) {
+	container, err := call[entity.Container, entity.Container](ctx, c, constant.Inspect, entity.Container{Id: id})
+	if err != nil {
+		return nil, err
+	}
+	return &container, nil
+}
+
+// ContainerCreate persists spec in mini-dockerd, the container is run by ContainerStart.
+func (c *Client) ContainerCreate(ctx context.Context, spec conf.RunCommands) (*entity.Container, error) {
+	container, err := call[conf.RunCommands, entity.Container](ctx, c, constant.Create, spec)
+	if err != nil {
+		return nil, err
+	}
+	return &container, nil
+}
+
+func (c *Client) ContainerStart(ctx context.Context, id entity.ContainerId) error {
+	return send(ctx, c, constant.Start, entity.Container{Id: id})
+}
+
+// ContainerRestart stops the container, killing it after timeout seconds, and starts it again.
+func (c *Client) ContainerRestart(ctx context.Context, id entity.ContainerId, timeout int) error {
+	return send(ctx, c, constant.Restart, conf.RestartCommand{Id: id, Timeout: timeout})
+}
+
+// ContainerStartAttach starts the container and writes its output to w until it exits, the
+// exited container is returned.
+func (c *Client) ContainerStartAttach(ctx context.Context, id entity.ContainerId, w io.Writer) (*entity.Container, error) {
+	var container *entity.Container
+	err := stream(ctx, c, constant.Attach, conf.AttachCommand{Id: id, Start: true}, func(rsp handler.Response) error {
+		if rsp.More {
+			data, err := handler.DataFromResponse[string](rsp)
+			if err != nil {
+				return err
+			}
+			_, err = io.WriteString(w, data)
+			return err
+		}
+		exited, err := handler.DataFromResponse[entity.Container](rsp)
+		container = &exited
+		return err
+	})
+	return container, err
+}
+
+// ContainerStats invokes fn with every sample of the usage of the containers of command, see
+// conf.StatsCommand. A streaming call returns once ctx is cancelled.
+func (c *Client) ContainerStats(ctx context.Context, command conf.StatsCommand, fn func([]entity.ContainerStats) error) error {
+	return stream(ctx, c, constant.Stats, command, func(rsp handler.Response) error {
+		stats, err := handler.DataFromResponse[[]entity.ContainerStats](rsp)
+		if err != nil {
+			return err
+		}
+		return fn(stats)
+	})
+}
+
+// Events invokes fn with every event of containers from now on, until ctx is cancelled.
+func (c *Client) Events(ctx context.Context, fn func(entity.Event) error) error {
+	return stream(ctx, c, constant.Events, struct{}{}, func(rsp handler.Response) error {
+		event, err := handler.DataFromResponse[entity.Event](rsp)
+		if err != nil {
+			return err
+		}
+		return fn(event)
+	})
+}
+
+// ContainerStop sends the stop signal to the container and kills it after timeout seconds.
+func (c *Client) ContainerStop(ctx context.Context, id entity.ContainerId, timeout int) error {
+	return send(ctx, c, constant.Stop, conf.StopCommand{Id: id, Timeout: timeout})
+}
+
+// ContainerPause freezes all processes of the container.
+func (c *Client) ContainerPause(ctx context.Context, id entity.ContainerId) error {
+	return send(ctx, c, constant.Pause, entity.Container{Id: id})
+}
+
+// ContainerUnpause resumes the processes of a paused container.
+func (c *Client) ContainerUnpause(ctx context.Context, id entity.ContainerId) error {
+	return send(ctx, c, constant.Unpause, entity.Container{Id: id})
+}
+
+// ContainerUpdate changes the resource limits of the container, see conf.UpdateCommand.
+func (c *Client) ContainerUpdate(ctx context.Context, command conf.UpdateCommand) error {
+	return send(ctx, c, constant.Update, command)
+}
+
+// ContainerKill sends signal to the container, a signal number or name, SIGKILL if it is empty.
+func (c *Client) ContainerKill(ctx context.Context, id entity.ContainerId, signal string) error {
+	return send(ctx, c, constant.Kill, conf.KillCommand{Id: id, Signal: signal})
+}
+
+// ContainerWait blocks until the container is not running and returns its final state, the
+// exit code of the container is in its ExitCode.
+func (c *Client) ContainerWait(ctx context.Context, id entity.ContainerId) (*entity.Container, error) {
+	container, err := call[entity.Container, entity.Container](ctx, c, constant.Wait, entity.Container{Id: id})
+	if err != nil {
+		return nil, err
+	}
+	return &container, nil
+}
+
+// ContainerRemove removes a container and everything it left behind, a running container is
+// only removed with force.
+func (c *Client) ContainerRemove(ctx context.Context, id entity.ContainerId, force bool, volumes bool) error {
+	return send(ctx, c, constant.Rm, conf.RmCommand{Id: id, Force: force, Volumes: volumes})
+}
+
+func (c *Client) ContainerLogs(ctx context.Context, id entity.ContainerId) (string, error) {
+	return call[entity.Container, string](ctx, c, constant.Logs, entity.Container{Id: id})
+}
+
+// ContainerRegister reports a container which has just been started to mini-dockerd.
+func (c *Client) ContainerRegister(ctx context.Context, container entity.Container) error {
+	return send(ctx, c, constant.Run, container)
+}
+
+// ContainerExit reports how the process of a container terminated to mini-dockerd.
+func (c *Client) ContainerExit(ctx context.Context, request entity.ExitRequest) error {
+	return send(ctx, c, constant.Exit, request)
+}
+
+func (c *Client) Commit(ctx context.Context, commands conf.CommitCommands) error {
+	return send(ctx, c, constant.Commit, commands)
+}
+
+func (c *Client) ImageList(ctx context.Context) ([]entity.Image, error) {
+	return call[struct{}, []entity.Image](ctx, c, constant.ImageList, struct{}{})
+}
+
+func (c *Client) NetworkCreate(ctx context.Context, name string) (*entity.Network, error) {
+	return callNetwork(ctx, c, constant.NetworkCreate, name)
+}
+
+func (c *Client) NetworkRemove(ctx context.Context, name string) (*entity.Network, error) {
+	return callNetwork(ctx, c, constant.NetworkRm, name)
+}
+
+func (c *Client) NetworkInspect(ctx context.Context, name string) (*entity.Network, error) {
+	return callNetwork(ctx, c, constant.NetworkInspect, name)
+}
+
+func (c *Client) NetworkList(ctx context.Context) ([]*entity.Network, error) {
+	return call[struct{}, []*entity.Network](ctx, c, constant.NetworkList, struct{}{})
+}
+
+func callNetwork(ctx context.Context, c *Client, act constant.Action, name string) (*entity.Network, error) {
+	network, err := call[entity.Network, entity.Network](ctx, c, act, entity.Network{Name: name})
+	if err != nil {
+		return nil, err
+	}
+	return &network, nil
+}
+
+// VolumeCreate creates a volume with a driver and its options, the local driver if it is empty.
+func (c *Client) VolumeCreate(ctx context.Context, command conf.VolumeCommand) (*entity.Volume, error) {
+	volume, err := call[conf.VolumeCommand, entity.Volume](ctx, c, constant.VolumeCreate, command)
+	if err != nil {
+		return nil, err
+	}
+	return &volume, nil
+}
+
+func (c *Client) VolumeRemove(ctx context.Context, name string) (*entity.Volume, error) {
+	return callVolume(ctx, c, constant.VolumeRm, name)
+}
+
+func (c *Client) VolumeInspect(ctx context.Context, name string) (*entity.Volume, error) {
+	return callVolume(ctx, c, constant.VolumeInspect, name)
+}
+
+func (c *Client) VolumeList(ctx context.Context) ([]*entity.Volume, error) {
+	return call[struct{}, []*entity.Volume](ctx, c, constant.VolumeList, struct{}{})
+}
+
+// VolumePrune removes the unused anonymous volumes, or every unused volume with all.
+func (c *Client) VolumePrune(ctx context.Context, all bool) ([]*entity.Volume, error) {
+	return call[conf.VolumeCommand, []*entity.Volume](ctx, c, constant.VolumePrune, conf.VolumeCommand{All: all})
+}
+
+func callVolume(ctx context.Context, c *Client, act constant.Action, name string) (*entity.Volume, error) {
+	volume, err := call[conf.VolumeCommand, entity.Volume](ctx, c, act, conf.VolumeCommand{Name: name})
+	if err != nil {
+		return nil, err
+	}
+	return &volume, nil
+}
+
+// send sends a request whose response carries no data.
+func send[D any](ctx context.Context, c *Client, act constant.Action, data D) error {
+	_, err := do(ctx, c, act, data)
+	return err
+}
+
+func call[D any, T any](ctx context.Context, c *Client, act constant.Action, data D) (T, error) {
+	var t T
+	rsp, err := do(ctx, c, act, data)
+	if err != nil {
+		return t, err
+	}
+	return handler.DataFromResponse[T](rsp)
+}
+
+func do[D any](ctx context.Context, c *Client, act constant.Action, data D) (handler.Response, error) {
+	var rsp handler.Response
+	err := stream(ctx, c, act, data, func(r handler.Response) error {
+		rsp = r
+		return nil
+	})
+	return rsp, err
+}
+
+// stream invokes fn with every frame of a response, a frame carrying an error aborts the call.
+func stream[D any](ctx context.Context, c *Client, act constant.Action, data D, fn func(handler.Response) error) error {
+	req, err := handler.ParamsIntoRequest(act, data)
+	if err != nil {
+		return err
+	}
+	conn, err := handler.Dial(ctx, c.socketPath)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = conn.Close() }()
+
+	return conn.Do(ctx, req, func(rsp handler.Response) error {
+		if err := rsp.Err(); err != nil {
+			return err
+		}
+		return fn(rsp)
+	})
+}
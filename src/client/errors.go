@@ -0,0 +1,28 @@
+package client
+
+import (
+	"errors"
+
+	"github.com/0x822a5b87/tiny-docker/src/constant"
+	"github.com/0x822a5b87/tiny-docker/src/handler"
+)
+
+// Error is returned when mini-dockerd rejects a request, it matches the constant.Err of the same
+// code with errors.Is, e.g. errors.Is(err, constant.ErrResourceNotFound).
+type Error = handler.RemoteError
+
+func IsNotFound(err error) bool {
+	return errors.Is(err, constant.ErrResourceNotFound) ||
+		errors.Is(err, constant.ErrResourceNotExists) ||
+		errors.Is(err, constant.ErrContainerNotFound) ||
+		errors.Is(err, constant.ErrVolumeNotFound)
+}
+
+func IsConflict(err error) bool {
+	return errors.Is(err, constant.ErrResourceExists) ||
+		errors.Is(err, constant.ErrDeviceIsBusy) ||
+		errors.Is(err, constant.ErrIllegalContainerStatus) ||
+		errors.Is(err, constant.ErrContainerNameInUse) ||
+		errors.Is(err, constant.ErrVolumeExists) ||
+		errors.Is(err, constant.ErrVolumeInUse)
+}
@@ -0,0 +1,43 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/0x822a5b87/tiny-docker/src/constant"
+	"github.com/0x822a5b87/tiny-docker/src/handler"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorMapping(t *testing.T) {
+	rsp, _ := handler.ErrorResponse(fmt.Errorf("lookup: %w", constant.ErrResourceNotFound), constant.ErrExecCommand)
+	err := rsp.Err()
+	assert.True(t, IsNotFound(err))
+	assert.False(t, IsConflict(err))
+
+	var e *Error
+	assert.True(t, errors.As(err, &e))
+	assert.Equal(t, constant.ErrResourceNotFound.ErrorCode, e.Code)
+
+	rsp, _ = handler.ErrorResponse(errors.New("boom"), constant.ErrExecCommand)
+	assert.ErrorIs(t, rsp.Err(), constant.ErrExecCommand)
+	assert.False(t, IsNotFound(rsp.Err()))
+
+	rsp, _ = handler.ErrorResponse(constant.ErrContainerNameInUse.WrapMessage("web"), constant.ErrExecCommand)
+	assert.True(t, IsConflict(rsp.Err()))
+	assert.Contains(t, rsp.Err().Error(), "web")
+
+	rsp, _ = handler.ErrorResponse(constant.ErrVolumeNotFound.WrapMessage("data"), constant.ErrExecCommand)
+	assert.True(t, IsNotFound(rsp.Err()))
+	assert.False(t, IsConflict(rsp.Err()))
+
+	for _, volumeErr := range []constant.Err{constant.ErrVolumeExists, constant.ErrVolumeInUse} {
+		rsp, _ = handler.ErrorResponse(volumeErr.WrapMessage("data"), constant.ErrExecCommand)
+		assert.True(t, IsConflict(rsp.Err()), volumeErr.ErrorText)
+		assert.False(t, IsNotFound(rsp.Err()), volumeErr.ErrorText)
+	}
+
+	rsp, _ = handler.SuccessResponse("{}")
+	assert.NoError(t, rsp.Err())
+}
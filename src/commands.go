@@ -1,8 +1,13 @@
 package main
 
 import (
+	ctx "context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 
+	"github.com/0x822a5b87/tiny-docker/src/client"
 	"github.com/0x822a5b87/tiny-docker/src/conf"
 	"github.com/0x822a5b87/tiny-docker/src/constant"
 	"github.com/0x822a5b87/tiny-docker/src/daemon"
@@ -47,13 +52,95 @@ var runCommand = cli.Command{
 			Name:  "d",
 			Usage: "detach daemon",
 		},
+		cli.StringFlag{
+			Name:  "name",
+			Usage: "Assign a name to the container",
+		},
+		cli.BoolFlag{
+			Name:  "rm",
+			Usage: "Automatically remove the container when it exits",
+		},
+		cli.StringFlag{
+			Name:  "restart",
+			Usage: "Restart policy to apply when a container exits: no, on-failure[:max-retries], always or unless-stopped",
+			Value: string(conf.RestartNo),
+		},
+		cli.StringFlag{
+			Name:  "stop-signal",
+			Usage: "Signal to stop the container",
+			Value: constant.DefaultStopSignal,
+		},
 		cli.StringFlag{
 			Name:  "m",
 			Usage: "memory limit",
 		},
+		cli.StringFlag{
+			Name:  "memory-swap",
+			Usage: "Swap limit equal to memory plus swap, -1 to enable unlimited swap",
+		},
+		cli.StringFlag{
+			Name:  "memory-reservation",
+			Usage: "Memory soft limit kept in memory.low, reclaimed only under memory pressure",
+		},
+		cli.StringFlag{
+			Name:  "memory-min",
+			Usage: "Memory which is never reclaimed, kept in memory.min",
+		},
+		cli.StringFlag{
+			Name:  "memory-high",
+			Usage: "Memory usage above which the container is throttled and reclaimed, kept in memory.high",
+		},
+		cli.BoolFlag{
+			Name:  "oom-kill-group",
+			Usage: "Kill all processes of the container together when the OOM killer picks one of them",
+		},
 		cli.StringFlag{
 			Name:  "c",
-			Usage: "cpu share limit",
+			Usage: "CPU quota and period of cpu.max, e.g. '50000 100000'",
+		},
+		cli.StringFlag{
+			Name:  "cpus",
+			Usage: "Number of CPUs, e.g. 1.5",
+		},
+		cli.Int64Flag{
+			Name:  "cpu-shares",
+			Usage: "CPU shares relative to other containers, between 2 and 262144",
+		},
+		cli.StringFlag{
+			Name:  "cpuset-cpus",
+			Usage: "CPUs in which to allow execution, e.g. 0-3,6",
+		},
+		cli.StringFlag{
+			Name:  "cpuset-mems",
+			Usage: "Memory nodes in which to allow execution, e.g. 0-1",
+		},
+		cli.Int64Flag{
+			Name:  "pids-limit",
+			Usage: "Limit the number of processes of the container, -1 for unlimited",
+		},
+		cli.UintFlag{
+			Name:  "blkio-weight",
+			Usage: "Relative block IO weight, between 10 and 1000",
+		},
+		cli.StringSliceFlag{
+			Name:  "device-read-bps",
+			Usage: "Limit the read rate of a device, format: `/dev/sda:10mb`",
+		},
+		cli.StringSliceFlag{
+			Name:  "device-write-bps",
+			Usage: "Limit the write rate of a device, format: `/dev/sda:10mb`",
+		},
+		cli.StringSliceFlag{
+			Name:  "device-read-iops",
+			Usage: "Limit the read operations per second of a device, format: `/dev/sda:1000`",
+		},
+		cli.StringSliceFlag{
+			Name:  "device-write-iops",
+			Usage: "Limit the write operations per second of a device, format: `/dev/sda:1000`",
+		},
+		cli.StringFlag{
+			Name:  "cgroup-parent",
+			Usage: "Parent cgroup of the container, a slice like `tiny.slice` with the systemd cgroup driver",
 		},
 		cli.StringFlag{
 			Name:  "entrypoint",
@@ -65,7 +152,23 @@ var runCommand = cli.Command{
 		},
 		&cli.StringSliceFlag{
 			Name:  "volume,v",
-			Usage: "Set volumes for the daemon",
+			Usage: "Bind mount a host path or a volume, format: `[/host/path|name:]/container/path[:ro]`",
+		},
+		&cli.StringSliceFlag{
+			Name:  "tmpfs",
+			Usage: "Mount a tmpfs, format: `/container/path[:options]`, e.g. /run:size=64m,mode=1777",
+		},
+		&cli.BoolFlag{
+			Name:  "read-only",
+			Usage: "Mount the root filesystem of the container read only",
+		},
+		&cli.StringSliceFlag{
+			Name:  "device",
+			Usage: "Add a device of the host, format: `/host/path[:/container/path][:rwm]`, e.g. /dev/fuse",
+		},
+		&cli.StringFlag{
+			Name:  "shm-size",
+			Usage: "Size of /dev/shm, 64m by default",
 		},
 	},
 	Action: func(context *cli.Context) error {
@@ -74,18 +177,39 @@ var runCommand = cli.Command{
 			log.Error(err, "error parse image and args")
 			return err
 		}
+		restartPolicy, err := conf.ParseRestartPolicy(context.String("restart"))
+		if err != nil {
+			return err
+		}
 		runCommands := conf.RunCommands{}
+		runCommands.RestartPolicy = restartPolicy
+		runCommands.StopSignal = context.String("stop-signal")
 		runCommands.Tty = context.Bool("it")
 		runCommands.Detach = context.Bool("d")
-		runCommands.Volume = context.String("v")
+		runCommands.Name = context.String("name")
+		runCommands.AutoRemove = context.Bool("rm")
 		runCommands.Image = image
 		runCommands.Args = args
-		runCommands.Cfg = conf.CgroupConfig{
-			MemoryLimit: context.String("m"),
-			CpuShares:   context.String("c"),
+		cfg, err := parseCgroupConfig(context)
+		if err != nil {
+			return err
 		}
+		runCommands.Cfg = cfg
 		runCommands.UserEnv = context.StringSlice("env")
-		err = daemon.RunContainerCmd(runCommands)
+		if runCommands.Mounts, err = conf.ParseVolumes(context.StringSlice("volume")); err != nil {
+			return err
+		}
+		if runCommands.Tmpfs, err = conf.ParseTmpfsList(context.StringSlice("tmpfs"), runCommands.Mounts); err != nil {
+			return err
+		}
+		runCommands.ReadOnly = context.Bool("read-only")
+		if runCommands.Devices, err = conf.ParseDevices(context.StringSlice("device")); err != nil {
+			return err
+		}
+		if runCommands.ShmSize, err = conf.ParseShmSize(context.String("shm-size")); err != nil {
+			return err
+		}
+		err = runContainer(runCommands)
 		if err != nil {
 			log.Errorf("error sending run request: %v\n", err)
 			return err
@@ -94,6 +218,85 @@ var runCommand = cli.Command{
 	},
 }
 
+// parseCgroupConfig reads the resource limits of run.
+func parseCgroupConfig(context *cli.Context) (conf.CgroupConfig, error) {
+	cfg := conf.CgroupConfig{
+		MemoryLimit:       context.String("m"),
+		MemorySwap:        context.String("memory-swap"),
+		MemoryReservation: context.String("memory-reservation"),
+		MemoryMin:         context.String("memory-min"),
+		MemoryHigh:        context.String("memory-high"),
+		OomGroup:          context.Bool("oom-kill-group"),
+		CpuMax:            context.String("c"),
+		CpusetCpus:        context.String("cpuset-cpus"),
+		CpusetMems:        context.String("cpuset-mems"),
+		PidsLimit:         context.Int64("pids-limit"),
+		CgroupParent:      context.String("cgroup-parent"),
+	}
+	var err error
+	if cpus := context.String("cpus"); cpus != "" {
+		if cfg.CpuMax != "" {
+			return cfg, constant.ErrConflictingOptions.WrapMessage("-c and --cpus")
+		}
+		if cfg.CpuMax, err = conf.ParseCpus(cpus); err != nil {
+			return cfg, err
+		}
+	}
+	if cfg.CpuWeight, err = conf.CpuSharesToWeight(context.Int64("cpu-shares")); err != nil {
+		return cfg, err
+	}
+	weight := context.Uint("blkio-weight")
+	if weight > conf.MaxBlkioWeight {
+		return cfg, constant.ErrMalformedArgs.WrapMessage(fmt.Sprintf("blkio weight %d is out of range [10, 1000]", weight))
+	}
+	cfg.BlkioWeight = uint16(weight)
+	if err = conf.ValidateBlkioWeight(cfg.BlkioWeight); err != nil {
+		return cfg, err
+	}
+	if cfg.DeviceReadBps, err = conf.ParseThrottleDevices(context.StringSlice("device-read-bps"), true); err != nil {
+		return cfg, err
+	}
+	if cfg.DeviceWriteBps, err = conf.ParseThrottleDevices(context.StringSlice("device-write-bps"), true); err != nil {
+		return cfg, err
+	}
+	if cfg.DeviceReadIOps, err = conf.ParseThrottleDevices(context.StringSlice("device-read-iops"), false); err != nil {
+		return cfg, err
+	}
+	if cfg.DeviceWriteIOps, err = conf.ParseThrottleDevices(context.StringSlice("device-write-iops"), false); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+// runContainer creates the container in mini-dockerd first, which assigns its id and name. A
+// detached container is started by mini-dockerd as well, otherwise it runs in the foreground.
+func runContainer(runCommands conf.RunCommands) error {
+	if runCommands.Tty && runCommands.Detach {
+		return constant.ErrProcessTerminalAndDaemonMode
+	}
+	image, err := filepath.Abs(runCommands.Image)
+	if err != nil {
+		return err
+	}
+	runCommands.Image = image
+	c := client.FromConfig()
+	container, err := c.ContainerCreate(ctx.Background(), runCommands)
+	if err != nil {
+		return err
+	}
+	if runCommands.Detach {
+		if err = c.ContainerStart(ctx.Background(), container.Id); err != nil {
+			return err
+		}
+		fmt.Println(container.Id)
+		return nil
+	}
+	runCommands.Id = container.Id
+	runCommands.Name = container.Name
+	runCommands.Cgroup = container.Cgroup
+	return daemon.RunContainerCmd(runCommands)
+}
+
 var initContainerCommand = cli.Command{
 	Name:  constant.InitContainer.String(),
 	Usage: `Init container process run user's process in daemon. Do not call it outside.`,
@@ -106,31 +309,37 @@ var initContainerCommand = cli.Command{
 	},
 }
 
+var initLaunchCommand = cli.Command{
+	Name:  constant.InitLaunch.String(),
+	Usage: `Launch a created container from its spec in daemon. Do not call it outside.`,
+	Action: func(context *cli.Context) error {
+		if context.NArg() != 1 {
+			return constant.ErrMalformedArgs
+		}
+		return daemon.LaunchContainer(entity.ContainerId(context.Args().First()))
+	},
+}
+
 var commitCommand = cli.Command{
 	Name:  constant.Commit.String(),
 	Usage: `Create a compression file(.tar) from a daemon`,
 	Flags: []cli.Flag{
-		&cli.StringSliceFlag{
+		&cli.StringFlag{
 			Name:  "s",
-			Usage: "Id of daemon to commit",
+			Usage: "Name or id of the container to commit",
 		},
-		&cli.StringSliceFlag{
+		&cli.StringFlag{
 			Name:  "t",
 			Usage: "Target name of committed daemon",
 		},
-		&cli.StringSliceFlag{
-			Name:  "v",
-			Usage: "Volume of daemon to commit",
-		},
 	},
 	Action: func(context *cli.Context) error {
 		cmd := conf.CommitCommands{
 			SrcName: context.String("s"),
 			DstName: context.String("t"),
-			Volume:  context.String("v"),
 		}
 
-		return daemon.SendCommitRequest(cmd)
+		return client.FromConfig().Commit(ctx.Background(), cmd)
 	},
 }
 
@@ -145,21 +354,292 @@ var psCommand = cli.Command{
 	},
 	Action: func(context *cli.Context) error {
 		all := context.Bool("a")
-		return daemon.SendPsRequest(conf.PsCommand{All: all})
+		containers, err := client.FromConfig().ContainerList(ctx.Background(), all)
+		if err != nil {
+			return err
+		}
+		printContainerTable(containers)
+		return nil
 	},
 }
 
 var stopCommand = cli.Command{
 	Name:  constant.Stop.String(),
-	Usage: `Stop target container`,
-	Flags: []cli.Flag{},
+	Usage: `Stop one or more running containers`,
+	Flags: []cli.Flag{
+		cli.IntFlag{
+			Name:  "t",
+			Usage: "Seconds to wait for the container to stop before killing it",
+			Value: constant.DefaultStopTimeout,
+		},
+	},
 	Action: func(context *cli.Context) error {
-		args := context.Args()
-		containerIds := make([]entity.ContainerId, 0)
-		for _, arg := range args {
-			containerIds = append(containerIds, entity.ContainerId(arg))
+		if context.NArg() == 0 {
+			return constant.ErrMalformedArgs
+		}
+		c := client.FromConfig()
+		return forEachContainer(context.Args(), func(id entity.ContainerId) error {
+			return c.ContainerStop(ctx.Background(), id, context.Int("t"))
+		})
+	},
+}
+
+var killCommand = cli.Command{
+	Name:  constant.Kill.String(),
+	Usage: `Send a signal to one or more running containers`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "s",
+			Usage: "Signal to send to the container, a number or a name like SIGHUP",
+			Value: "SIGKILL",
+		},
+	},
+	Action: func(context *cli.Context) error {
+		if context.NArg() == 0 {
+			return constant.ErrMalformedArgs
+		}
+		c := client.FromConfig()
+		return forEachContainer(context.Args(), func(id entity.ContainerId) error {
+			return c.ContainerKill(ctx.Background(), id, context.String("s"))
+		})
+	},
+}
+
+var pauseCommand = cli.Command{
+	Name:  constant.Pause.String(),
+	Usage: `Pause all processes within one or more containers`,
+	Action: func(context *cli.Context) error {
+		if context.NArg() == 0 {
+			return constant.ErrMalformedArgs
+		}
+		c := client.FromConfig()
+		return forEachContainer(context.Args(), func(id entity.ContainerId) error {
+			return c.ContainerPause(ctx.Background(), id)
+		})
+	},
+}
+
+var unpauseCommand = cli.Command{
+	Name:  constant.Unpause.String(),
+	Usage: `Unpause all processes within one or more containers`,
+	Action: func(context *cli.Context) error {
+		if context.NArg() == 0 {
+			return constant.ErrMalformedArgs
 		}
-		return daemon.SendStopRequest(conf.StopCommand{ContainerIds: containerIds})
+		c := client.FromConfig()
+		return forEachContainer(context.Args(), func(id entity.ContainerId) error {
+			return c.ContainerUnpause(ctx.Background(), id)
+		})
+	},
+}
+
+var updateCommand = cli.Command{
+	Name:  constant.Update.String(),
+	Usage: `Update the resource limits of one or more containers`,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "memory,m",
+			Usage: "Memory limit, e.g. 512m",
+		},
+		cli.StringFlag{
+			Name:  "memory-swap",
+			Usage: "Swap limit equal to memory plus swap, -1 to enable unlimited swap",
+		},
+		cli.StringFlag{
+			Name:  "memory-reservation",
+			Usage: "Memory soft limit",
+		},
+		cli.StringFlag{
+			Name:  "cpus",
+			Usage: "Number of CPUs, e.g. 1.5",
+		},
+		cli.StringFlag{
+			Name:  "cpu-shares",
+			Usage: "CPU shares relative to other containers, between 2 and 262144",
+		},
+		cli.StringFlag{
+			Name:  "cpuset-cpus",
+			Usage: "CPUs in which to allow execution, e.g. 0-3,6",
+		},
+		cli.StringFlag{
+			Name:  "cpuset-mems",
+			Usage: "Memory nodes in which to allow execution, e.g. 0-1",
+		},
+		cli.StringFlag{
+			Name:  "pids-limit",
+			Usage: "Limit the number of processes, -1 for unlimited",
+		},
+	},
+	Action: func(context *cli.Context) error {
+		if context.NArg() == 0 {
+			return constant.ErrMalformedArgs
+		}
+		c := client.FromConfig()
+		return forEachContainer(context.Args(), func(id entity.ContainerId) error {
+			return c.ContainerUpdate(ctx.Background(), conf.UpdateCommand{
+				Id:                id,
+				Memory:            context.String("memory"),
+				MemorySwap:        context.String("memory-swap"),
+				MemoryReservation: context.String("memory-reservation"),
+				Cpus:              context.String("cpus"),
+				CpuShares:         context.String("cpu-shares"),
+				CpusetCpus:        context.String("cpuset-cpus"),
+				CpusetMems:        context.String("cpuset-mems"),
+				PidsLimit:         context.String("pids-limit"),
+			})
+		})
+	},
+}
+
+var statsCommand = cli.Command{
+	Name:  constant.Stats.String(),
+	Usage: `Display a live stream of resource usage of containers, all running ones by default`,
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "no-stream",
+			Usage: "Print the first sample only instead of refreshing it",
+		},
+	},
+	Action: func(context *cli.Context) error {
+		command := conf.StatsCommand{Stream: !context.Bool("no-stream")}
+		for _, arg := range context.Args() {
+			command.Ids = append(command.Ids, entity.ContainerId(arg))
+		}
+		return client.FromConfig().ContainerStats(ctx.Background(), command, func(stats []entity.ContainerStats) error {
+			if command.Stream {
+				// clear the screen and move the cursor home, so the table refreshes in place
+				fmt.Print("\033[2J\033[H")
+			}
+			printStatsTable(stats)
+			return nil
+		})
+	},
+}
+
+var eventsCommand = cli.Command{
+	Name:  constant.Events.String(),
+	Usage: `Print the events of containers as they happen, like start, die and oom`,
+	Action: func(context *cli.Context) error {
+		return client.FromConfig().Events(ctx.Background(), func(event entity.Event) error {
+			printEvent(event)
+			return nil
+		})
+	},
+}
+
+var startCommand = cli.Command{
+	Name:  constant.Start.String(),
+	Usage: `Start one or more stopped containers`,
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "a",
+			Usage: "Attach to the output of the container and exit with its exit code",
+		},
+	},
+	Action: func(context *cli.Context) error {
+		if context.NArg() == 0 {
+			return constant.ErrMalformedArgs
+		}
+		c := client.FromConfig()
+		if context.Bool("a") {
+			if context.NArg() != 1 {
+				return constant.ErrMalformedArgs
+			}
+			container, err := c.ContainerStartAttach(ctx.Background(), entity.ContainerId(context.Args().First()), os.Stdout)
+			if err != nil {
+				return err
+			}
+			if container.ExitCode != 0 {
+				return cli.NewExitError("", container.ExitCode)
+			}
+			return nil
+		}
+		return forEachContainer(context.Args(), func(id entity.ContainerId) error {
+			return c.ContainerStart(ctx.Background(), id)
+		})
+	},
+}
+
+var restartCommand = cli.Command{
+	Name:  constant.Restart.String(),
+	Usage: `Restart one or more containers`,
+	Flags: []cli.Flag{
+		cli.IntFlag{
+			Name:  "t",
+			Usage: "Seconds to wait for the container to stop before killing it",
+			Value: constant.DefaultStopTimeout,
+		},
+	},
+	Action: func(context *cli.Context) error {
+		if context.NArg() == 0 {
+			return constant.ErrMalformedArgs
+		}
+		c := client.FromConfig()
+		return forEachContainer(context.Args(), func(id entity.ContainerId) error {
+			return c.ContainerRestart(ctx.Background(), id, context.Int("t"))
+		})
+	},
+}
+
+// forEachContainer runs fn for every container given on the command line, it prints the ones that
+// succeeded and the error of each one that failed.
+func forEachContainer(args []string, fn func(id entity.ContainerId) error) error {
+	failed := false
+	for _, arg := range args {
+		if err := fn(entity.ContainerId(arg)); err != nil {
+			failed = true
+			_, _ = fmt.Fprintf(os.Stderr, "Error response from daemon for %s: %v\n", arg, err)
+			continue
+		}
+		fmt.Println(arg)
+	}
+	if failed {
+		return cli.NewExitError("", 1)
+	}
+	return nil
+}
+
+var rmCommand = cli.Command{
+	Name:  constant.Rm.String(),
+	Usage: `Remove one or more containers`,
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "f",
+			Usage: "Force the removal of a running container",
+		},
+		cli.BoolFlag{
+			Name:  "v",
+			Usage: "Remove anonymous volumes associated with the container",
+		},
+	},
+	Action: func(context *cli.Context) error {
+		if context.NArg() == 0 {
+			return constant.ErrMalformedArgs
+		}
+		c := client.FromConfig()
+		return forEachContainer(context.Args(), func(id entity.ContainerId) error {
+			return c.ContainerRemove(ctx.Background(), id, context.Bool("f"), context.Bool("v"))
+		})
+	},
+}
+
+var inspectCommand = cli.Command{
+	Name:  constant.Inspect.String(),
+	Usage: `Display detailed information on one or more containers`,
+	Action: func(context *cli.Context) error {
+		if context.NArg() == 0 {
+			return constant.ErrMalformedArgs
+		}
+		c := client.FromConfig()
+		containers := make([]*entity.Container, 0, context.NArg())
+		for _, arg := range context.Args() {
+			container, err := c.ContainerInspect(ctx.Background(), entity.ContainerId(arg))
+			if err != nil {
+				return err
+			}
+			containers = append(containers, container)
+		}
+		return printJSON(containers)
 	},
 }
 
@@ -176,7 +656,32 @@ var logsCommand = cli.Command{
 		if len(containerIds) != 1 {
 			return constant.ErrMalformedLogsArgs
 		}
-		return daemon.SendLogRequest(conf.LogsCommand{ContainerId: containerIds[0]})
+		data, err := client.FromConfig().ContainerLogs(ctx.Background(), containerIds[0])
+		if err != nil {
+			return err
+		}
+		fmt.Print(data)
+		return nil
+	},
+}
+
+var waitCommand = cli.Command{
+	Name:  constant.Wait.String(),
+	Usage: `Block until a container stops, then print its exit code and exit with it`,
+	Flags: []cli.Flag{},
+	Action: func(context *cli.Context) error {
+		if context.NArg() != 1 {
+			return constant.ErrMalformedArgs
+		}
+		c, err := client.FromConfig().ContainerWait(ctx.Background(), entity.ContainerId(context.Args().First()))
+		if err != nil {
+			return err
+		}
+		fmt.Println(c.ExitCode)
+		if c.ExitCode != 0 {
+			return cli.NewExitError("", c.ExitCode)
+		}
+		return nil
 	},
 }
 
@@ -194,7 +699,19 @@ var execCommand = cli.Command{
 		if err != nil {
 			return err
 		}
-		return daemon.Exec(command)
+		c, err := client.FromConfig().ContainerInspect(ctx.Background(), command.Id)
+		if err != nil {
+			return err
+		}
+		if c.Status != entity.ContainerRunning {
+			return fmt.Errorf("exec: container %s is not running", command.Id)
+		}
+		env, err := util.ReadNsEnv(c.Pid)
+		if err != nil {
+			log.Errorf("exec read container env error: %v", err)
+			return err
+		}
+		return util.NsenterExec(c.Pid, command.Args, env)
 	},
 }
 
@@ -214,7 +731,7 @@ var networkCommand = cli.Command{
 
 func newNetworkCreateCommand() cli.Command {
 	return cli.Command{
-		Name:  constant.NetworkCreate.String(),
+		Name:  "create",
 		Usage: "Create a network",
 		Flags: []cli.Flag{
 			&cli.StringFlag{
@@ -235,9 +752,13 @@ func newNetworkCreateCommand() cli.Command {
 		},
 		Action: func(c *cli.Context) error {
 			networkName := c.String("name")
-			if err := daemon.SendNetworkCreate(networkName); err != nil {
+			network, err := client.FromConfig().NetworkCreate(ctx.Background(), networkName)
+			if err != nil {
 				return fmt.Errorf("failed to create network: %w", err)
 			}
+			if err = printJSON(network); err != nil {
+				return err
+			}
 			fmt.Printf("Network %s created successfully\n", networkName)
 			return nil
 		},
@@ -246,7 +767,7 @@ func newNetworkCreateCommand() cli.Command {
 
 func newNetworkConnectCommand() cli.Command {
 	return cli.Command{
-		Name:  constant.NetworkConnect.String(),
+		Name:  "connect",
 		Usage: "Connect a container to a network",
 		Flags: []cli.Flag{
 			&cli.StringFlag{
@@ -261,21 +782,23 @@ func newNetworkConnectCommand() cli.Command {
 			},
 		},
 		Action: func(c *cli.Context) error {
-			//networkName := c.String("network")
-			//containerID := c.String("container")
-			//
-			//if err := daemon.ConnectNetwork(networkName, containerID); err != nil {
+			networkName := c.String("network")
+			container, err := client.FromConfig().ContainerInspect(ctx.Background(), entity.ContainerId(c.String("container")))
+			if err != nil {
+				return fmt.Errorf("failed to connect network: %w", err)
+			}
+			//if err := daemon.ConnectNetwork(networkName, container.Id); err != nil {
 			//	return fmt.Errorf("failed to connect network: %w", err)
 			//}
-			//fmt.Printf("Container %s connected to network %s\n", containerID, networkName)
-			return nil
+			//fmt.Printf("Container %s connected to network %s\n", container.Id, networkName)
+			return constant.ErrUnsupportedAction.WrapMessage(fmt.Sprintf("connect %s to %s", container.Id, networkName))
 		},
 	}
 }
 
 func newNetworkRmCommand() cli.Command {
 	return cli.Command{
-		Name:  constant.NetworkRm.String(),
+		Name:  "rm",
 		Usage: "Remove a network",
 		Flags: []cli.Flag{
 			&cli.StringFlag{
@@ -286,9 +809,13 @@ func newNetworkRmCommand() cli.Command {
 		},
 		Action: func(c *cli.Context) error {
 			networkName := c.String("name")
-			if err := daemon.SendNetworkRm(networkName); err != nil {
+			network, err := client.FromConfig().NetworkRemove(ctx.Background(), networkName)
+			if err != nil {
 				return fmt.Errorf("failed to remove network: %w", err)
 			}
+			if err = printJSON(network); err != nil {
+				return err
+			}
 			fmt.Printf("Network %s removed successfully\n", networkName)
 			return nil
 		},
@@ -297,7 +824,7 @@ func newNetworkRmCommand() cli.Command {
 
 func newNetworkInspectCommand() cli.Command {
 	return cli.Command{
-		Name:  constant.NetworkInspect.String(),
+		Name:  "inspect",
 		Usage: "Inspect a network",
 		Flags: []cli.Flag{
 			&cli.StringFlag{
@@ -308,8 +835,166 @@ func newNetworkInspectCommand() cli.Command {
 		},
 		Action: func(c *cli.Context) error {
 			networkName := c.String("name")
-			if err := daemon.SendNetworkInspect(networkName); err != nil {
-				return fmt.Errorf("failed to remove network: %w", err)
+			network, err := client.FromConfig().NetworkInspect(ctx.Background(), networkName)
+			if err != nil {
+				return fmt.Errorf("failed to inspect network: %w", err)
+			}
+			return printJSON(network)
+		},
+	}
+}
+
+var volumeCommand = cli.Command{
+	Name:  constant.Volume.String(),
+	Usage: "Manage volumes: create/ls/inspect/rm/prune",
+	Subcommands: cli.Commands{
+		newVolumeCreateCommand(),
+		newVolumeListCommand(),
+		newVolumeInspectCommand(),
+		newVolumeRmCommand(),
+		newVolumePruneCommand(),
+	},
+	Action: func(c *cli.Context) error {
+		return cli.ShowSubcommandHelp(c)
+	},
+}
+
+func newVolumeCreateCommand() cli.Command {
+	return cli.Command{
+		Name:      "create",
+		Usage:     "Create a volume, a random name is chosen when VOLUME is not given",
+		ArgsUsage: "[VOLUME]",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "driver,d",
+				Usage: "Volume driver: local, or loop for a volume of a fixed size",
+				Value: entity.VolumeLocal,
+			},
+			cli.StringSliceFlag{
+				Name:  "opt,o",
+				Usage: "Set driver specific options, format: `KEY=VALUE`, e.g. size=1g for the loop driver",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			if c.NArg() > 1 {
+				return constant.ErrMalformedArgs
+			}
+			options, err := parseVolumeOptions(c.StringSlice("opt"))
+			if err != nil {
+				return err
+			}
+			volume, err := client.FromConfig().VolumeCreate(ctx.Background(), conf.VolumeCommand{
+				Name:    c.Args().First(),
+				Driver:  c.String("driver"),
+				Options: options,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to create volume: %w", err)
+			}
+			fmt.Println(volume.Name)
+			return nil
+		},
+	}
+}
+
+func parseVolumeOptions(opts []string) (map[string]string, error) {
+	if len(opts) == 0 {
+		return nil, nil
+	}
+	options := make(map[string]string, len(opts))
+	for _, opt := range opts {
+		key, value, ok := strings.Cut(opt, "=")
+		if !ok || key == "" {
+			return nil, constant.ErrInvalidVolume.WrapMessage(fmt.Sprintf("option %s is not like KEY=VALUE", opt))
+		}
+		options[key] = value
+	}
+	return options, nil
+}
+
+func newVolumeListCommand() cli.Command {
+	return cli.Command{
+		Name:  "ls",
+		Usage: "List volumes",
+		Action: func(c *cli.Context) error {
+			volumes, err := client.FromConfig().VolumeList(ctx.Background())
+			if err != nil {
+				return fmt.Errorf("failed to list volumes: %w", err)
+			}
+			printVolumeTable(volumes)
+			return nil
+		},
+	}
+}
+
+func newVolumeInspectCommand() cli.Command {
+	return cli.Command{
+		Name:      "inspect",
+		Usage:     "Display detailed information on one or more volumes",
+		ArgsUsage: "VOLUME [VOLUME...]",
+		Action: func(c *cli.Context) error {
+			if c.NArg() == 0 {
+				return constant.ErrMalformedArgs
+			}
+			dockerClient := client.FromConfig()
+			volumes := make([]*entity.Volume, 0, c.NArg())
+			for _, name := range c.Args() {
+				volume, err := dockerClient.VolumeInspect(ctx.Background(), name)
+				if err != nil {
+					return fmt.Errorf("failed to inspect volume: %w", err)
+				}
+				volumes = append(volumes, volume)
+			}
+			return printJSON(volumes)
+		},
+	}
+}
+
+func newVolumeRmCommand() cli.Command {
+	return cli.Command{
+		Name:      "rm",
+		Usage:     "Remove one or more volumes, a volume used by a container cannot be removed",
+		ArgsUsage: "VOLUME [VOLUME...]",
+		Action: func(c *cli.Context) error {
+			if c.NArg() == 0 {
+				return constant.ErrMalformedArgs
+			}
+			dockerClient := client.FromConfig()
+			failed := false
+			for _, name := range c.Args() {
+				if _, err := dockerClient.VolumeRemove(ctx.Background(), name); err != nil {
+					failed = true
+					_, _ = fmt.Fprintf(os.Stderr, "Error response from daemon for %s: %v\n", name, err)
+					continue
+				}
+				fmt.Println(name)
+			}
+			if failed {
+				return cli.NewExitError("", 1)
+			}
+			return nil
+		},
+	}
+}
+
+func newVolumePruneCommand() cli.Command {
+	return cli.Command{
+		Name:  "prune",
+		Usage: "Remove the anonymous volumes no container uses",
+		Flags: []cli.Flag{
+			cli.BoolFlag{
+				Name:  "all,a",
+				Usage: "Remove the unused named volumes as well",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			volumes, err := client.FromConfig().VolumePrune(ctx.Background(), c.Bool("all"))
+			if err != nil {
+				return fmt.Errorf("failed to prune volumes: %w", err)
+			}
+			fmt.Println("Deleted Volumes:")
+			for _, volume := range volumes {
+				fmt.Println(volume.Name)
 			}
 			return nil
 		},
@@ -0,0 +1,136 @@
+package conf
+
+import (
+	"cmp"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/0x822a5b87/tiny-docker/src/constant"
+	"github.com/0x822a5b87/tiny-docker/src/entity"
+)
+
+type CgroupDriver string
+
+const (
+	// CgroupDriverCgroupfs creates the cgroups of the containers by writing to the cgroup file
+	// system, CgroupDriverSystemd asks systemd for a transient scope per container, so that
+	// mini-dockerd does not fight systemd over the cgroups it owns.
+	CgroupDriverCgroupfs CgroupDriver = "cgroupfs"
+	CgroupDriverSystemd  CgroupDriver = "systemd"
+)
+
+// CgroupDriverConfig is the cgroup section of config.yaml:
+//
+//	cgroup:
+//	  driver: systemd
+//	  parent: tiny-docker.slice
+type CgroupDriverConfig struct {
+	Driver CgroupDriver `yaml:"driver"`
+	// Parent is where the cgroups of the containers are created unless they are run with
+	// --cgroup-parent, a path relative to the root of the hierarchy for cgroupfs and a slice for
+	// systemd. It defaults to constant.DefaultCgroupParent or constant.DefaultCgroupSlice.
+	Parent string `yaml:"parent"`
+}
+
+// Validate checks the cgroup section of config.yaml, an empty driver is cgroupfs.
+func (c CgroupDriverConfig) Validate() error {
+	switch c.Driver {
+	case "", CgroupDriverCgroupfs, CgroupDriverSystemd:
+	default:
+		return constant.ErrInvalidCgroupDriver.WrapMessage(string(c.Driver))
+	}
+	if c.Parent == "" {
+		return nil
+	}
+	return ValidateCgroupParent(c.driver(), c.Parent)
+}
+
+func (c CgroupDriverConfig) driver() CgroupDriver {
+	return cmp.Or(c.Driver, CgroupDriverCgroupfs)
+}
+
+// ContainerCgroup returns the cgroup of a container relative to the root of a hierarchy, it is
+// created in parent, or in the parent of the config if parent is empty.
+func (c CgroupDriverConfig) ContainerCgroup(id entity.ContainerId, parent string) (string, error) {
+	driver := c.driver()
+	if parent == "" {
+		parent = c.Parent
+	}
+	if driver == CgroupDriverSystemd {
+		slice, err := ExpandSlice(cmp.Or(parent, constant.DefaultCgroupSlice))
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(slice, ScopeUnit(id)), nil
+	}
+	parent = cmp.Or(parent, constant.DefaultCgroupParent)
+	if err := ValidateCgroupParent(driver, parent); err != nil {
+		return "", err
+	}
+	return filepath.Join(strings.TrimPrefix(filepath.Clean("/"+parent), "/"), string(id)), nil
+}
+
+// ValidateCgroupParent checks the value of --cgroup-parent, a slice is all systemd takes.
+func ValidateCgroupParent(driver CgroupDriver, parent string) error {
+	if driver == CgroupDriverSystemd {
+		_, err := ExpandSlice(parent)
+		return err
+	}
+	for _, name := range strings.Split(parent, "/") {
+		if name == ".." {
+			return constant.ErrInvalidCgroupParent.WrapMessage(fmt.Sprintf("%s must not leave the cgroup root", parent))
+		}
+	}
+	return nil
+}
+
+// ScopeUnit returns the name of the transient scope of a container with the systemd driver.
+func ScopeUnit(id entity.ContainerId) string {
+	return constant.CgroupScopePrefix + string(id) + ".scope"
+}
+
+// CgroupScope returns the unit of a cgroup which is a systemd scope, such a cgroup was created by
+// the systemd driver.
+func CgroupScope(cgroup string) (string, bool) {
+	unit := filepath.Base(cgroup)
+	return unit, strings.HasSuffix(unit, ".scope")
+}
+
+// ExpandSlice returns the cgroup of a systemd slice, a dash in its name stands for a parent
+// slice: tiny-docker.slice is at tiny.slice/tiny-docker.slice, and -.slice is the root.
+func ExpandSlice(slice string) (string, error) {
+	name, ok := strings.CutSuffix(slice, ".slice")
+	if !ok || strings.Contains(slice, "/") {
+		return "", constant.ErrInvalidCgroupParent.WrapMessage(fmt.Sprintf("%s is not a slice named like xxx.slice", slice))
+	}
+	if name == "-" {
+		return "", nil
+	}
+	if name == "" || strings.HasPrefix(name, "-") || strings.HasSuffix(name, "-") || strings.Contains(name, "--") {
+		return "", constant.ErrInvalidCgroupParent.WrapMessage(fmt.Sprintf("%s is not a valid slice", slice))
+	}
+	var path, prefix string
+	for _, part := range strings.Split(name, "-") {
+		prefix += part
+		path = filepath.Join(path, prefix+".slice")
+		prefix += "-"
+	}
+	return path, nil
+}
+
+// Controllers returns the controllers of cgroup v2 the limits of c need. cpu.max and memory.max are
+// written for every container, the other controllers are only needed for the limits which are set.
+func (c CgroupConfig) Controllers() []string {
+	controllers := []string{"cpu", "memory"}
+	if c.CpusetCpus != "" || c.CpusetMems != "" {
+		controllers = append(controllers, "cpuset")
+	}
+	if c.BlkioWeight != 0 || len(c.DeviceReadBps)+len(c.DeviceWriteBps)+len(c.DeviceReadIOps)+len(c.DeviceWriteIOps) > 0 {
+		controllers = append(controllers, "io")
+	}
+	if c.PidsLimit > 0 {
+		controllers = append(controllers, "pids")
+	}
+	return controllers
+}
@@ -0,0 +1,82 @@
+package conf
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/0x822a5b87/tiny-docker/src/constant"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandSlice(t *testing.T) {
+	valid := map[string]string{
+		"-.slice":             "",
+		"system.slice":        "system.slice",
+		"tiny-docker.slice":   "tiny.slice/tiny-docker.slice",
+		"a-b-c.slice":         "a.slice/a-b.slice/a-b-c.slice",
+		"machine-test.slice":  "machine.slice/machine-test.slice",
+		"user-1000.slice":     "user.slice/user-1000.slice",
+		"tiny_docker-x.slice": "tiny_docker.slice/tiny_docker-x.slice",
+	}
+	for slice, expected := range valid {
+		path, err := ExpandSlice(slice)
+		assert.NoError(t, err, slice)
+		assert.Equal(t, expected, path, slice)
+	}
+
+	for _, slice := range []string{"", "system", ".slice", "-a.slice", "a-.slice", "a--b.slice", "a/b.slice", "tiny.scope"} {
+		_, err := ExpandSlice(slice)
+		assert.True(t, errors.Is(err, constant.ErrInvalidCgroupParent), slice)
+	}
+}
+
+func TestContainerCgroup(t *testing.T) {
+	cgroupfs := CgroupDriverConfig{}
+	cgroup, err := cgroupfs.ContainerCgroup("abc", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "system.slice/tiny-docker.service/abc", cgroup)
+	cgroup, err = cgroupfs.ContainerCgroup("abc", "/tiny/")
+	assert.NoError(t, err)
+	assert.Equal(t, "tiny/abc", cgroup)
+	cgroup, err = CgroupDriverConfig{Parent: "docker"}.ContainerCgroup("abc", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "docker/abc", cgroup)
+	_, err = cgroupfs.ContainerCgroup("abc", "../escape")
+	assert.True(t, errors.Is(err, constant.ErrInvalidCgroupParent))
+
+	systemd := CgroupDriverConfig{Driver: CgroupDriverSystemd}
+	cgroup, err = systemd.ContainerCgroup("abc", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "system.slice/tiny-docker-abc.scope", cgroup)
+	cgroup, err = systemd.ContainerCgroup("abc", "tiny-docker.slice")
+	assert.NoError(t, err)
+	assert.Equal(t, "tiny.slice/tiny-docker.slice/tiny-docker-abc.scope", cgroup)
+	_, err = systemd.ContainerCgroup("abc", "system.slice/tiny")
+	assert.True(t, errors.Is(err, constant.ErrInvalidCgroupParent))
+
+	unit, ok := CgroupScope(cgroup)
+	assert.True(t, ok)
+	assert.Equal(t, "tiny-docker-abc.scope", unit)
+	_, ok = CgroupScope("system.slice/tiny-docker.service/abc")
+	assert.False(t, ok)
+}
+
+func TestValidateCgroupDriverConfig(t *testing.T) {
+	assert.NoError(t, CgroupDriverConfig{}.Validate())
+	assert.NoError(t, CgroupDriverConfig{Driver: CgroupDriverCgroupfs, Parent: "tiny"}.Validate())
+	assert.NoError(t, CgroupDriverConfig{Driver: CgroupDriverSystemd, Parent: "tiny.slice"}.Validate())
+
+	assert.True(t, errors.Is(CgroupDriverConfig{Driver: "docker"}.Validate(), constant.ErrInvalidCgroupDriver))
+	assert.True(t, errors.Is(CgroupDriverConfig{Driver: CgroupDriverSystemd, Parent: "tiny"}.Validate(), constant.ErrInvalidCgroupParent))
+}
+
+func TestCgroupConfigControllers(t *testing.T) {
+	assert.Equal(t, []string{"cpu", "memory"}, CgroupConfig{}.Controllers())
+	assert.Equal(t, []string{"cpu", "memory"}, CgroupConfig{PidsLimit: -1}.Controllers())
+	cfg := CgroupConfig{
+		CpusetMems:    "0",
+		DeviceReadBps: []ThrottleDevice{{Path: "/dev/sda", Rate: 1024}},
+		PidsLimit:     100,
+	}
+	assert.Equal(t, []string{"cpu", "memory", "cpuset", "io", "pids"}, cfg.Controllers())
+}
@@ -15,14 +15,21 @@ var RuntimePath PathType = "runtime"
 var LogPath PathType = "logs"
 var StatePath PathType = "state"
 var ContainerPath PathType = "container"
+var SpecPath PathType = "spec"
 
 var NetworksPath PathType = "networks"
 var NetworkPath PathType = "network"
 var EndpointPath PathType = "endpoint"
 var IPAMPath PathType = "IPAM"
 
+var VolumesPath PathType = "volumes"
+var VolumePath PathType = "volume"
+var VolumeDataPath PathType = "data"
+var VolumeLoopPath PathType = "loop"
+
 type Commands struct {
 	Id       entity.ContainerId
+	Name     string
 	Tty      bool
 	Detach   bool
 	Image    string
@@ -30,30 +37,67 @@ type Commands struct {
 	Args     []string
 	Cfg      CgroupConfig
 	UserEnv  []string
-	Volume   string
+	Mounts   []Mount
+	Tmpfs    []Tmpfs
+	// ReadOnly mounts the root of the container read only.
+	ReadOnly bool
+	Devices  []Device
+	ShmSize  int64
+	// Cgroup is the cgroup of the container relative to the root of a hierarchy.
+	Cgroup string
 }
 
 type RunCommands struct {
+	Id      entity.ContainerId
+	Name    string
 	Tty     bool
 	Detach  bool
 	Image   string
 	Args    []string
 	Cfg     CgroupConfig
 	UserEnv []string
-	Volume  string
+	// Mounts are the volumes given with -v.
+	Mounts []Mount
+	// Tmpfs are the tmpfs given with --tmpfs.
+	Tmpfs []Tmpfs
+	// ReadOnly mounts the root of the container read only, only its volumes and tmpfs are
+	// writable then.
+	ReadOnly bool
+	// Devices are the devices of the host given with --device.
+	Devices []Device
+	// ShmSize is the size of /dev/shm in bytes, DefaultShmSize unless it is given.
+	ShmSize int64
+	// AutoRemove removes the container once it exits.
+	AutoRemove    bool
+	RestartPolicy RestartPolicy
+	// StopSignal is sent to the container by stop, SIGTERM if it is empty.
+	StopSignal string
+	// Cgroup is the cgroup of the container relative to the root of a hierarchy, mini-dockerd
+	// chooses it by the cgroup driver when the container is created.
+	Cgroup string
 }
 
 func (r RunCommands) IntoCommands() Commands {
-	fullID, _ := GenContainerID()
+	id := r.Id
+	if id == "" {
+		fullID, _ := GenContainerID()
+		id = entity.ContainerId(fullID)
+	}
 	return Commands{
-		Id:      entity.ContainerId(fullID),
-		Tty:     r.Tty,
-		Detach:  r.Detach,
-		Image:   r.Image,
-		Args:    r.Args,
-		Cfg:     r.Cfg,
-		UserEnv: r.UserEnv,
-		Volume:  r.Volume,
+		Id:       id,
+		Name:     r.Name,
+		Tty:      r.Tty,
+		Detach:   r.Detach,
+		Image:    r.Image,
+		Args:     r.Args,
+		Cfg:      r.Cfg,
+		UserEnv:  r.UserEnv,
+		Mounts:   r.Mounts,
+		Tmpfs:    r.Tmpfs,
+		ReadOnly: r.ReadOnly,
+		Devices:  r.Devices,
+		ShmSize:  r.ShmSize,
+		Cgroup:   r.Cgroup,
 	}
 }
 
@@ -66,14 +110,16 @@ type ExecCommand struct {
 type CommitCommands struct {
 	SrcName string
 	DstName string
-	Volume  string
+	// Id and Image are those of the container SrcName refers to, filled in by mini-dockerd.
+	Id    entity.ContainerId
+	Image string
 }
 
 func (c CommitCommands) IntoCommands() Commands {
 	return Commands{
-		Image:    c.SrcName,
+		Id:       c.Id,
+		Image:    c.Image,
 		DstImage: c.DstName,
-		Volume:   c.Volume,
 	}
 }
 
@@ -82,23 +128,100 @@ type PsCommand struct {
 }
 
 type StopCommand struct {
-	ContainerIds []entity.ContainerId
+	Id entity.ContainerId
+	// Timeout is the number of seconds to wait for the container to stop before killing it.
+	Timeout int
+}
+
+type KillCommand struct {
+	Id entity.ContainerId
+	// Signal is a signal number or name, SIGKILL if it is empty.
+	Signal string
+}
+
+// UpdateCommand changes the resource limits of a container, an empty limit is left as it is.
+type UpdateCommand struct {
+	Id                entity.ContainerId
+	Memory            string
+	MemorySwap        string
+	MemoryReservation string
+	Cpus              string
+	CpuShares         string
+	CpusetCpus        string
+	CpusetMems        string
+	PidsLimit         string
+}
+
+// StatsCommand samples the usage of containers, all running containers if Ids is empty.
+type StatsCommand struct {
+	Ids []entity.ContainerId
+	// Stream keeps sending samples until the client goes away, otherwise a single one is sent.
+	Stream bool
+}
+
+type RestartCommand struct {
+	Id entity.ContainerId
+	// Timeout is the number of seconds to wait for the container to stop before killing it.
+	Timeout int
+}
+
+type AttachCommand struct {
+	Id entity.ContainerId
+	// Start starts the container before attaching to it.
+	Start bool
+}
+
+type RmCommand struct {
+	Id      entity.ContainerId
+	Force   bool
+	Volumes bool
 }
 
-type LogsCommand struct {
-	ContainerId entity.ContainerId
+type VolumeCommand struct {
+	Name    string
+	Driver  string
+	Options map[string]string
+	// All makes prune remove every unused volume instead of only the anonymous ones.
+	All bool
 }
 
 type CgroupConfig struct {
 	MemoryLimit string
-	CpuShares   string
+	// MemorySwap limits memory and swap together like docker, -1 means unlimited swap.
+	MemorySwap string
+	// MemoryReservation is a soft guarantee kept in memory.low, MemoryMin a hard one in
+	// memory.min, and MemoryHigh throttles the container before it reaches MemoryLimit.
+	MemoryReservation string
+	MemoryMin         string
+	MemoryHigh        string
+	// OomGroup makes the OOM killer kill the whole container instead of a single process.
+	OomGroup bool
+	// CpuMax is the quota and period of cpu.max, like "50000 100000". It used to be called
+	// CpuShares, the json name is kept so that the specs of existing containers still read.
+	CpuMax string `json:"CpuShares"`
+	// CpuWeight is the relative cpu weight from 1 to 10000, 0 keeps the default.
+	CpuWeight uint64
+	// CpusetCpus and CpusetMems pin the container to cpus and memory nodes, like 0-3,6.
+	CpusetCpus string
+	CpusetMems string
+	// PidsLimit bounds the number of processes, 0 or less means no limit.
+	PidsLimit int64
+	// BlkioWeight is the relative io weight from 10 to 1000, 0 keeps the default.
+	BlkioWeight     uint16
+	DeviceReadBps   []ThrottleDevice
+	DeviceWriteBps  []ThrottleDevice
+	DeviceReadIOps  []ThrottleDevice
+	DeviceWriteIOps []ThrottleDevice
+	// CgroupParent is the cgroup the container is created in, the parent of the config if empty.
+	CgroupParent string
 }
 
 type Config struct {
-	Meta     MetaConfig `yaml:"meta"`
-	Fs       FsConfig   `yaml:"fs"`
-	Cmd      Commands   `yaml:"cmd"`
-	InnerEnv []string   `yaml:"inner_env"`
+	Meta     MetaConfig         `yaml:"meta"`
+	Fs       FsConfig           `yaml:"fs"`
+	Cmd      Commands           `yaml:"cmd"`
+	Cgroup   CgroupDriverConfig `yaml:"cgroup"`
+	InnerEnv []string           `yaml:"inner_env"`
 }
 
 type MetaConfig struct {
@@ -118,7 +241,12 @@ func (c Config) ImageName() string {
 }
 
 func (c Config) ReadPath() string {
-	return filepath.Join(c.rootPath(), string(ImagePath), "read", c.ImageName())
+	return filepath.Join(c.ImagesReadPath(), c.ImageName())
+}
+
+// ImagesReadPath is the directory holding the read layer of every image.
+func (c Config) ImagesReadPath() string {
+	return filepath.Join(c.rootPath(), string(ImagePath), "read")
 }
 
 func (c Config) WritePath() string {
@@ -153,6 +281,18 @@ func (c Config) DockerdIpamPath() string {
 	return c.DockerdPath(NetworksPath, string(IPAMPath))
 }
 
+func (c Config) DockerdVolumePath() string {
+	return c.DockerdPath(VolumesPath, string(VolumePath))
+}
+
+func (c Config) DockerdVolumeDataPath() string {
+	return c.DockerdPath(VolumesPath, string(VolumeDataPath))
+}
+
+func (c Config) DockerdVolumeLoopPath() string {
+	return c.DockerdPath(VolumesPath, string(VolumeLoopPath))
+}
+
 func (c Config) DockerdLogFile() string {
 	return c.DockerdPath(LogPath, constant.DockerdLogFile)
 }
@@ -165,12 +305,12 @@ func (c Config) DockerdContainerLogPath() string {
 	return c.DockerdPath(ContainerPath, "")
 }
 
+func (c Config) DockerdContainerSpecPath() string {
+	return c.DockerdPath(SpecPath, "")
+}
+
 func (c Config) rootPath() string {
-	root := c.Fs.Root
-	if c.Cmd.Volume != "" {
-		root = c.Cmd.Volume
-	}
-	return filepath.Join(root)
+	return filepath.Join(c.Fs.Root)
 }
 
 func (c Config) Net(pathType PathType) string {
@@ -0,0 +1,43 @@
+package conf
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+
+	"github.com/0x822a5b87/tiny-docker/src/constant"
+	"github.com/0x822a5b87/tiny-docker/src/subsystem/cpu"
+)
+
+// ParseCpus turns the number of cpus of --cpus, like 1.5, into the quota and period of cpu.max.
+func ParseCpus(s string) (string, error) {
+	cpus, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return "", constant.ErrMalformedArgs.WrapMessage(fmt.Sprintf("invalid cpus %s", s))
+	}
+	// a smaller quota than 0.01 of the period is truncated to 0, which the kernel rejects
+	if !(cpus >= 0.01 && cpus <= float64(runtime.NumCPU())) {
+		return "", constant.ErrMalformedArgs.WrapMessage(
+			fmt.Sprintf("range of CPUs is from 0.01 to %d.00, as there are only %d CPUs available", runtime.NumCPU(), runtime.NumCPU()))
+	}
+	return fmt.Sprintf("%d %d", int(cpus*constant.CpuPeriod), constant.CpuPeriod), nil
+}
+
+// CpuSharesToWeight validates the shares of --cpu-shares and maps them onto cpu.weight, 0 keeps
+// the default weight.
+func CpuSharesToWeight(shares int64) (uint64, error) {
+	if shares != 0 && (shares < cpu.MinShares || shares > cpu.MaxShares) {
+		return 0, constant.ErrMalformedArgs.WrapMessage(
+			fmt.Sprintf("cpu shares %d is out of range [%d, %d]", shares, cpu.MinShares, cpu.MaxShares))
+	}
+	return cpu.SharesToWeight(uint64(shares)), nil
+}
+
+// ValidateCpuWeight accepts 0, which keeps the default weight, or a weight from 1 to 10000.
+func ValidateCpuWeight(weight uint64) error {
+	if weight > cpu.MaxWeight {
+		return constant.ErrMalformedArgs.WrapMessage(
+			fmt.Sprintf("cpu weight %d is out of range [%d, %d]", weight, cpu.MinWeight, cpu.MaxWeight))
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package conf
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCpus(t *testing.T) {
+	numCpu := runtime.NumCPU()
+	valid := map[string]string{
+		"0.01":             "1000 100000",
+		"1":                "100000 100000",
+		fmt.Sprint(numCpu): fmt.Sprintf("%d 100000", numCpu*100000),
+	}
+	invalid := []string{"0", "0.001", "0.000001", "-1", "NaN", "Inf", "one",
+		fmt.Sprintf("%d.5", numCpu), fmt.Sprint(numCpu + 1)}
+	if numCpu >= 2 {
+		valid["1.5"] = "150000 100000"
+	} else {
+		invalid = append(invalid, "1.5")
+	}
+	for cpus, expected := range valid {
+		cpuMax, err := ParseCpus(cpus)
+		assert.NoError(t, err, cpus)
+		assert.Equal(t, expected, cpuMax, cpus)
+	}
+
+	for _, cpus := range invalid {
+		_, err := ParseCpus(cpus)
+		assert.Error(t, err, cpus)
+	}
+}
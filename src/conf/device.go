@@ -0,0 +1,103 @@
+package conf
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"path/filepath"
+	"strings"
+
+	"github.com/0x822a5b87/tiny-docker/src/constant"
+	"github.com/0x822a5b87/tiny-docker/src/subsystem"
+)
+
+// DefaultShmSize is the size of /dev/shm unless it is given with --shm-size, like with docker.
+const DefaultShmSize int64 = 64 << 20
+
+// Device is a device of the host given with --device, which is created in the container.
+type Device struct {
+	PathOnHost      string
+	PathInContainer string
+	// Permissions are some of r, w and m like with docker. Containers are not confined by a
+	// devices cgroup, so they are kept for docker compatibility only.
+	Permissions string
+}
+
+// ParseDevice parses the value of --device: /host/path[:/container/path][:permissions], the
+// device keeps its path in the container unless another one is given.
+func ParseDevice(spec string) (Device, error) {
+	parts := strings.Split(spec, ":")
+	if len(parts) > 3 {
+		return Device{}, constant.ErrInvalidDevice.WrapMessage(spec)
+	}
+	device := Device{PathOnHost: parts[0], Permissions: "rwm"}
+	switch {
+	case len(parts) == 3:
+		device.PathInContainer, device.Permissions = parts[1], parts[2]
+	case len(parts) == 2 && isDevicePermissions(parts[1]):
+		device.Permissions = parts[1]
+	case len(parts) == 2:
+		device.PathInContainer = parts[1]
+	}
+	if device.PathInContainer == "" {
+		device.PathInContainer = device.PathOnHost
+	}
+	if !filepath.IsAbs(device.PathOnHost) || !filepath.IsAbs(device.PathInContainer) {
+		return Device{}, constant.ErrInvalidDevice.WrapMessage(fmt.Sprintf("%s is not an absolute path", spec))
+	}
+	if !isDevicePermissions(device.Permissions) {
+		return Device{}, constant.ErrInvalidDevice.WrapMessage(fmt.Sprintf("invalid permissions %s of %s", device.Permissions, spec))
+	}
+	device.PathOnHost = filepath.Clean(device.PathOnHost)
+	device.PathInContainer = filepath.Clean(device.PathInContainer)
+	return device, nil
+}
+
+// ParseDevices parses the values of --device.
+func ParseDevices(specs []string) ([]Device, error) {
+	devices := make([]Device, 0, len(specs))
+	for _, spec := range specs {
+		device, err := ParseDevice(spec)
+		if err != nil {
+			return nil, err
+		}
+		devices = append(devices, device)
+	}
+	return devices, nil
+}
+
+// DevicesFromEnv returns the devices the container process creates, they are passed to it by the
+// launcher in the environment.
+func DevicesFromEnv() ([]Device, error) {
+	value := Devices.Get()
+	if value == "" {
+		return nil, nil
+	}
+	var devices []Device
+	err := json.Unmarshal([]byte(value), &devices)
+	return devices, err
+}
+
+// ParseShmSize parses the value of --shm-size like 64m, an empty value leaves the default.
+func ParseShmSize(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	size, err := subsystem.SizeToBytes(value)
+	if err != nil || size <= 0 || size == math.MaxInt64 {
+		return 0, constant.ErrMalformedArgs.WrapMessage(fmt.Sprintf("invalid shm size %s", value))
+	}
+	return size, nil
+}
+
+func isDevicePermissions(permissions string) bool {
+	if permissions == "" || len(permissions) > 3 {
+		return false
+	}
+	for _, c := range permissions {
+		if !strings.ContainsRune("rwm", c) || strings.Count(permissions, string(c)) > 1 {
+			return false
+		}
+	}
+	return true
+}
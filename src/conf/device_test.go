@@ -0,0 +1,51 @@
+package conf
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/0x822a5b87/tiny-docker/src/constant"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDevice(t *testing.T) {
+	valid := map[string]Device{
+		"/dev/fuse":                 {PathOnHost: "/dev/fuse", PathInContainer: "/dev/fuse", Permissions: "rwm"},
+		"/dev/fuse:r":               {PathOnHost: "/dev/fuse", PathInContainer: "/dev/fuse", Permissions: "r"},
+		"/dev/sda:/dev/xvda":        {PathOnHost: "/dev/sda", PathInContainer: "/dev/xvda", Permissions: "rwm"},
+		"/dev/sda/:/dev/xvda:rw":    {PathOnHost: "/dev/sda", PathInContainer: "/dev/xvda", Permissions: "rw"},
+		"/dev/net/tun:/dev/tun:mwr": {PathOnHost: "/dev/net/tun", PathInContainer: "/dev/tun", Permissions: "mwr"},
+	}
+	for spec, expected := range valid {
+		device, err := ParseDevice(spec)
+		assert.NoError(t, err, spec)
+		assert.Equal(t, expected, device, spec)
+	}
+
+	invalid := []string{
+		"fuse",
+		"/dev/fuse:fuse",
+		"/dev/fuse:/dev/fuse:x",
+		"/dev/fuse:/dev/fuse:rr",
+		"/dev/fuse:/dev/fuse:",
+		"/dev/fuse:/dev/fuse:rwm:r",
+	}
+	for _, spec := range invalid {
+		_, err := ParseDevice(spec)
+		assert.True(t, errors.Is(err, constant.ErrInvalidDevice), spec)
+	}
+}
+
+func TestParseShmSize(t *testing.T) {
+	size, err := ParseShmSize("")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), size)
+	size, err = ParseShmSize("128m")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(128<<20), size)
+
+	for _, value := range []string{"0", "-1m", "max", "lots"} {
+		_, err = ParseShmSize(value)
+		assert.True(t, errors.Is(err, constant.ErrMalformedArgs), value)
+	}
+}
@@ -47,12 +47,32 @@ const FsMergeLayerPath EnvVariable = "tiny-docker-fs-merge-layer-path"
 
 const DetachMode EnvVariable = "tiny-docker-detach-mode"
 
+// Mounts holds the volumes of the container as JSON.
+const Mounts EnvVariable = "tiny-docker-mounts"
+
+// TmpfsMounts holds the tmpfs of the container as JSON.
+const TmpfsMounts EnvVariable = "tiny-docker-tmpfs"
+
+// ReadOnlyRootfs is true when the root of the container is mounted read only.
+const ReadOnlyRootfs EnvVariable = "tiny-docker-read-only"
+
+// Devices holds the devices given with --device as JSON.
+const Devices EnvVariable = "tiny-docker-devices"
+
+// ShmSize is the size of /dev/shm in bytes.
+const ShmSize EnvVariable = "tiny-docker-shm-size"
+
 const RuntimeDockerdUdsFile EnvVariable = "tiny-docker-runtime-dockerd-uds-file"
 const RuntimeDockerdUdsPidFile EnvVariable = "tiny-docker-runtime-dockerd-pid-file"
 const RuntimeDockerdLogFile EnvVariable = "tiny-docker-runtime-dockerd-log-file"
 const RuntimeDockerdContainerStatus EnvVariable = "tiny-docker-runtime-dockerd-container-status"
 const RuntimeDockerdContainerLog EnvVariable = "tiny-docker-runtime-dockerd-container-log"
+const RuntimeDockerdContainerSpec EnvVariable = "tiny-docker-runtime-dockerd-container-spec"
 
 const RuntimeNetworkPath EnvVariable = "tiny-docker-runtime-dockerd-network"
 const RuntimeEndpointPath EnvVariable = "tiny-docker-runtime-dockerd-endpoint"
 const RuntimeIpamPath EnvVariable = "tiny-docker-runtime-dockerd-ipam"
+
+const RuntimeVolumePath EnvVariable = "tiny-docker-runtime-dockerd-volume"
+const RuntimeVolumeDataPath EnvVariable = "tiny-docker-runtime-dockerd-volume-data"
+const RuntimeVolumeLoopPath EnvVariable = "tiny-docker-runtime-dockerd-volume-loop"
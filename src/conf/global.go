@@ -1,8 +1,10 @@
 package conf
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
 
 	"github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v3"
@@ -63,11 +65,35 @@ func Environ() {
 	env = appendEnv(env, RuntimeDockerdLogFile, GlobalConfig.DockerdLogFile())
 	env = appendEnv(env, RuntimeDockerdContainerStatus, GlobalConfig.DockerdContainerStatusPath())
 	env = appendEnv(env, RuntimeDockerdContainerLog, GlobalConfig.DockerdContainerLogPath())
+	env = appendEnv(env, RuntimeDockerdContainerSpec, GlobalConfig.DockerdContainerSpecPath())
 
 	env = appendEnv(env, RuntimeNetworkPath, GlobalConfig.DockerdNetworkPath())
 	env = appendEnv(env, RuntimeEndpointPath, GlobalConfig.DockerdEndpointPath())
 	env = appendEnv(env, RuntimeIpamPath, GlobalConfig.DockerdIpamPath())
 
+	env = appendEnv(env, RuntimeVolumePath, GlobalConfig.DockerdVolumePath())
+	env = appendEnv(env, RuntimeVolumeDataPath, GlobalConfig.DockerdVolumeDataPath())
+	env = appendEnv(env, RuntimeVolumeLoopPath, GlobalConfig.DockerdVolumeLoopPath())
+
+	if len(GlobalConfig.Cmd.Mounts) > 0 {
+		mounts, _ := json.Marshal(GlobalConfig.Cmd.Mounts)
+		env = appendEnv(env, Mounts, string(mounts))
+	}
+	if len(GlobalConfig.Cmd.Tmpfs) > 0 {
+		tmpfs, _ := json.Marshal(GlobalConfig.Cmd.Tmpfs)
+		env = appendEnv(env, TmpfsMounts, string(tmpfs))
+	}
+	if GlobalConfig.Cmd.ReadOnly {
+		env = appendEnv(env, ReadOnlyRootfs, "true")
+	}
+	if len(GlobalConfig.Cmd.Devices) > 0 {
+		devices, _ := json.Marshal(GlobalConfig.Cmd.Devices)
+		env = appendEnv(env, Devices, string(devices))
+	}
+	if GlobalConfig.Cmd.ShmSize > 0 {
+		env = appendEnv(env, ShmSize, strconv.FormatInt(GlobalConfig.Cmd.ShmSize, 10))
+	}
+
 	if GlobalConfig.Cmd.Detach {
 		env = appendEnv(env, DetachMode, "true")
 	} else {
@@ -0,0 +1,75 @@
+package conf
+
+import (
+	"fmt"
+
+	"github.com/0x822a5b87/tiny-docker/src/constant"
+	"github.com/0x822a5b87/tiny-docker/src/subsystem"
+	"github.com/0x822a5b87/tiny-docker/src/subsystem/memory"
+)
+
+// MemorySwapUnlimited is the --memory-swap which lets a container swap as much as it likes.
+const MemorySwapUnlimited = "-1"
+
+// ValidateMemory checks the memory limits of cfg against each other the way docker does.
+func (c CgroupConfig) ValidateMemory() error {
+	limit, err := parseMemory("memory", c.MemoryLimit)
+	if err != nil {
+		return err
+	}
+	reservation, err := parseMemory("memory reservation", c.MemoryReservation)
+	if err != nil {
+		return err
+	}
+	if _, err = parseMemory("memory high", c.MemoryHigh); err != nil {
+		return err
+	}
+	if _, err = parseMemory("memory min", c.MemoryMin); err != nil {
+		return err
+	}
+	if limit > 0 && reservation > limit {
+		return constant.ErrMalformedArgs.WrapMessage("minimum memory limit should be larger than memory reservation limit")
+	}
+	if c.MemorySwap == "" || c.MemorySwap == MemorySwapUnlimited {
+		return nil
+	}
+	swap, err := parseMemory("memory swap", c.MemorySwap)
+	if err != nil {
+		return err
+	}
+	if limit == 0 {
+		return constant.ErrMalformedArgs.WrapMessage("you should always set the memory limit when using memory swap limit")
+	}
+	if swap < limit {
+		return constant.ErrMalformedArgs.WrapMessage("minimum memory swap limit should be larger than memory limit")
+	}
+	return nil
+}
+
+// SwapMax returns the limit of memory.swap.max. --memory-swap limits memory and swap together
+// while the kernel limits the swap alone, so it is the difference to the memory limit.
+func (c CgroupConfig) SwapMax() (int64, error) {
+	if c.MemorySwap == MemorySwapUnlimited {
+		return memory.Unlimited, nil
+	}
+	swap, err := subsystem.SizeToBytes(c.MemorySwap)
+	if err != nil {
+		return 0, err
+	}
+	limit, err := subsystem.SizeToBytes(c.MemoryLimit)
+	if err != nil {
+		return 0, err
+	}
+	return swap - limit, nil
+}
+
+func parseMemory(name string, s string) (int64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	bytes, err := subsystem.SizeToBytes(s)
+	if err != nil {
+		return 0, constant.ErrMalformedArgs.WrapMessage(fmt.Sprintf("invalid %s %s: %v", name, s, err))
+	}
+	return bytes, nil
+}
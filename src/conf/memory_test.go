@@ -0,0 +1,33 @@
+package conf
+
+import (
+	"testing"
+
+	"github.com/0x822a5b87/tiny-docker/src/subsystem/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateMemory(t *testing.T) {
+	assert.NoError(t, CgroupConfig{}.ValidateMemory())
+	assert.NoError(t, CgroupConfig{MemoryLimit: "128m", MemorySwap: "256m", MemoryReservation: "64m"}.ValidateMemory())
+	assert.NoError(t, CgroupConfig{MemorySwap: MemorySwapUnlimited}.ValidateMemory())
+
+	assert.Error(t, CgroupConfig{MemorySwap: "256m"}.ValidateMemory())
+	assert.Error(t, CgroupConfig{MemoryLimit: "128m", MemorySwap: "64m"}.ValidateMemory())
+	assert.Error(t, CgroupConfig{MemoryLimit: "128m", MemoryReservation: "256m"}.ValidateMemory())
+	assert.Error(t, CgroupConfig{MemoryHigh: "lots"}.ValidateMemory())
+}
+
+func TestSwapMax(t *testing.T) {
+	swap, err := CgroupConfig{MemoryLimit: "128m", MemorySwap: "256m"}.SwapMax()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(128*1024*1024), swap)
+
+	swap, err = CgroupConfig{MemoryLimit: "128m", MemorySwap: "128m"}.SwapMax()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), swap)
+
+	swap, err = CgroupConfig{MemoryLimit: "128m", MemorySwap: MemorySwapUnlimited}.SwapMax()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(memory.Unlimited), swap)
+}
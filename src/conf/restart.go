@@ -0,0 +1,82 @@
+package conf
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/0x822a5b87/tiny-docker/src/constant"
+)
+
+type RestartPolicyName string
+
+const (
+	RestartNo            RestartPolicyName = "no"
+	RestartOnFailure     RestartPolicyName = "on-failure"
+	RestartAlways        RestartPolicyName = "always"
+	RestartUnlessStopped RestartPolicyName = "unless-stopped"
+)
+
+// RestartPolicy decides whether mini-dockerd starts a container again after it exited. The fields
+// are named like those of docker, so the policy of the Engine API decodes into it as is.
+type RestartPolicy struct {
+	Name RestartPolicyName
+	// MaximumRetryCount limits the restarts of on-failure, 0 means no limit.
+	MaximumRetryCount int
+}
+
+// ParseRestartPolicy parses the value of --restart: no, always, unless-stopped or on-failure[:max].
+func ParseRestartPolicy(s string) (RestartPolicy, error) {
+	name, count, hasCount := strings.Cut(s, ":")
+	policy := RestartPolicy{Name: RestartPolicyName(name)}
+	switch policy.Name {
+	case "":
+		policy.Name = RestartNo
+	case RestartNo, RestartAlways, RestartUnlessStopped:
+	case RestartOnFailure:
+		if hasCount {
+			n, err := strconv.Atoi(count)
+			if err != nil || n < 0 {
+				return RestartPolicy{}, constant.ErrInvalidRestartPolicy.WrapMessage(s)
+			}
+			policy.MaximumRetryCount = n
+		}
+		return policy, nil
+	default:
+		return RestartPolicy{}, constant.ErrInvalidRestartPolicy.WrapMessage(s)
+	}
+	if hasCount {
+		// only on-failure takes a maximum retry count
+		return RestartPolicy{}, constant.ErrInvalidRestartPolicy.WrapMessage(s)
+	}
+	return policy, nil
+}
+
+func (p RestartPolicy) IsNone() bool {
+	return p.Name == "" || p.Name == RestartNo
+}
+
+// ShouldRestart reports whether a container which exited with exitCode after restartCount restarts
+// is started again. A container stopped by the user is never restarted.
+func (p RestartPolicy) ShouldRestart(exitCode int, restartCount int, manuallyStopped bool) bool {
+	if manuallyStopped {
+		return false
+	}
+	switch p.Name {
+	case RestartAlways, RestartUnlessStopped:
+		return true
+	case RestartOnFailure:
+		return exitCode != 0 && (p.MaximumRetryCount == 0 || restartCount < p.MaximumRetryCount)
+	default:
+		return false
+	}
+}
+
+func (p RestartPolicy) String() string {
+	if p.Name == RestartOnFailure && p.MaximumRetryCount > 0 {
+		return string(p.Name) + ":" + strconv.Itoa(p.MaximumRetryCount)
+	}
+	if p.Name == "" {
+		return string(RestartNo)
+	}
+	return string(p.Name)
+}
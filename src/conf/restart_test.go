@@ -0,0 +1,43 @@
+package conf
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/0x822a5b87/tiny-docker/src/constant"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRestartPolicy(t *testing.T) {
+	valid := map[string]RestartPolicy{
+		"":               {Name: RestartNo},
+		"no":             {Name: RestartNo},
+		"always":         {Name: RestartAlways},
+		"unless-stopped": {Name: RestartUnlessStopped},
+		"on-failure":     {Name: RestartOnFailure},
+		"on-failure:3":   {Name: RestartOnFailure, MaximumRetryCount: 3},
+	}
+	for s, expected := range valid {
+		policy, err := ParseRestartPolicy(s)
+		assert.NoError(t, err, s)
+		assert.Equal(t, expected, policy, s)
+	}
+
+	for _, s := range []string{"sometimes", "always:3", "on-failure:x", "on-failure:-1"} {
+		_, err := ParseRestartPolicy(s)
+		assert.True(t, errors.Is(err, constant.ErrInvalidRestartPolicy), s)
+	}
+}
+
+func TestShouldRestart(t *testing.T) {
+	onFailure := RestartPolicy{Name: RestartOnFailure, MaximumRetryCount: 2}
+	assert.True(t, onFailure.ShouldRestart(1, 1, false))
+	assert.False(t, onFailure.ShouldRestart(1, 2, false))
+	assert.False(t, onFailure.ShouldRestart(0, 0, false))
+
+	always := RestartPolicy{Name: RestartAlways}
+	assert.True(t, always.ShouldRestart(0, 100, false))
+	assert.False(t, always.ShouldRestart(0, 0, true))
+
+	assert.False(t, RestartPolicy{}.ShouldRestart(1, 0, false))
+}
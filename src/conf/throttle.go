@@ -0,0 +1,68 @@
+package conf
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/0x822a5b87/tiny-docker/src/constant"
+	"github.com/0x822a5b87/tiny-docker/src/subsystem"
+)
+
+const (
+	// the range of --blkio-weight, like docker
+	MinBlkioWeight = 10
+	MaxBlkioWeight = 1000
+)
+
+// ThrottleDevice limits the rate of a block device, in bytes or operations per second. The fields
+// are named like those of docker, so the devices of the Engine API decode into it as is.
+type ThrottleDevice struct {
+	Path string
+	Rate uint64
+}
+
+// ParseThrottleDevice parses <device-path>:<rate> of --device-read-bps and the like, a rate in
+// bytes per second may have a unit like 10mb while a rate in operations per second is a number.
+func ParseThrottleDevice(s string, bytes bool) (ThrottleDevice, error) {
+	path, rate, ok := strings.Cut(s, ":")
+	if !ok || !strings.HasPrefix(path, "/dev/") {
+		return ThrottleDevice{}, constant.ErrMalformedArgs.WrapMessage(fmt.Sprintf("bad format %s, expected <device-path>:<rate>", s))
+	}
+	device := ThrottleDevice{Path: path}
+	if bytes {
+		n, err := subsystem.SizeToBytes(rate)
+		if err != nil || n <= 0 {
+			return ThrottleDevice{}, constant.ErrMalformedArgs.WrapMessage(fmt.Sprintf("invalid rate %s", rate))
+		}
+		device.Rate = uint64(n)
+	} else {
+		n, err := strconv.ParseUint(rate, 10, 64)
+		if err != nil || n == 0 {
+			return ThrottleDevice{}, constant.ErrMalformedArgs.WrapMessage(fmt.Sprintf("invalid rate %s", rate))
+		}
+		device.Rate = n
+	}
+	return device, nil
+}
+
+// ParseThrottleDevices parses every value of a throttle flag, see ParseThrottleDevice.
+func ParseThrottleDevices(values []string, bytes bool) ([]ThrottleDevice, error) {
+	devices := make([]ThrottleDevice, 0, len(values))
+	for _, v := range values {
+		device, err := ParseThrottleDevice(v, bytes)
+		if err != nil {
+			return nil, err
+		}
+		devices = append(devices, device)
+	}
+	return devices, nil
+}
+
+// ValidateBlkioWeight accepts 0, which keeps the default weight, or a weight from 10 to 1000.
+func ValidateBlkioWeight(weight uint16) error {
+	if weight != 0 && (weight < MinBlkioWeight || weight > MaxBlkioWeight) {
+		return constant.ErrMalformedArgs.WrapMessage(fmt.Sprintf("blkio weight %d is out of range [10, 1000]", weight))
+	}
+	return nil
+}
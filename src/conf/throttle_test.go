@@ -0,0 +1,33 @@
+package conf
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/0x822a5b87/tiny-docker/src/constant"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseThrottleDevice(t *testing.T) {
+	device, err := ParseThrottleDevice("/dev/sda:10mb", true)
+	assert.NoError(t, err)
+	assert.Equal(t, ThrottleDevice{Path: "/dev/sda", Rate: 10 * 1024 * 1024}, device)
+
+	device, err = ParseThrottleDevice("/dev/sda:1000", false)
+	assert.NoError(t, err)
+	assert.Equal(t, ThrottleDevice{Path: "/dev/sda", Rate: 1000}, device)
+
+	for _, s := range []string{"/dev/sda", "sda:10mb", "/dev/sda:0", "/dev/sda:fast"} {
+		_, err = ParseThrottleDevice(s, true)
+		assert.True(t, errors.Is(err, constant.ErrMalformedArgs), s)
+	}
+	_, err = ParseThrottleDevice("/dev/sda:10mb", false)
+	assert.True(t, errors.Is(err, constant.ErrMalformedArgs))
+}
+
+func TestValidateBlkioWeight(t *testing.T) {
+	assert.NoError(t, ValidateBlkioWeight(0))
+	assert.NoError(t, ValidateBlkioWeight(500))
+	assert.Error(t, ValidateBlkioWeight(5))
+	assert.Error(t, ValidateBlkioWeight(1001))
+}
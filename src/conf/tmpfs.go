@@ -0,0 +1,111 @@
+package conf
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/0x822a5b87/tiny-docker/src/constant"
+)
+
+// TmpfsFlagOptions are the options of a tmpfs which are mount flags, the rest of the options
+// are passed to tmpfs itself.
+var TmpfsFlagOptions = []string{"ro", "rw", "exec", "noexec", "suid", "nosuid", "dev", "nodev", "noatime"}
+
+var (
+	// tmpfsDataOptions are the options tmpfs takes, see tmpfs(5).
+	tmpfsDataOptions = []string{"size", "mode", "uid", "gid", "nr_inodes", "nr_blocks"}
+	// tmpfsSize is a number with a unit like 64m, or a share of the memory like 50%.
+	tmpfsSize = regexp.MustCompile(`^[0-9]+[kKmMgG%]?$`)
+)
+
+// Tmpfs is a tmpfs given with --tmpfs, which is mounted at Destination in the container.
+type Tmpfs struct {
+	Destination string
+	// Options are like those of mount(8), noexec, nosuid and nodev unless they are overridden
+	// like with docker.
+	Options []string
+}
+
+// ParseTmpfs parses the value of --tmpfs: /container/path[:options], the options are separated
+// by commas like size=64m,mode=1777.
+func ParseTmpfs(spec string) (Tmpfs, error) {
+	destination, options, _ := strings.Cut(spec, ":")
+	if !filepath.IsAbs(destination) || filepath.Clean(destination) == "/" {
+		return Tmpfs{}, constant.ErrInvalidVolume.WrapMessage(fmt.Sprintf("%s is not an absolute container path other than /", destination))
+	}
+	t := Tmpfs{Destination: filepath.Clean(destination), Options: []string{"noexec", "nosuid", "nodev"}}
+	if options == "" {
+		return t, nil
+	}
+	for _, option := range strings.Split(options, ",") {
+		if err := validateTmpfsOption(option); err != nil {
+			return Tmpfs{}, constant.ErrInvalidVolume.WrapMessage(fmt.Sprintf("%s of tmpfs %s", err.Error(), spec))
+		}
+		t.Options = append(t.Options, option)
+	}
+	return t, nil
+}
+
+// ParseTmpfsList parses the values of --tmpfs, a path of the container can be the destination of
+// a single tmpfs or volume.
+func ParseTmpfsList(specs []string, mounts []Mount) ([]Tmpfs, error) {
+	tmpfs := make([]Tmpfs, 0, len(specs))
+	destinations := make(map[string]struct{}, len(specs)+len(mounts))
+	for _, m := range mounts {
+		destinations[m.Destination] = struct{}{}
+	}
+	for _, spec := range specs {
+		t, err := ParseTmpfs(spec)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := destinations[t.Destination]; ok {
+			return nil, constant.ErrInvalidVolume.WrapMessage(fmt.Sprintf("duplicate mount point %s", t.Destination))
+		}
+		destinations[t.Destination] = struct{}{}
+		tmpfs = append(tmpfs, t)
+	}
+	return tmpfs, nil
+}
+
+// TmpfsFromEnv returns the tmpfs the container process mounts, they are passed to it by the
+// launcher in the environment.
+func TmpfsFromEnv() ([]Tmpfs, error) {
+	value := TmpfsMounts.Get()
+	if value == "" {
+		return nil, nil
+	}
+	var tmpfs []Tmpfs
+	err := json.Unmarshal([]byte(value), &tmpfs)
+	return tmpfs, err
+}
+
+func validateTmpfsOption(option string) error {
+	if slices.Contains(TmpfsFlagOptions, option) {
+		return nil
+	}
+	key, value, _ := strings.Cut(option, "=")
+	if !slices.Contains(tmpfsDataOptions, key) || value == "" {
+		return fmt.Errorf("unknown option %s", option)
+	}
+	switch key {
+	case "mode":
+		if mode, err := strconv.ParseUint(value, 8, 32); err != nil || mode > 07777 {
+			return fmt.Errorf("invalid mode %s", value)
+		}
+	case "uid", "gid":
+		if _, err := strconv.ParseUint(value, 10, 32); err != nil {
+			return fmt.Errorf("invalid %s %s", key, value)
+		}
+	default:
+		if !tmpfsSize.MatchString(value) {
+			return fmt.Errorf("invalid %s %s", key, value)
+		}
+	}
+	return nil
+}
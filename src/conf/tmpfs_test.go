@@ -0,0 +1,49 @@
+package conf
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/0x822a5b87/tiny-docker/src/constant"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTmpfs(t *testing.T) {
+	valid := map[string]Tmpfs{
+		"/run":                      {Destination: "/run", Options: []string{"noexec", "nosuid", "nodev"}},
+		"/run/:size=64m,mode=1777":  {Destination: "/run", Options: []string{"noexec", "nosuid", "nodev", "size=64m", "mode=1777"}},
+		"/tmp:exec,size=50%,uid=10": {Destination: "/tmp", Options: []string{"noexec", "nosuid", "nodev", "exec", "size=50%", "uid=10"}},
+		"/cache:ro,nr_inodes=1k":    {Destination: "/cache", Options: []string{"noexec", "nosuid", "nodev", "ro", "nr_inodes=1k"}},
+	}
+	for spec, expected := range valid {
+		tmpfs, err := ParseTmpfs(spec)
+		assert.NoError(t, err, spec)
+		assert.Equal(t, expected, tmpfs, spec)
+	}
+
+	invalid := []string{
+		"run",
+		"/",
+		"/run:size",
+		"/run:size=64x",
+		"/run:mode=999",
+		"/run:mode=17777",
+		"/run:uid=-1",
+		"/run:bind",
+	}
+	for _, spec := range invalid {
+		_, err := ParseTmpfs(spec)
+		assert.True(t, errors.Is(err, constant.ErrInvalidVolume), spec)
+	}
+}
+
+func TestParseTmpfsList(t *testing.T) {
+	tmpfs, err := ParseTmpfsList([]string{"/run", "/tmp:size=1g"}, []Mount{{Source: "/srv", Destination: "/srv"}})
+	assert.NoError(t, err)
+	assert.Len(t, tmpfs, 2)
+
+	_, err = ParseTmpfsList([]string{"/run", "/run/"}, nil)
+	assert.True(t, errors.Is(err, constant.ErrInvalidVolume))
+	_, err = ParseTmpfsList([]string{"/srv"}, []Mount{{Source: "/srv", Destination: "/srv"}})
+	assert.True(t, errors.Is(err, constant.ErrInvalidVolume))
+}
@@ -0,0 +1,130 @@
+package conf
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/0x822a5b87/tiny-docker/src/constant"
+)
+
+// Propagation decides whether the mounts made below a volume later on show up on the other side,
+// see mount_namespaces(7). The r variants apply to the mounts below the volume as well.
+type Propagation string
+
+const (
+	PropagationPrivate  Propagation = "private"
+	PropagationRPrivate Propagation = "rprivate"
+	PropagationShared   Propagation = "shared"
+	PropagationRShared  Propagation = "rshared"
+	PropagationSlave    Propagation = "slave"
+	PropagationRSlave   Propagation = "rslave"
+)
+
+// validVolumeName is the rule of docker for the names of volumes.
+var validVolumeName = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]+$`)
+
+// Mount is a volume given with -v, Source on the host is bind mounted at Destination in the
+// container. The fields are named like those of docker.
+type Mount struct {
+	Source string
+	// Name is the named volume mounted instead of a host path, its Source is resolved when the
+	// container starts.
+	Name string
+	// Anonymous is set for -v /container/path, whose volume gets a random Name.
+	Anonymous   bool
+	Destination string
+	ReadOnly    bool
+	// Propagation is rprivate unless it is given, like with docker.
+	Propagation Propagation
+}
+
+// IsValidVolumeName follows the rule of docker: [a-zA-Z0-9][a-zA-Z0-9_.-]+
+func IsValidVolumeName(name string) bool {
+	return validVolumeName.MatchString(name)
+}
+
+// ParseVolume parses the value of -v: /host/path:/container/path[:options], the options are
+// separated by commas, ro or rw and one propagation. A name instead of /host/path mounts a named
+// volume, and a lone /container/path an anonymous one.
+func ParseVolume(spec string) (Mount, error) {
+	parts := strings.Split(spec, ":")
+	if len(parts) > 3 {
+		return Mount{}, constant.ErrInvalidVolume.WrapMessage(fmt.Sprintf("%s is not like /host/path:/container/path[:ro]", spec))
+	}
+	m := Mount{Propagation: PropagationRPrivate}
+	if len(parts) == 1 {
+		// an anonymous volume is a named volume whose name nobody chose
+		name, _ := GenUUID()
+		m.Anonymous, parts = true, []string{name, parts[0]}
+	}
+	m.Destination = parts[1]
+	switch {
+	case filepath.IsAbs(parts[0]):
+		m.Source = filepath.Clean(parts[0])
+	case IsValidVolumeName(parts[0]):
+		m.Name = parts[0]
+	default:
+		return Mount{}, constant.ErrInvalidVolume.WrapMessage(fmt.Sprintf("%s is neither an absolute host path nor a volume name", parts[0]))
+	}
+	if !filepath.IsAbs(m.Destination) || filepath.Clean(m.Destination) == "/" {
+		return Mount{}, constant.ErrInvalidVolume.WrapMessage(fmt.Sprintf("%s is not an absolute container path other than /", m.Destination))
+	}
+	m.Destination = filepath.Clean(m.Destination)
+	if len(parts) == 2 {
+		return m, nil
+	}
+
+	var mode, propagation bool
+	for _, option := range strings.Split(parts[2], ",") {
+		switch p := Propagation(option); {
+		case option == "ro" || option == "rw":
+			if mode {
+				return Mount{}, constant.ErrInvalidVolume.WrapMessage(fmt.Sprintf("%s has more than one of ro and rw", spec))
+			}
+			mode, m.ReadOnly = true, option == "ro"
+		case p == PropagationPrivate || p == PropagationRPrivate || p == PropagationShared ||
+			p == PropagationRShared || p == PropagationSlave || p == PropagationRSlave:
+			if propagation {
+				return Mount{}, constant.ErrInvalidVolume.WrapMessage(fmt.Sprintf("%s has more than one propagation", spec))
+			}
+			propagation, m.Propagation = true, p
+		default:
+			return Mount{}, constant.ErrInvalidVolume.WrapMessage(fmt.Sprintf("unknown option %s of %s", option, spec))
+		}
+	}
+	return m, nil
+}
+
+// ParseVolumes parses the values of -v, a path of the container can be the destination of a
+// single volume.
+func ParseVolumes(specs []string) ([]Mount, error) {
+	mounts := make([]Mount, 0, len(specs))
+	destinations := make(map[string]struct{}, len(specs))
+	for _, spec := range specs {
+		m, err := ParseVolume(spec)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := destinations[m.Destination]; ok {
+			return nil, constant.ErrInvalidVolume.WrapMessage(fmt.Sprintf("duplicate mount point %s", m.Destination))
+		}
+		destinations[m.Destination] = struct{}{}
+		mounts = append(mounts, m)
+	}
+	return mounts, nil
+}
+
+// MountsFromEnv returns the volumes the container process mounts, they are passed to it by
+// the launcher in the environment.
+func MountsFromEnv() ([]Mount, error) {
+	value := Mounts.Get()
+	if value == "" {
+		return nil, nil
+	}
+	var mounts []Mount
+	err := json.Unmarshal([]byte(value), &mounts)
+	return mounts, err
+}
@@ -0,0 +1,60 @@
+package conf
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/0x822a5b87/tiny-docker/src/constant"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseVolume(t *testing.T) {
+	valid := map[string]Mount{
+		"/srv/data:/data":               {Source: "/srv/data", Destination: "/data", Propagation: PropagationRPrivate},
+		"/srv/data/:/data/:ro":          {Source: "/srv/data", Destination: "/data", ReadOnly: true, Propagation: PropagationRPrivate},
+		"/srv/data:/data:rw,rslave":     {Source: "/srv/data", Destination: "/data", Propagation: PropagationRSlave},
+		"/srv/data:/data:shared,ro":     {Source: "/srv/data", Destination: "/data", ReadOnly: true, Propagation: PropagationShared},
+		"/etc/hosts:/etc/hosts:private": {Source: "/etc/hosts", Destination: "/etc/hosts", Propagation: PropagationPrivate},
+		"data:/data":                    {Name: "data", Destination: "/data", Propagation: PropagationRPrivate},
+		"my-vol.1:/data:ro":             {Name: "my-vol.1", Destination: "/data", ReadOnly: true, Propagation: PropagationRPrivate},
+	}
+	for spec, expected := range valid {
+		m, err := ParseVolume(spec)
+		assert.NoError(t, err, spec)
+		assert.Equal(t, expected, m, spec)
+	}
+
+	m, err := ParseVolume("/data/")
+	assert.NoError(t, err)
+	assert.True(t, m.Anonymous)
+	assert.True(t, IsValidVolumeName(m.Name))
+	assert.Equal(t, "/data", m.Destination)
+	assert.Empty(t, m.Source)
+
+	invalid := []string{
+		"data",
+		"/",
+		"./data:/data",
+		"-data:/data",
+		"d:/data",
+		"/srv/data:data",
+		"/srv/data:/",
+		"/srv/data:/data:ro,rw",
+		"/srv/data:/data:rshared,rslave",
+		"/srv/data:/data:z",
+		"/srv/data:/data:ro:extra",
+	}
+	for _, spec := range invalid {
+		_, err := ParseVolume(spec)
+		assert.True(t, errors.Is(err, constant.ErrInvalidVolume), spec)
+	}
+}
+
+func TestParseVolumes(t *testing.T) {
+	mounts, err := ParseVolumes([]string{"/a:/a", "/b:/b:ro"})
+	assert.NoError(t, err)
+	assert.Len(t, mounts, 2)
+
+	_, err = ParseVolumes([]string{"/a:/data", "/b:/data/"})
+	assert.True(t, errors.Is(err, constant.ErrInvalidVolume))
+}
@@ -9,16 +9,38 @@ func (action Action) String() string {
 const Daemon Action = "daemon"
 const InitDaemon Action = "init_daemon"
 const InitContainer Action = "init_container"
+const InitLaunch Action = "init_launch"
 const Run Action = "run"
 const Stop Action = "stop"
 const Ps Action = "ps"
 const Commit Action = "commit"
 const Logs Action = "logs"
 const Exec Action = "exec"
+const Create Action = "create"
+const Start Action = "start"
+const Inspect Action = "inspect"
+const Wait Action = "wait"
+const Rm Action = "rm"
+const Restart Action = "restart"
+const Attach Action = "attach"
+const Kill Action = "kill"
+const Pause Action = "pause"
+const Unpause Action = "unpause"
+const Update Action = "update"
+const Stats Action = "stats"
+const Events Action = "events"
+const ImageList Action = "images"
 const Network Action = "network"
-const NetworkCreate Action = "create"
-const NetworkConnect Action = "connect"
-const NetworkRm Action = "rm"
-const NetworkInspect Action = "inspect"
+const NetworkCreate Action = "network_create"
+const NetworkConnect Action = "network_connect"
+const NetworkRm Action = "network_rm"
+const NetworkInspect Action = "network_inspect"
+const NetworkList Action = "network_ls"
+const Volume Action = "volume"
+const VolumeCreate Action = "volume_create"
+const VolumeRm Action = "volume_rm"
+const VolumeInspect Action = "volume_inspect"
+const VolumeList Action = "volume_ls"
+const VolumePrune Action = "volume_prune"
 
-const Wait Action = "__wait_request__"
+const Exit Action = "__exit_request__"
@@ -13,12 +13,31 @@ func (e Err) Error() string {
 	return fmt.Sprintf("code = [%d], error = [%s]", e.ErrorCode, e.ErrorText)
 }
 
+// Wrap fills the error text with wrapErr, the result matches both e and wrapErr with errors.Is.
 func (e Err) Wrap(wrapErr error) error {
-	return fmt.Errorf(e.Error(), wrapErr.Error())
+	return &wrappedErr{err: e, cause: wrapErr, msg: fmt.Sprintf(e.Error(), wrapErr.Error())}
 }
 
+// WrapMessage fills the error text with msg, the result still matches e with errors.Is.
 func (e Err) WrapMessage(msg string) error {
-	return fmt.Errorf(e.Error(), msg)
+	return &wrappedErr{err: e, msg: fmt.Sprintf(e.Error(), msg)}
+}
+
+type wrappedErr struct {
+	err   Err
+	cause error
+	msg   string
+}
+
+func (w *wrappedErr) Error() string {
+	return w.msg
+}
+
+func (w *wrappedErr) Unwrap() []error {
+	if w.cause == nil {
+		return []error{w.err}
+	}
+	return []error{w.err, w.cause}
 }
 
 var (
@@ -47,4 +66,24 @@ var (
 	ErrDeviceIsBusy                 = Err{ErrorCode: 100022, ErrorText: "device is busy"}
 	ErrInvalidGateway               = Err{ErrorCode: 100023, ErrorText: "gateway IP is already used by other device"}
 	ErrInvalidIp                    = Err{ErrorCode: 100024, ErrorText: "invalid IP"}
+	ErrProtocolVersion              = Err{ErrorCode: 100025, ErrorText: "Unsupported protocol version: %v"}
+	ErrFrameTooLarge                = Err{ErrorCode: 100026, ErrorText: "frame exceeds the maximum size"}
+	ErrIllegalContainerStatus       = Err{ErrorCode: 100027, ErrorText: "Illegal container status: %v"}
+	ErrContainerNotFound            = Err{ErrorCode: 100028, ErrorText: "No such container: %v"}
+	ErrContainerNameInUse           = Err{ErrorCode: 100029, ErrorText: "Container name is already in use: %v"}
+	ErrAmbiguousContainer           = Err{ErrorCode: 100030, ErrorText: "Multiple containers match: %v"}
+	ErrInvalidContainerName         = Err{ErrorCode: 100031, ErrorText: "Invalid container name: %v"}
+	ErrInvalidRestartPolicy         = Err{ErrorCode: 100032, ErrorText: "Invalid restart policy: %v"}
+	ErrConflictingOptions           = Err{ErrorCode: 100033, ErrorText: "Conflicting options: %v"}
+	ErrInvalidSignal                = Err{ErrorCode: 100034, ErrorText: "Invalid signal: %v"}
+	ErrCgroupEventTimeout           = Err{ErrorCode: 100035, ErrorText: "Timed out waiting for the events of cgroup: %v"}
+	ErrInvalidCgroupParent          = Err{ErrorCode: 100036, ErrorText: "Invalid cgroup parent: %v"}
+	ErrInvalidCgroupDriver          = Err{ErrorCode: 100037, ErrorText: "Invalid cgroup driver: %v"}
+	ErrInvalidVolume                = Err{ErrorCode: 100038, ErrorText: "Invalid volume: %v"}
+	ErrMountVolume                  = Err{ErrorCode: 100039, ErrorText: "Mount volume error: %v"}
+	ErrVolumeNotFound               = Err{ErrorCode: 100040, ErrorText: "No such volume: %v"}
+	ErrVolumeExists                 = Err{ErrorCode: 100041, ErrorText: "Volume already exists: %v"}
+	ErrVolumeInUse                  = Err{ErrorCode: 100042, ErrorText: "Volume is in use: %v"}
+	ErrInvalidDevice                = Err{ErrorCode: 100043, ErrorText: "Invalid device: %v"}
+	ErrSetupDev                     = Err{ErrorCode: 100044, ErrorText: "Setup /dev error: %v"}
 )
@@ -1,11 +1,40 @@
 package constant
 
 const (
-	CgroupBasePath       = "/sys/fs/cgroup/system.slice"
-	CgroupServiceName    = "tiny-docker.service"
+	CgroupRootPath       = "/sys/fs/cgroup"
 	CgroupSubtreeControl = "cgroup.subtree_control"
+	CgroupControllers    = "cgroup.controllers"
+	// DefaultCgroupParent is where the cgroupfs driver creates the cgroups of the containers,
+	// DefaultCgroupSlice is the slice the systemd driver puts their scopes in.
+	DefaultCgroupParent = "system.slice/tiny-docker.service"
+	DefaultCgroupSlice  = "system.slice"
+	// CgroupScopePrefix names the scope of a container with the systemd driver, like docker does.
+	CgroupScopePrefix = "tiny-docker-"
 
-	CgroupProcs = "cgroup.procs"
-	CpuMax      = "cpu.max"
-	MemoryMax   = "memory.max"
+	CgroupProcs    = "cgroup.procs"
+	CgroupFreeze   = "cgroup.freeze"
+	CpuMax         = "cpu.max"
+	CpuWeight      = "cpu.weight"
+	CpusetCpus     = "cpuset.cpus"
+	CpusetMems     = "cpuset.mems"
+	MemoryMax      = "memory.max"
+	MemoryHigh     = "memory.high"
+	MemoryLow      = "memory.low"
+	MemoryMin      = "memory.min"
+	MemorySwapMax  = "memory.swap.max"
+	MemoryOomGroup = "memory.oom.group"
+	PidsMax        = "pids.max"
+	IoMax          = "io.max"
+	IoWeight       = "io.weight"
+
+	CgroupEvents  = "cgroup.events"
+	MemoryEvents  = "memory.events"
+	PidsCurrent   = "pids.current"
+	CpuStat       = "cpu.stat"
+	MemoryCurrent = "memory.current"
+	MemoryStat    = "memory.stat"
+	IoStat        = "io.stat"
+
+	CpusetCpusEffective = "cpuset.cpus.effective"
+	CpusetMemsEffective = "cpuset.mems.effective"
 )
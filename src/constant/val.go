@@ -1,3 +1,10 @@
 package constant
 
 const LiteralMax = "max"
+
+// DefaultStopTimeout is the number of seconds a container gets to exit after SIGTERM before it is
+// killed, when no timeout is given.
+const DefaultStopTimeout = 10
+
+// DefaultStopSignal asks a container to exit when it has no stop signal of its own.
+const DefaultStopSignal = "SIGTERM"
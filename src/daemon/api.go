@@ -0,0 +1,658 @@
+package daemon
+
+import (
+	"cmp"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/0x822a5b87/tiny-docker/src/conf"
+	"github.com/0x822a5b87/tiny-docker/src/constant"
+	"github.com/0x822a5b87/tiny-docker/src/entity"
+	"github.com/0x822a5b87/tiny-docker/src/handler"
+	"github.com/0x822a5b87/tiny-docker/src/util"
+)
+
+// The routes below implement the subset of the Docker Engine API that tiny-docker supports.
+// Every route is translated into an action and dispatched through the action registry.
+
+const apiVersion = "1.43"
+
+type apiContainer struct {
+	Id      string   `json:"Id"`
+	Names   []string `json:"Names"`
+	Image   string   `json:"Image"`
+	Command string   `json:"Command"`
+	Created int64    `json:"Created"`
+	State   string   `json:"State"`
+	Status  string   `json:"Status"`
+}
+
+type apiHostConfig struct {
+	AutoRemove        bool               `json:"AutoRemove"`
+	RestartPolicy     conf.RestartPolicy `json:"RestartPolicy"`
+	Memory            int64              `json:"Memory"`
+	MemorySwap        int64              `json:"MemorySwap"`
+	MemoryReservation int64              `json:"MemoryReservation"`
+	CpuQuota          int64              `json:"CpuQuota"`
+	CpuPeriod         int64              `json:"CpuPeriod"`
+	NanoCpus          int64              `json:"NanoCpus"`
+	CpuShares         int64              `json:"CpuShares"`
+	CpusetCpus        string             `json:"CpusetCpus"`
+	CpusetMems        string             `json:"CpusetMems"`
+	PidsLimit         int64              `json:"PidsLimit"`
+	CgroupParent      string             `json:"CgroupParent"`
+	Binds             []string           `json:"Binds"`
+	Tmpfs             map[string]string  `json:"Tmpfs"`
+	ReadonlyRootfs    bool               `json:"ReadonlyRootfs"`
+	Devices           []apiDevice        `json:"Devices"`
+	ShmSize           int64              `json:"ShmSize"`
+
+	BlkioWeight          uint16                `json:"BlkioWeight"`
+	BlkioDeviceReadBps   []conf.ThrottleDevice `json:"BlkioDeviceReadBps"`
+	BlkioDeviceWriteBps  []conf.ThrottleDevice `json:"BlkioDeviceWriteBps"`
+	BlkioDeviceReadIOps  []conf.ThrottleDevice `json:"BlkioDeviceReadIOps"`
+	BlkioDeviceWriteIOps []conf.ThrottleDevice `json:"BlkioDeviceWriteIOps"`
+}
+
+type apiDevice struct {
+	PathOnHost        string `json:"PathOnHost"`
+	PathInContainer   string `json:"PathInContainer"`
+	CgroupPermissions string `json:"CgroupPermissions"`
+}
+
+type apiContainerCreate struct {
+	Image      string        `json:"Image"`
+	Cmd        []string      `json:"Cmd"`
+	Entrypoint []string      `json:"Entrypoint"`
+	Env        []string      `json:"Env"`
+	StopSignal string        `json:"StopSignal"`
+	HostConfig apiHostConfig `json:"HostConfig"`
+}
+
+type apiContainerUpdate struct {
+	Memory            int64  `json:"Memory"`
+	MemorySwap        int64  `json:"MemorySwap"`
+	MemoryReservation int64  `json:"MemoryReservation"`
+	NanoCpus          int64  `json:"NanoCpus"`
+	CpuShares         int64  `json:"CpuShares"`
+	CpusetCpus        string `json:"CpusetCpus"`
+	CpusetMems        string `json:"CpusetMems"`
+	PidsLimit         *int64 `json:"PidsLimit"`
+}
+
+type apiNetworkIpamConfig struct {
+	Subnet  string `json:"Subnet"`
+	Gateway string `json:"Gateway"`
+}
+
+type apiNetworkIpam struct {
+	Driver string                 `json:"Driver"`
+	Config []apiNetworkIpamConfig `json:"Config"`
+}
+
+type apiNetwork struct {
+	Name   string         `json:"Name"`
+	Id     string         `json:"Id"`
+	Scope  string         `json:"Scope"`
+	Driver string         `json:"Driver"`
+	IPAM   apiNetworkIpam `json:"IPAM"`
+}
+
+type apiNetworkCreate struct {
+	Name   string `json:"Name"`
+	Driver string `json:"Driver"`
+}
+
+type apiVolume struct {
+	Name       string            `json:"Name"`
+	Driver     string            `json:"Driver"`
+	Mountpoint string            `json:"Mountpoint"`
+	CreatedAt  string            `json:"CreatedAt"`
+	Scope      string            `json:"Scope"`
+	Labels     map[string]string `json:"Labels"`
+	Options    map[string]string `json:"Options"`
+}
+
+type apiVolumeCreate struct {
+	Name       string            `json:"Name"`
+	Driver     string            `json:"Driver"`
+	DriverOpts map[string]string `json:"DriverOpts"`
+}
+
+type apiImage struct {
+	Id       string   `json:"Id"`
+	RepoTags []string `json:"RepoTags"`
+	Created  int64    `json:"Created"`
+	Size     int64    `json:"Size"`
+}
+
+func addAllRoutes() {
+	handler.AddRoute("GET /_ping", apiPing)
+	handler.AddRoute("GET /version", apiVersionInfo)
+
+	handler.AddRoute("GET /containers/json", apiContainerList)
+	handler.AddRoute("POST /containers/create", apiContainerCreateRoute)
+	handler.AddRoute("POST /containers/{id}/start", apiContainerStart)
+	handler.AddRoute("POST /containers/{id}/stop", apiContainerStop)
+	handler.AddRoute("POST /containers/{id}/kill", apiContainerKill)
+	handler.AddRoute("POST /containers/{id}/pause", apiContainerPause)
+	handler.AddRoute("POST /containers/{id}/update", apiContainerUpdateRoute)
+	handler.AddRoute("POST /containers/{id}/unpause", apiContainerUnpause)
+	handler.AddRoute("POST /containers/{id}/restart", apiContainerRestart)
+	handler.AddRoute("GET /containers/{id}/logs", apiContainerLogs)
+	handler.AddRoute("POST /containers/{id}/wait", apiContainerWait)
+	handler.AddRoute("DELETE /containers/{id}", apiContainerRm)
+
+	handler.AddRoute("GET /networks", apiNetworkList)
+	handler.AddRoute("POST /networks/create", apiNetworkCreateRoute)
+	handler.AddRoute("GET /networks/{id}", apiNetworkInspect)
+	handler.AddRoute("DELETE /networks/{id}", apiNetworkRm)
+
+	handler.AddRoute("GET /volumes", apiVolumeList)
+	handler.AddRoute("POST /volumes/create", apiVolumeCreateRoute)
+	handler.AddRoute("POST /volumes/prune", apiVolumePrune)
+	handler.AddRoute("GET /volumes/{name}", apiVolumeInspect)
+	handler.AddRoute("DELETE /volumes/{name}", apiVolumeRm)
+
+	handler.AddRoute("GET /images/json", apiImageList)
+}
+
+func apiPing(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Api-Version", apiVersion)
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = w.Write([]byte("OK"))
+}
+
+func apiVersionInfo(w http.ResponseWriter, r *http.Request) {
+	handler.WriteJSON(w, http.StatusOK, map[string]string{
+		"ApiVersion":    apiVersion,
+		"MinAPIVersion": apiVersion,
+		"Os":            "linux",
+	})
+}
+
+func apiContainerList(w http.ResponseWriter, r *http.Request) {
+	all := isTrueQuery(r, "all")
+	containers, err := handler.Dispatch[conf.PsCommand, []entity.Container](constant.Ps, conf.PsCommand{All: all})
+	if err != nil {
+		handler.WriteError(w, err)
+		return
+	}
+	result := make([]apiContainer, 0, len(containers))
+	for _, c := range containers {
+		result = append(result, toApiContainer(c))
+	}
+	handler.WriteJSON(w, http.StatusOK, result)
+}
+
+func apiContainerCreateRoute(w http.ResponseWriter, r *http.Request) {
+	var body apiContainerCreate
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		apiBadRequest(w, err.Error())
+		return
+	}
+	if body.Image == "" {
+		apiBadRequest(w, "image is required")
+		return
+	}
+
+	args := make([]string, 0, len(body.Entrypoint)+len(body.Cmd))
+	args = append(args, body.Entrypoint...)
+	args = append(args, body.Cmd...)
+	cfg, err := toCgroupConfig(body.HostConfig)
+	if err != nil {
+		apiBadRequest(w, err.Error())
+		return
+	}
+	mounts, err := conf.ParseVolumes(body.HostConfig.Binds)
+	if err != nil {
+		apiBadRequest(w, err.Error())
+		return
+	}
+	tmpfsSpecs := make([]string, 0, len(body.HostConfig.Tmpfs))
+	for destination, options := range body.HostConfig.Tmpfs {
+		tmpfsSpecs = append(tmpfsSpecs, strings.TrimSuffix(destination+":"+options, ":"))
+	}
+	tmpfs, err := conf.ParseTmpfsList(tmpfsSpecs, mounts)
+	if err != nil {
+		apiBadRequest(w, err.Error())
+		return
+	}
+	deviceSpecs := make([]string, 0, len(body.HostConfig.Devices))
+	for _, d := range body.HostConfig.Devices {
+		deviceSpecs = append(deviceSpecs, d.PathOnHost+":"+cmp.Or(d.PathInContainer, d.PathOnHost)+":"+cmp.Or(d.CgroupPermissions, "rwm"))
+	}
+	devices, err := conf.ParseDevices(deviceSpecs)
+	if err != nil {
+		apiBadRequest(w, err.Error())
+		return
+	}
+	if body.HostConfig.ShmSize < 0 {
+		apiBadRequest(w, "ShmSize must not be negative")
+		return
+	}
+	spec := conf.RunCommands{
+		Name:       r.URL.Query().Get("name"),
+		Detach:     true,
+		Image:      body.Image,
+		Args:       args,
+		UserEnv:    body.Env,
+		Cfg:        cfg,
+		Mounts:     mounts,
+		Tmpfs:      tmpfs,
+		ReadOnly:   body.HostConfig.ReadonlyRootfs,
+		Devices:    devices,
+		ShmSize:    body.HostConfig.ShmSize,
+		AutoRemove: body.HostConfig.AutoRemove,
+		StopSignal: body.StopSignal,
+	}
+	policy, err := conf.ParseRestartPolicy(string(body.HostConfig.RestartPolicy.Name))
+	if err != nil {
+		apiBadRequest(w, err.Error())
+		return
+	}
+	if policy.Name == conf.RestartOnFailure {
+		policy.MaximumRetryCount = body.HostConfig.RestartPolicy.MaximumRetryCount
+	}
+	spec.RestartPolicy = policy
+	c, err := handler.Dispatch[conf.RunCommands, entity.Container](constant.Create, spec)
+	if err != nil {
+		handler.WriteError(w, err)
+		return
+	}
+	handler.WriteJSON(w, http.StatusCreated, map[string]any{"Id": c.Id, "Warnings": []string{}})
+}
+
+func apiContainerStart(w http.ResponseWriter, r *http.Request) {
+	c := entity.Container{Id: entity.ContainerId(r.PathValue("id"))}
+	if _, err := handler.Dispatch[entity.Container, string](constant.Start, c); err != nil {
+		handler.WriteError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func apiContainerStop(w http.ResponseWriter, r *http.Request) {
+	command := conf.StopCommand{Id: entity.ContainerId(r.PathValue("id")), Timeout: constant.DefaultStopTimeout}
+	if t := r.URL.Query().Get("t"); t != "" {
+		timeout, err := strconv.Atoi(t)
+		if err != nil {
+			apiBadRequest(w, err.Error())
+			return
+		}
+		command.Timeout = timeout
+	}
+	if _, err := handler.Dispatch[conf.StopCommand, string](constant.Stop, command); err != nil {
+		handler.WriteError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func apiContainerPause(w http.ResponseWriter, r *http.Request) {
+	c := entity.Container{Id: entity.ContainerId(r.PathValue("id"))}
+	if _, err := handler.Dispatch[entity.Container, string](constant.Pause, c); err != nil {
+		handler.WriteError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func apiContainerUnpause(w http.ResponseWriter, r *http.Request) {
+	c := entity.Container{Id: entity.ContainerId(r.PathValue("id"))}
+	if _, err := handler.Dispatch[entity.Container, string](constant.Unpause, c); err != nil {
+		handler.WriteError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func apiContainerUpdateRoute(w http.ResponseWriter, r *http.Request) {
+	var body apiContainerUpdate
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		apiBadRequest(w, err.Error())
+		return
+	}
+	command := conf.UpdateCommand{Id: entity.ContainerId(r.PathValue("id"))}
+	if body.Memory > 0 {
+		command.Memory = strconv.FormatInt(body.Memory, 10)
+	}
+	command.MemorySwap = apiMemorySwap(body.MemorySwap)
+	if body.MemoryReservation > 0 {
+		command.MemoryReservation = strconv.FormatInt(body.MemoryReservation, 10)
+	}
+	if body.NanoCpus > 0 {
+		command.Cpus = strconv.FormatFloat(float64(body.NanoCpus)/1e9, 'f', -1, 64)
+	}
+	if body.CpuShares > 0 {
+		command.CpuShares = strconv.FormatInt(body.CpuShares, 10)
+	}
+	command.CpusetCpus = body.CpusetCpus
+	command.CpusetMems = body.CpusetMems
+	if body.PidsLimit != nil {
+		command.PidsLimit = strconv.FormatInt(*body.PidsLimit, 10)
+	}
+	if _, err := handler.Dispatch[conf.UpdateCommand, string](constant.Update, command); err != nil {
+		handler.WriteError(w, err)
+		return
+	}
+	handler.WriteJSON(w, http.StatusOK, map[string]any{"Warnings": []string{}})
+}
+
+func apiContainerKill(w http.ResponseWriter, r *http.Request) {
+	command := conf.KillCommand{Id: entity.ContainerId(r.PathValue("id")), Signal: r.URL.Query().Get("signal")}
+	if _, err := handler.Dispatch[conf.KillCommand, string](constant.Kill, command); err != nil {
+		handler.WriteError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func apiContainerRestart(w http.ResponseWriter, r *http.Request) {
+	command := conf.RestartCommand{Id: entity.ContainerId(r.PathValue("id")), Timeout: constant.DefaultStopTimeout}
+	if t := r.URL.Query().Get("t"); t != "" {
+		timeout, err := strconv.Atoi(t)
+		if err != nil {
+			apiBadRequest(w, err.Error())
+			return
+		}
+		command.Timeout = timeout
+	}
+	if _, err := handler.Dispatch[conf.RestartCommand, string](constant.Restart, command); err != nil {
+		handler.WriteError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func apiContainerLogs(w http.ResponseWriter, r *http.Request) {
+	c := entity.Container{Id: entity.ContainerId(r.PathValue("id"))}
+	data, err := handler.Dispatch[entity.Container, string](constant.Logs, c)
+	if err != nil {
+		handler.WriteError(w, err)
+		return
+	}
+	// containers without a tty are served with the multiplexed stream format, stdout and
+	// stderr share a single log file so everything is reported as stdout.
+	header := make([]byte, 8)
+	header[0] = 1
+	binary.BigEndian.PutUint32(header[4:], uint32(len(data)))
+	w.Header().Set("Content-Type", "application/vnd.docker.multiplexed-stream")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(header)
+	_, _ = w.Write([]byte(data))
+}
+
+func apiContainerWait(w http.ResponseWriter, r *http.Request) {
+	c, err := waitContainer(r.Context(), entity.ContainerId(r.PathValue("id")))
+	if err != nil {
+		if r.Context().Err() == nil {
+			handler.WriteError(w, err)
+		}
+		return
+	}
+	handler.WriteJSON(w, http.StatusOK, map[string]any{"StatusCode": c.ExitCode})
+}
+
+func apiContainerRm(w http.ResponseWriter, r *http.Request) {
+	command := conf.RmCommand{
+		Id:      entity.ContainerId(r.PathValue("id")),
+		Force:   isTrueQuery(r, "force"),
+		Volumes: isTrueQuery(r, "v"),
+	}
+	if _, err := handler.Dispatch[conf.RmCommand, string](constant.Rm, command); err != nil {
+		handler.WriteError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func apiNetworkList(w http.ResponseWriter, r *http.Request) {
+	networkList, err := handler.Dispatch[struct{}, []*entity.Network](constant.NetworkList, struct{}{})
+	if err != nil {
+		handler.WriteError(w, err)
+		return
+	}
+	result := make([]apiNetwork, 0, len(networkList))
+	for _, n := range networkList {
+		result = append(result, toApiNetwork(n))
+	}
+	handler.WriteJSON(w, http.StatusOK, result)
+}
+
+func apiNetworkCreateRoute(w http.ResponseWriter, r *http.Request) {
+	var body apiNetworkCreate
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		apiBadRequest(w, err.Error())
+		return
+	}
+	if body.Driver != "" && body.Driver != string(entity.NetworkBridge) {
+		apiBadRequest(w, "only bridge driver is supported")
+		return
+	}
+	n, err := handler.Dispatch[entity.Network, entity.Network](constant.NetworkCreate, entity.Network{Name: body.Name})
+	if err != nil {
+		handler.WriteError(w, err)
+		return
+	}
+	handler.WriteJSON(w, http.StatusCreated, map[string]string{"Id": string(n.Id), "Warning": ""})
+}
+
+func apiNetworkInspect(w http.ResponseWriter, r *http.Request) {
+	n, err := handler.Dispatch[entity.Network, entity.Network](constant.NetworkInspect, entity.Network{Name: r.PathValue("id")})
+	if err != nil {
+		handler.WriteError(w, err)
+		return
+	}
+	handler.WriteJSON(w, http.StatusOK, toApiNetwork(&n))
+}
+
+func apiNetworkRm(w http.ResponseWriter, r *http.Request) {
+	if _, err := handler.Dispatch[entity.Network, entity.Network](constant.NetworkRm, entity.Network{Name: r.PathValue("id")}); err != nil {
+		handler.WriteError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func apiVolumeList(w http.ResponseWriter, r *http.Request) {
+	volumeList, err := handler.Dispatch[struct{}, []*entity.Volume](constant.VolumeList, struct{}{})
+	if err != nil {
+		handler.WriteError(w, err)
+		return
+	}
+	result := make([]apiVolume, 0, len(volumeList))
+	for _, v := range volumeList {
+		result = append(result, toApiVolume(v))
+	}
+	handler.WriteJSON(w, http.StatusOK, map[string]any{"Volumes": result, "Warnings": []string{}})
+}
+
+func apiVolumeCreateRoute(w http.ResponseWriter, r *http.Request) {
+	var body apiVolumeCreate
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		apiBadRequest(w, err.Error())
+		return
+	}
+	command := conf.VolumeCommand{Name: body.Name, Driver: body.Driver, Options: body.DriverOpts}
+	v, err := handler.Dispatch[conf.VolumeCommand, entity.Volume](constant.VolumeCreate, command)
+	if err != nil {
+		handler.WriteError(w, err)
+		return
+	}
+	handler.WriteJSON(w, http.StatusCreated, toApiVolume(&v))
+}
+
+func apiVolumeInspect(w http.ResponseWriter, r *http.Request) {
+	v, err := handler.Dispatch[conf.VolumeCommand, entity.Volume](constant.VolumeInspect, conf.VolumeCommand{Name: r.PathValue("name")})
+	if err != nil {
+		handler.WriteError(w, err)
+		return
+	}
+	handler.WriteJSON(w, http.StatusOK, toApiVolume(&v))
+}
+
+func apiVolumeRm(w http.ResponseWriter, r *http.Request) {
+	if _, err := handler.Dispatch[conf.VolumeCommand, entity.Volume](constant.VolumeRm, conf.VolumeCommand{Name: r.PathValue("name")}); err != nil {
+		handler.WriteError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func apiVolumePrune(w http.ResponseWriter, r *http.Request) {
+	// docker asks for the named volumes with the filter all=true
+	var filters map[string][]string
+	if value := r.URL.Query().Get("filters"); value != "" {
+		if err := json.Unmarshal([]byte(value), &filters); err != nil {
+			apiBadRequest(w, err.Error())
+			return
+		}
+	}
+	all := slices.ContainsFunc(filters["all"], func(v string) bool { return v == "1" || v == "true" })
+	pruned, err := handler.Dispatch[conf.VolumeCommand, []*entity.Volume](constant.VolumePrune, conf.VolumeCommand{All: all})
+	if err != nil {
+		handler.WriteError(w, err)
+		return
+	}
+	deleted := make([]string, 0, len(pruned))
+	for _, v := range pruned {
+		deleted = append(deleted, v.Name)
+	}
+	handler.WriteJSON(w, http.StatusOK, map[string]any{"VolumesDeleted": deleted, "SpaceReclaimed": 0})
+}
+
+func apiImageList(w http.ResponseWriter, r *http.Request) {
+	images, err := handler.Dispatch[struct{}, []entity.Image](constant.ImageList, struct{}{})
+	if err != nil {
+		handler.WriteError(w, err)
+		return
+	}
+	result := make([]apiImage, 0, len(images))
+	for _, image := range images {
+		result = append(result, apiImage{
+			Id:       image.Name,
+			RepoTags: []string{image.Name + ":latest"},
+			Created:  image.CreatedAt / 1000,
+			Size:     image.Size,
+		})
+	}
+	handler.WriteJSON(w, http.StatusOK, result)
+}
+
+func toApiContainer(c entity.Container) apiContainer {
+	state := string(c.Status)
+	if c.Status == entity.ContainerExit {
+		state = "exited"
+	}
+	return apiContainer{
+		Id:      string(c.Id),
+		Names:   []string{"/" + c.Name},
+		Image:   c.Image,
+		Command: c.Command,
+		Created: c.CreatedAt / 1000,
+		State:   state,
+		Status:  util.FormatContainerStatus(c),
+	}
+}
+
+func toApiVolume(v *entity.Volume) apiVolume {
+	return apiVolume{
+		Name:       v.Name,
+		Driver:     v.Driver,
+		Mountpoint: v.Mountpoint,
+		CreatedAt:  time.UnixMilli(v.CreatedAt).Format(time.RFC3339),
+		Scope:      "local",
+		Labels:     map[string]string{},
+		Options:    v.Options,
+	}
+}
+
+func toApiNetwork(n *entity.Network) apiNetwork {
+	ipam := apiNetworkIpam{Driver: "default", Config: make([]apiNetworkIpamConfig, 0)}
+	if n.IPNet != nil {
+		config := apiNetworkIpamConfig{Subnet: n.IPNet.String()}
+		if n.Gateway != nil {
+			config.Gateway = n.Gateway.IP.String()
+		}
+		ipam.Config = append(ipam.Config, config)
+	}
+	return apiNetwork{
+		Name:   n.Name,
+		Id:     string(n.Id),
+		Scope:  "local",
+		Driver: string(n.Type),
+		IPAM:   ipam,
+	}
+}
+
+func toCgroupConfig(hostConfig apiHostConfig) (conf.CgroupConfig, error) {
+	cfg := conf.CgroupConfig{}
+	if hostConfig.Memory > 0 {
+		cfg.MemoryLimit = strconv.FormatInt(hostConfig.Memory, 10)
+	}
+	cfg.MemorySwap = apiMemorySwap(hostConfig.MemorySwap)
+	if hostConfig.MemoryReservation > 0 {
+		cfg.MemoryReservation = strconv.FormatInt(hostConfig.MemoryReservation, 10)
+	}
+	if hostConfig.CpuQuota > 0 && hostConfig.NanoCpus > 0 {
+		return cfg, constant.ErrConflictingOptions.WrapMessage("CpuQuota and NanoCpus")
+	}
+	if hostConfig.CpuQuota > 0 {
+		period := hostConfig.CpuPeriod
+		if period <= 0 {
+			period = constant.CpuPeriod
+		}
+		cfg.CpuMax = fmt.Sprintf("%d %d", hostConfig.CpuQuota, period)
+	}
+	if hostConfig.NanoCpus > 0 {
+		cpuMax, err := conf.ParseCpus(strconv.FormatFloat(float64(hostConfig.NanoCpus)/1e9, 'f', -1, 64))
+		if err != nil {
+			return cfg, err
+		}
+		cfg.CpuMax = cpuMax
+	}
+	weight, err := conf.CpuSharesToWeight(hostConfig.CpuShares)
+	if err != nil {
+		return cfg, err
+	}
+	cfg.CpuWeight = weight
+	cfg.CpusetCpus = hostConfig.CpusetCpus
+	cfg.CpusetMems = hostConfig.CpusetMems
+	cfg.PidsLimit = hostConfig.PidsLimit
+	cfg.CgroupParent = hostConfig.CgroupParent
+	cfg.BlkioWeight = hostConfig.BlkioWeight
+	cfg.DeviceReadBps = hostConfig.BlkioDeviceReadBps
+	cfg.DeviceWriteBps = hostConfig.BlkioDeviceWriteBps
+	cfg.DeviceReadIOps = hostConfig.BlkioDeviceReadIOps
+	cfg.DeviceWriteIOps = hostConfig.BlkioDeviceWriteIOps
+	return cfg, nil
+}
+
+// apiMemorySwap converts the MemorySwap of the Engine API, where 0 leaves it unset and -1 means
+// unlimited swap.
+func apiMemorySwap(swap int64) string {
+	switch {
+	case swap == -1:
+		return conf.MemorySwapUnlimited
+	case swap > 0:
+		return strconv.FormatInt(swap, 10)
+	default:
+		return ""
+	}
+}
+
+func apiBadRequest(w http.ResponseWriter, msg string) {
+	handler.WriteJSON(w, http.StatusBadRequest, map[string]string{"message": msg})
+}
+
+func isTrueQuery(r *http.Request, key string) bool {
+	v := r.URL.Query().Get(key)
+	return v == "1" || v == "true" || v == "True"
+}
@@ -0,0 +1,103 @@
+package daemon
+
+import (
+	"cmp"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/0x822a5b87/tiny-docker/src/conf"
+	"github.com/0x822a5b87/tiny-docker/src/constant"
+	"github.com/0x822a5b87/tiny-docker/src/entity"
+	"github.com/0x822a5b87/tiny-docker/src/subsystem/manager"
+	"github.com/0x822a5b87/tiny-docker/src/systemd"
+	"github.com/0x822a5b87/tiny-docker/src/util"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// scopeTimeout bounds the wait for systemd to move the launcher into the scope of a container.
+	scopeTimeout = 10 * time.Second
+	// scopePollInterval is how often the scope is checked for the launcher.
+	scopePollInterval = 10 * time.Millisecond
+	// scopeStartRetries bounds the starts of a scope while the scope of a previous run is stopping.
+	scopeStartRetries = 50
+)
+
+// containerCgroup returns the cgroup of a container, a container created before the cgroup was
+// kept in its state is in the default parent of the cgroupfs driver.
+func containerCgroup(c *entity.Container) string {
+	return cmp.Or(c.Cgroup, defaultContainerCgroup(c.Id))
+}
+
+func defaultContainerCgroup(id entity.ContainerId) string {
+	return filepath.Join(constant.DefaultCgroupParent, string(id))
+}
+
+// createCgroup prepares the cgroup of a container before the limits are written to it. The
+// cgroupfs driver creates the cgroup along with the limits and only needs the controllers enabled
+// in its parent, of which those of cfg are required. The systemd driver asks systemd for a
+// transient scope with pid in it.
+func createCgroup(id entity.ContainerId, cgroup string, pid int, cfg conf.CgroupConfig) error {
+	unit, ok := conf.CgroupScope(cgroup)
+	if !ok {
+		if util.DetectCgroupMode() != util.CgroupUnified {
+			// the hierarchies of cgroup v1 have their controllers everywhere
+			return nil
+		}
+		return util.PrepareCgroupParent(filepath.Dir(cgroup), cfg.Controllers())
+	}
+
+	bus, err := systemd.Dial(systemd.SystemBusAddress())
+	if err != nil {
+		logrus.Errorf("error connect to systemd: %v", err)
+		return err
+	}
+	defer func() { _ = bus.Close() }()
+	slice := filepath.Base(filepath.Dir(cgroup))
+	if slice == "." {
+		slice = "-.slice"
+	}
+	description := fmt.Sprintf("tiny-docker container %s", id)
+	for i := 0; ; i++ {
+		err = bus.StartTransientScope(unit, slice, description, pid)
+		if !systemd.IsError(err, systemd.ErrUnitExists) || i == scopeStartRetries {
+			break
+		}
+		// the scope of a previous run is left behind until systemd stopped it
+		if err = bus.StopUnit(unit); err != nil {
+			break
+		}
+		time.Sleep(scopePollInterval)
+	}
+	if err != nil {
+		logrus.Errorf("error start scope {%s}: %v", unit, err)
+		return err
+	}
+
+	// systemd moves pid once the job of the scope ran
+	deadline := time.Now().Add(scopeTimeout)
+	for !manager.ContainsProcess(cgroup, pid) {
+		if time.Now().After(deadline) {
+			return fmt.Errorf("systemd did not move %d into scope %s within %s", pid, unit, scopeTimeout)
+		}
+		time.Sleep(scopePollInterval)
+	}
+	logrus.Infof("Created scope {%s} for container {%s}", unit, id)
+	return nil
+}
+
+// stopScope stops the scope of a container created by the systemd driver, systemd removes its
+// cgroup along with it.
+func stopScope(cgroup string) error {
+	unit, ok := conf.CgroupScope(cgroup)
+	if !ok {
+		return nil
+	}
+	bus, err := systemd.Dial(systemd.SystemBusAddress())
+	if err != nil {
+		return err
+	}
+	defer func() { _ = bus.Close() }()
+	return bus.StopUnit(unit)
+}
@@ -51,18 +51,29 @@ func handleContainerRun(request handler.Request) (handler.Response, error) {
 }
 
 func handleContainerStop(request handler.Request) (handler.Response, error) {
-	containers, err := handler.ParamsFromRequest[[]entity.Container](&request)
+	command, err := handler.ParamsFromRequest[conf.StopCommand](&request)
 	if err != nil {
 		logrus.Errorf("error parse container stop request: %s", err.Error())
 		return handler.ErrorMessageResponse("error parse container stop request", constant.ErrMalformedUdsReq)
 	}
-	err = stopContainers(containers)
-	if err != nil {
+	if err = stopContainerByRef(command); err != nil {
 		return handler.ErrorResponse(err, constant.ErrMalformedUdsRsp)
 	}
 	return handler.SuccessResponse("{}")
 }
 
+func handleContainerKill(request handler.Request) (handler.Response, error) {
+	command, err := handler.ParamsFromRequest[conf.KillCommand](&request)
+	if err != nil {
+		logrus.Errorf("error parse container kill request: %s", err.Error())
+		return handler.ErrorMessageResponse("error parse container kill request", constant.ErrMalformedUdsReq)
+	}
+	if err = signalContainer(command); err != nil {
+		return handler.ErrorResponse(err, constant.ErrExecCommand)
+	}
+	return handler.SuccessResponse("{}")
+}
+
 func handleContainerLogs(request handler.Request) (handler.Response, error) {
 	container, err := handler.ParamsFromRequest[entity.Container](&request)
 	if err != nil {
@@ -77,16 +88,110 @@ func handleContainerLogs(request handler.Request) (handler.Response, error) {
 	return handler.SuccessResponse(data)
 }
 
-func handleWaitContainer(request handler.Request) (handler.Response, error) {
-	waitReq, err := handler.ParamsFromRequest[entity.WaitRequest](&request)
+func handleContainerRm(request handler.Request) (handler.Response, error) {
+	command, err := handler.ParamsFromRequest[conf.RmCommand](&request)
 	if err != nil {
-		logrus.Errorf("error parse wait container request: %s", err.Error())
-		return handler.ErrorMessageResponse("error parse container stop request", constant.ErrMalformedUdsReq)
+		logrus.Errorf("error parse container rm request: %s", err.Error())
+		return handler.ErrorMessageResponse("error parse container rm request", constant.ErrMalformedUdsReq)
+	}
+	if err = removeContainer(command); err != nil {
+		return handler.ErrorResponse(err, constant.ErrExecCommand)
 	}
-	go wait(waitReq)
 	return handler.SuccessResponse("{}")
 }
 
+func handleContainerUpdate(request handler.Request) (handler.Response, error) {
+	command, err := handler.ParamsFromRequest[conf.UpdateCommand](&request)
+	if err != nil {
+		logrus.Errorf("error parse container update request: %s", err.Error())
+		return handler.ErrorMessageResponse("error parse container update request", constant.ErrMalformedUdsReq)
+	}
+	if err = updateContainer(command); err != nil {
+		return handler.ErrorResponse(err, constant.ErrExecCommand)
+	}
+	return handler.SuccessResponse("{}")
+}
+
+func handleContainerRestart(request handler.Request) (handler.Response, error) {
+	command, err := handler.ParamsFromRequest[conf.RestartCommand](&request)
+	if err != nil {
+		logrus.Errorf("error parse container restart request: %s", err.Error())
+		return handler.ErrorMessageResponse("error parse container restart request", constant.ErrMalformedUdsReq)
+	}
+	if err = restartContainer(command); err != nil {
+		return handler.ErrorResponse(err, constant.ErrExecCommand)
+	}
+	return handler.SuccessResponse("{}")
+}
+
+// handleContainerAttach streams the output of a container until it exits, the last frame carries
+// the exited container.
+func handleContainerAttach(request handler.Request, stream *handler.Stream) (handler.Response, error) {
+	command, err := handler.ParamsFromRequest[conf.AttachCommand](&request)
+	if err != nil {
+		logrus.Errorf("error parse container attach request: %s", err.Error())
+		return handler.ErrorMessageResponse("error parse container attach request", constant.ErrMalformedUdsReq)
+	}
+
+	send := func(data string) error { return stream.Send(data) }
+	c, err := attachContainer(stream.Context(), command.Id, command.Start, send)
+	if err != nil {
+		return handler.ErrorResponse(err, constant.ErrExecCommand)
+	}
+	return handler.SuccessResponse(c)
+}
+
+// handleContainerStats streams samples of the usage of containers, the last frame carries the
+// last sample.
+func handleContainerStats(request handler.Request, stream *handler.Stream) (handler.Response, error) {
+	command, err := handler.ParamsFromRequest[conf.StatsCommand](&request)
+	if err != nil {
+		logrus.Errorf("error parse container stats request: %s", err.Error())
+		return handler.ErrorMessageResponse("error parse container stats request", constant.ErrMalformedUdsReq)
+	}
+
+	send := func(stats []entity.ContainerStats) error { return stream.Send(stats) }
+	stats, err := statsContainers(stream.Context(), command, send)
+	if err != nil {
+		return handler.ErrorResponse(err, constant.ErrExecCommand)
+	}
+	return handler.SuccessResponse(stats)
+}
+
+// handleEvents streams the events of containers until the client goes away.
+func handleEvents(request handler.Request, stream *handler.Stream) (handler.Response, error) {
+	send := func(event entity.Event) error { return stream.Send(event) }
+	err := streamEvents(stream.Context(), send)
+	return handler.ErrorResponse(err, constant.ErrExecCommand)
+}
+
+func handleContainerExit(request handler.Request) (handler.Response, error) {
+	exitReq, err := handler.ParamsFromRequest[entity.ExitRequest](&request)
+	if err != nil {
+		logrus.Errorf("error parse container exit request: %s", err.Error())
+		return handler.ErrorMessageResponse("error parse container exit request", constant.ErrMalformedUdsReq)
+	}
+	if err = exitContainer(exitReq); err != nil {
+		return handler.ErrorResponse(err, constant.ErrExecCommand)
+	}
+	return handler.SuccessResponse("{}")
+}
+
+// handleContainerWait is a stream handler, so the wait is abandoned once the client goes away.
+func handleContainerWait(request handler.Request, stream *handler.Stream) (handler.Response, error) {
+	container, err := handler.ParamsFromRequest[entity.Container](&request)
+	if err != nil {
+		logrus.Errorf("error parse container wait request: %s", err.Error())
+		return handler.ErrorMessageResponse("error parse container wait request", constant.ErrMalformedUdsReq)
+	}
+
+	c, err := waitContainer(stream.Context(), container.Id)
+	if err != nil {
+		return handler.ErrorResponse(err, constant.ErrExecCommand)
+	}
+	return handler.SuccessResponse(c)
+}
+
 func handleNetworkCreate(request handler.Request) (handler.Response, error) {
 	network, err := handler.ParamsFromRequest[entity.Network](&request)
 	if err != nil {
@@ -128,3 +233,148 @@ func handleNetworkInspect(request handler.Request) (handler.Response, error) {
 	}
 	return handler.SuccessResponse(n)
 }
+
+func handleContainerCreate(request handler.Request) (handler.Response, error) {
+	spec, err := handler.ParamsFromRequest[conf.RunCommands](&request)
+	if err != nil {
+		logrus.Errorf("error parse container create request: %s", err.Error())
+		return handler.ErrorMessageResponse("error parse container create request", constant.ErrMalformedUdsReq)
+	}
+
+	c, err := createContainer(spec)
+	if err != nil {
+		return handler.ErrorResponse(err, constant.ErrExecCommand)
+	}
+	return handler.SuccessResponse(c)
+}
+
+func handleContainerStart(request handler.Request) (handler.Response, error) {
+	container, err := handler.ParamsFromRequest[entity.Container](&request)
+	if err != nil {
+		logrus.Errorf("error parse container start request: %s", err.Error())
+		return handler.ErrorMessageResponse("error parse container start request", constant.ErrMalformedUdsReq)
+	}
+
+	if err = startContainer(container.Id); err != nil {
+		return handler.ErrorResponse(err, constant.ErrExecCommand)
+	}
+	return handler.SuccessResponse("{}")
+}
+
+func handleContainerPause(request handler.Request) (handler.Response, error) {
+	container, err := handler.ParamsFromRequest[entity.Container](&request)
+	if err != nil {
+		logrus.Errorf("error parse container pause request: %s", err.Error())
+		return handler.ErrorMessageResponse("error parse container pause request", constant.ErrMalformedUdsReq)
+	}
+	if err = pauseContainer(container.Id); err != nil {
+		return handler.ErrorResponse(err, constant.ErrExecCommand)
+	}
+	return handler.SuccessResponse("{}")
+}
+
+func handleContainerUnpause(request handler.Request) (handler.Response, error) {
+	container, err := handler.ParamsFromRequest[entity.Container](&request)
+	if err != nil {
+		logrus.Errorf("error parse container unpause request: %s", err.Error())
+		return handler.ErrorMessageResponse("error parse container unpause request", constant.ErrMalformedUdsReq)
+	}
+	if err = unpauseContainer(container.Id); err != nil {
+		return handler.ErrorResponse(err, constant.ErrExecCommand)
+	}
+	return handler.SuccessResponse("{}")
+}
+
+func handleContainerInspect(request handler.Request) (handler.Response, error) {
+	container, err := handler.ParamsFromRequest[entity.Container](&request)
+	if err != nil {
+		logrus.Errorf("error parse container inspect request: %s", err.Error())
+		return handler.ErrorMessageResponse("error parse container inspect request", constant.ErrMalformedUdsReq)
+	}
+
+	c, err := inspectContainer(container.Id)
+	if err != nil {
+		return handler.ErrorResponse(err, constant.ErrExecCommand)
+	}
+	return handler.SuccessResponse(c)
+}
+
+func handleImageList(request handler.Request) (handler.Response, error) {
+	images, err := imageList()
+	if err != nil {
+		return handler.ErrorResponse(err, constant.ErrExecCommand)
+	}
+	return handler.SuccessResponse(images)
+}
+
+func handleNetworkList(request handler.Request) (handler.Response, error) {
+	n, err := NetworkList()
+	if err != nil {
+		return handler.ErrorResponse(err, constant.ErrExecCommand)
+	}
+	return handler.SuccessResponse(n)
+}
+
+func handleVolumeCreate(request handler.Request) (handler.Response, error) {
+	command, err := handler.ParamsFromRequest[conf.VolumeCommand](&request)
+	if err != nil {
+		logrus.Errorf("error parse volume create request: %s", err.Error())
+		return handler.ErrorMessageResponse("error parse volume create request", constant.ErrMalformedUdsReq)
+	}
+
+	v, err := VolumeCreate(command)
+	if err != nil {
+		return handler.ErrorResponse(err, constant.ErrExecCommand)
+	}
+	return handler.SuccessResponse(v)
+}
+
+func handleVolumeRm(request handler.Request) (handler.Response, error) {
+	command, err := handler.ParamsFromRequest[conf.VolumeCommand](&request)
+	if err != nil {
+		logrus.Errorf("error parse volume rm request: %s", err.Error())
+		return handler.ErrorMessageResponse("error parse volume rm request", constant.ErrMalformedUdsReq)
+	}
+
+	v, err := VolumeRm(command.Name)
+	if err != nil {
+		return handler.ErrorResponse(err, constant.ErrExecCommand)
+	}
+	return handler.SuccessResponse(v)
+}
+
+func handleVolumeInspect(request handler.Request) (handler.Response, error) {
+	command, err := handler.ParamsFromRequest[conf.VolumeCommand](&request)
+	if err != nil {
+		logrus.Errorf("error parse volume inspect request: %s", err.Error())
+		return handler.ErrorMessageResponse("error parse volume inspect request", constant.ErrMalformedUdsReq)
+	}
+
+	v, err := VolumeInspect(command.Name)
+	if err != nil {
+		return handler.ErrorResponse(err, constant.ErrExecCommand)
+	}
+	return handler.SuccessResponse(v)
+}
+
+func handleVolumeList(request handler.Request) (handler.Response, error) {
+	v, err := VolumeList()
+	if err != nil {
+		return handler.ErrorResponse(err, constant.ErrExecCommand)
+	}
+	return handler.SuccessResponse(v)
+}
+
+func handleVolumePrune(request handler.Request) (handler.Response, error) {
+	command, err := handler.ParamsFromRequest[conf.VolumeCommand](&request)
+	if err != nil {
+		logrus.Errorf("error parse volume prune request: %s", err.Error())
+		return handler.ErrorMessageResponse("error parse volume prune request", constant.ErrMalformedUdsReq)
+	}
+
+	v, err := VolumePrune(command.All)
+	if err != nil {
+		return handler.ErrorResponse(err, constant.ErrExecCommand)
+	}
+	return handler.SuccessResponse(v)
+}
@@ -2,14 +2,21 @@ package daemon
 
 import (
 	"github.com/0x822a5b87/tiny-docker/src/conf"
+	"github.com/0x822a5b87/tiny-docker/src/entity"
 	"github.com/0x822a5b87/tiny-docker/src/util"
 	"github.com/sirupsen/logrus"
 )
 
 func Commit(cmd conf.CommitCommands) error {
-	logrus.Infof("Commit Commands: %s", cmd)
+	c, err := resolveContainer(entity.ContainerId(cmd.SrcName))
+	if err != nil {
+		return err
+	}
+	cmd.Id = c.Id
+	cmd.Image = c.Image
+	logrus.Infof("Commit Commands: %v", cmd)
 	conf.LoadCommitConfig(cmd)
-	if err := setupUnionFsFromEnv(); err != nil {
+	if err = setupUnionFsFromConfig(); err != nil {
 		logrus.Error("error setting up union fs", err)
 		return err
 	}
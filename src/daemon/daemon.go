@@ -8,11 +8,13 @@ import (
 	"github.com/0x822a5b87/tiny-docker/src/constant"
 	"github.com/0x822a5b87/tiny-docker/src/network"
 	"github.com/0x822a5b87/tiny-docker/src/util"
+	"github.com/0x822a5b87/tiny-docker/src/volume"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/sys/unix"
 )
 
 var networks *network.Networks
+var volumes *volume.Volumes
 
 func StartDockerd(debug bool) error {
 	initContext()
@@ -73,12 +75,14 @@ func initContext() {
 	ensureFile(conf.RuntimeDockerdLogFile.Get())
 
 	ensurePath(conf.RuntimeDockerdContainerStatus.Get())
+	ensurePath(conf.RuntimeDockerdContainerSpec.Get())
 	logrus.Infof("init dockerd uds file: {%s}", conf.RuntimeDockerdUdsFile.Get())
 	logrus.Infof("init dockerd uds pid file: {%s}", conf.RuntimeDockerdUdsPidFile.Get())
 	logrus.Infof("init dockerd log file: {%s}", conf.RuntimeDockerdLogFile.Get())
 	logrus.Infof("init dockerd container path: {%s}", conf.RuntimeDockerdContainerStatus.Get())
 
 	initNetwork()
+	initVolume()
 }
 
 func initNetwork() {
@@ -92,6 +96,17 @@ func initNetwork() {
 	logrus.Infof("init network successfully.")
 }
 
+func initVolume() {
+	conf.LoadBasicCommand()
+	var err error
+	volumes, err = volume.NewVolumes()
+	if err != nil {
+		logrus.Errorf("error creating volumes: %v", err)
+		panic(err)
+	}
+	logrus.Infof("init volume successfully.")
+}
+
 func ensureFile(path string) {
 	if err := util.EnsureFileExists(path); err != nil {
 		panic(err)
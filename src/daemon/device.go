@@ -0,0 +1,146 @@
+package daemon
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/0x822a5b87/tiny-docker/src/conf"
+	"github.com/0x822a5b87/tiny-docker/src/constant"
+	"github.com/0x822a5b87/tiny-docker/src/util"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+)
+
+// defaultDevices are the devices every container gets, like with docker.
+var defaultDevices = []struct {
+	path         string
+	major, minor uint32
+}{
+	{"/dev/null", 1, 3},
+	{"/dev/zero", 1, 5},
+	{"/dev/full", 1, 7},
+	{"/dev/random", 1, 8},
+	{"/dev/urandom", 1, 9},
+	{"/dev/tty", 5, 0},
+}
+
+// devSymlinks are the links of /dev to the files of the process and to the ptmx of devpts.
+var devSymlinks = []struct{ link, target string }{
+	{"/dev/fd", "/proc/self/fd"},
+	{"/dev/stdin", "/proc/self/fd/0"},
+	{"/dev/stdout", "/proc/self/fd/1"},
+	{"/dev/stderr", "/proc/self/fd/2"},
+	{"/dev/ptmx", "pts/ptmx"},
+}
+
+// setupDev populates /dev of the container below root, the merged directory of its overlay. It
+// runs before the container pivots into root, while the devices of the host can still be bound
+// where the container cannot create them. /dev is a tmpfs, so it is writable with --read-only.
+func setupDev(root string) error {
+	dev := filepath.Join(root, "dev")
+	if err := os.MkdirAll(dev, 0755); err != nil {
+		return constant.ErrSetupDev.Wrap(err)
+	}
+	if err := unix.Mount("tmpfs", dev, "tmpfs", unix.MS_NOSUID|unix.MS_STRICTATIME, "mode=755"); err != nil {
+		return constant.ErrSetupDev.WrapMessage(fmt.Sprintf("mount tmpfs at %s: %v", dev, err))
+	}
+	// the modes of the nodes and directories are not to be masked
+	defer unix.Umask(unix.Umask(0))
+
+	for _, d := range defaultDevices {
+		if err := createDevice(root, d.path, d.path, unix.S_IFCHR|0666, unix.Mkdev(d.major, d.minor)); err != nil {
+			return constant.ErrSetupDev.WrapMessage(fmt.Sprintf("%s: %v", d.path, err))
+		}
+	}
+	if err := mountDevPts(dev); err != nil {
+		return err
+	}
+	if err := mountShm(dev); err != nil {
+		return err
+	}
+	for _, s := range devSymlinks {
+		if err := os.Symlink(s.target, filepath.Join(root, s.link)); err != nil {
+			return constant.ErrSetupDev.Wrap(err)
+		}
+	}
+	return setupDevices(root)
+}
+
+// mountDevPts mounts a devpts of its own for the container, so that it neither sees nor takes the
+// ptys of the host.
+func mountDevPts(dev string) error {
+	pts := filepath.Join(dev, "pts")
+	if err := os.Mkdir(pts, 0755); err != nil {
+		return constant.ErrSetupDev.Wrap(err)
+	}
+	// gid 5 is the tty group, like with docker
+	data := "newinstance,ptmxmode=0666,mode=0620,gid=5"
+	if err := unix.Mount("devpts", pts, "devpts", unix.MS_NOSUID|unix.MS_NOEXEC, data); err != nil {
+		return constant.ErrSetupDev.WrapMessage(fmt.Sprintf("mount devpts at %s: %v", pts, err))
+	}
+	return nil
+}
+
+func mountShm(dev string) error {
+	shm := filepath.Join(dev, "shm")
+	if err := os.Mkdir(shm, 0777|os.ModeSticky); err != nil {
+		return constant.ErrSetupDev.Wrap(err)
+	}
+	size := cmp.Or(conf.ShmSize.Get(), strconv.FormatInt(conf.DefaultShmSize, 10))
+	flags := uintptr(unix.MS_NOSUID | unix.MS_NODEV | unix.MS_NOEXEC)
+	if err := unix.Mount("shm", shm, "tmpfs", flags, "mode=1777,size="+size); err != nil {
+		return constant.ErrSetupDev.WrapMessage(fmt.Sprintf("mount tmpfs at %s: %v", shm, err))
+	}
+	logrus.Infof("mount /dev/shm of size %s", size)
+	return nil
+}
+
+// setupDevices creates the devices of the host given with --device in the container.
+func setupDevices(root string) error {
+	devices, err := conf.DevicesFromEnv()
+	if err != nil {
+		return constant.ErrSetupDev.Wrap(err)
+	}
+	for _, d := range devices {
+		var stat unix.Stat_t
+		if err = unix.Stat(d.PathOnHost, &stat); err != nil {
+			return constant.ErrInvalidDevice.WrapMessage(fmt.Sprintf("%s: %v", d.PathOnHost, err))
+		}
+		if stat.Mode&unix.S_IFMT != unix.S_IFCHR && stat.Mode&unix.S_IFMT != unix.S_IFBLK {
+			return constant.ErrInvalidDevice.WrapMessage(fmt.Sprintf("%s is not a device", d.PathOnHost))
+		}
+		if err = createDevice(root, d.PathOnHost, d.PathInContainer, stat.Mode, stat.Rdev); err != nil {
+			return constant.ErrSetupDev.WrapMessage(fmt.Sprintf("%s at %s: %v", d.PathOnHost, d.PathInContainer, err))
+		}
+		logrus.Infof("create device %s at %s", d.PathOnHost, d.PathInContainer)
+	}
+	return nil
+}
+
+// createDevice creates a device node at path of the container, or bind mounts the device of the
+// host there when the container is not allowed to create nodes.
+func createDevice(root, hostPath, path string, mode uint32, rdev uint64) error {
+	target, err := util.SecureJoin(root, path)
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	// a device outside of /dev replaces what the image has there
+	if err = os.Remove(target); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	err = unix.Mknod(target, mode, int(rdev))
+	if !errors.Is(err, unix.EPERM) {
+		return err
+	}
+	if err = createMountPoint(target, false); err != nil {
+		return err
+	}
+	return unix.Mount(hostPath, target, "", unix.MS_BIND, "")
+}
@@ -0,0 +1,121 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/0x822a5b87/tiny-docker/src/constant"
+	"github.com/0x822a5b87/tiny-docker/src/entity"
+	"github.com/0x822a5b87/tiny-docker/src/subsystem/manager"
+	"github.com/0x822a5b87/tiny-docker/src/subsystem/memory"
+	"github.com/sirupsen/logrus"
+)
+
+// eventBuffer is the number of events a subscriber may lag behind, later events are dropped for it.
+const eventBuffer = 64
+
+var (
+	eventsMu         sync.Mutex
+	eventSubscribers = make(map[chan entity.Event]struct{})
+)
+
+// cgroupWatcher reports the OOM kills of running containers.
+var cgroupWatcher *manager.EventWatcher
+
+func startCgroupWatcher() error {
+	w, err := manager.NewEventWatcher(recordOom)
+	if err != nil {
+		logrus.Errorf("error create cgroup event watcher: %v", err)
+		return err
+	}
+	cgroupWatcher = w
+	return nil
+}
+
+// watchCgroup starts watching the cgroup of a running container, a failure leaves OOM kills to be
+// found when the container exits, which is how they are found with cgroup v1.
+func watchCgroup(id entity.ContainerId, cgroup string) {
+	err := cgroupWatcher.Watch(id, cgroup)
+	if errors.Is(err, constant.ErrUnsupportedAction) {
+		logrus.Debugf("Cgroup events of container {%s} are not watched: %v", id, err)
+		return
+	}
+	if err != nil {
+		logrus.Warnf("error watch cgroup events of container {%s}: %v", id, err)
+	}
+}
+
+// recordOom records that the OOM killer killed processes of a container and publishes an oom
+// event for it.
+func recordOom(id entity.ContainerId, events memory.EventsValue) {
+	mu.Lock()
+	p := getContainerStatusFilePath(id)
+	state, err := readContainerState(p)
+	if err != nil || events.OomKill <= state.OOMKillCount {
+		mu.Unlock()
+		return
+	}
+	state.OOMKilled = true
+	state.OOMKillCount = events.OomKill
+	err = writeContainerState(p, state)
+	mu.Unlock()
+	if err != nil {
+		logrus.Errorf("error record oom kill of container {%s}: %v", id, err)
+		return
+	}
+	logrus.Warnf("Container {%s} was OOM killed, %d processes so far", id, events.OomKill)
+	publishEvent(entity.EventOom, *state, map[string]string{"oomKillCount": strconv.FormatInt(events.OomKill, 10)})
+}
+
+// publishEvent hands an event to every subscriber, it never blocks.
+func publishEvent(action entity.EventAction, c entity.Container, attributes map[string]string) {
+	event := entity.Event{
+		Action:     action,
+		Id:         c.Id,
+		Name:       c.Name,
+		Time:       time.Now().UnixMilli(),
+		Attributes: attributes,
+	}
+	eventsMu.Lock()
+	defer eventsMu.Unlock()
+	for ch := range eventSubscribers {
+		select {
+		case ch <- event:
+		default:
+			logrus.Warnf("drop event %s of container {%s} for a slow subscriber", action, c.Id)
+		}
+	}
+}
+
+func subscribeEvents() chan entity.Event {
+	eventsMu.Lock()
+	defer eventsMu.Unlock()
+	ch := make(chan entity.Event, eventBuffer)
+	eventSubscribers[ch] = struct{}{}
+	return ch
+}
+
+func unsubscribeEvents(ch chan entity.Event) {
+	eventsMu.Lock()
+	defer eventsMu.Unlock()
+	delete(eventSubscribers, ch)
+}
+
+// streamEvents sends the events published from now on until ctx is cancelled.
+func streamEvents(ctx context.Context, send func(entity.Event) error) error {
+	ch := subscribeEvents()
+	defer unsubscribeEvents(ch)
+	for {
+		select {
+		case event := <-ch:
+			if err := send(event); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
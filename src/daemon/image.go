@@ -0,0 +1,58 @@
+package daemon
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/0x822a5b87/tiny-docker/src/conf"
+	"github.com/0x822a5b87/tiny-docker/src/entity"
+	"github.com/sirupsen/logrus"
+)
+
+// imageList lists the images which have been extracted into the read layer.
+func imageList() ([]entity.Image, error) {
+	root := conf.GlobalConfig.ImagesReadPath()
+	entries, err := os.ReadDir(root)
+	if os.IsNotExist(err) {
+		return make([]entity.Image, 0), nil
+	}
+	if err != nil {
+		logrus.Errorf("error read images : %v", err)
+		return nil, err
+	}
+
+	images := make([]entity.Image, 0, len(entries))
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			logrus.Warnf("skip image %s: %v", e.Name(), err)
+			continue
+		}
+		images = append(images, entity.Image{
+			Name:      e.Name(),
+			CreatedAt: info.ModTime().UnixMilli(),
+			Size:      dirSize(filepath.Join(root, e.Name())),
+		})
+	}
+	return images, nil
+}
+
+func dirSize(path string) int64 {
+	var size int64
+	_ = filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.Type().IsRegular() {
+			if info, e := d.Info(); e == nil {
+				size += info.Size()
+			}
+		}
+		return nil
+	})
+	return size
+}
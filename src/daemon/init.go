@@ -14,12 +14,34 @@ func addAllHandler() {
 	handler.AddHandler(constant.Commit, handleCommit)
 	handler.AddHandler(constant.Run, handleContainerRun)
 	handler.AddHandler(constant.Stop, handleContainerStop)
+	handler.AddHandler(constant.Kill, handleContainerKill)
+	handler.AddHandler(constant.Pause, handleContainerPause)
+	handler.AddHandler(constant.Unpause, handleContainerUnpause)
+	handler.AddHandler(constant.Update, handleContainerUpdate)
 	handler.AddHandler(constant.Logs, handleContainerLogs)
-	handler.AddHandler(constant.Wait, handleWaitContainer)
+	handler.AddHandler(constant.Exit, handleContainerExit)
+	handler.AddHandler(constant.Rm, handleContainerRm)
+	handler.AddHandler(constant.Restart, handleContainerRestart)
+	handler.AddHandler(constant.Create, handleContainerCreate)
+	handler.AddHandler(constant.Start, handleContainerStart)
+	handler.AddHandler(constant.Inspect, handleContainerInspect)
+	handler.AddHandler(constant.ImageList, handleImageList)
+	handler.AddStreamHandler(constant.Wait, handleContainerWait)
+	handler.AddStreamHandler(constant.Attach, handleContainerAttach)
+	handler.AddStreamHandler(constant.Stats, handleContainerStats)
+	handler.AddStreamHandler(constant.Events, handleEvents)
 
 	handler.AddHandler(constant.NetworkCreate, handleNetworkCreate)
 	handler.AddHandler(constant.NetworkRm, handleNetworkRm)
 	handler.AddHandler(constant.NetworkInspect, handleNetworkInspect)
 	handler.AddHandler(constant.NetworkConnect, handleNetworkCreate)
+	handler.AddHandler(constant.NetworkList, handleNetworkList)
 
+	handler.AddHandler(constant.VolumeCreate, handleVolumeCreate)
+	handler.AddHandler(constant.VolumeRm, handleVolumeRm)
+	handler.AddHandler(constant.VolumeInspect, handleVolumeInspect)
+	handler.AddHandler(constant.VolumeList, handleVolumeList)
+	handler.AddHandler(constant.VolumePrune, handleVolumePrune)
+
+	addAllRoutes()
 }
@@ -3,19 +3,34 @@ package daemon
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"syscall"
 
 	"github.com/0x822a5b87/tiny-docker/src/conf"
 	"github.com/0x822a5b87/tiny-docker/src/constant"
 	"github.com/0x822a5b87/tiny-docker/src/handler"
+	"github.com/0x822a5b87/tiny-docker/src/util"
 	"github.com/sirupsen/logrus"
 )
 
 func RunDaemon() error {
 	logrus.Info("Starting daemon process")
+	logrus.Infof("Using cgroup %s", util.DetectCgroupMode())
+	if err := conf.GlobalConfig.Cgroup.Validate(); err != nil {
+		logrus.Errorf("error cgroup config: %v", err)
+		return err
+	}
 	_ = setupDetachMode()
 	initContext()
-	return handler.CreateUdsServer()
+	initNameIndex()
+	if err := startSupervisor(); err != nil {
+		return err
+	}
+	if err := startCgroupWatcher(); err != nil {
+		return err
+	}
+	// containers are restored once they can register themselves again
+	return handler.CreateUdsServer(restoreContainers)
 }
 
 func setupDetachMode() error {
@@ -28,11 +43,43 @@ func setupDetachMode() error {
 	return err
 }
 
-func pivotRoot(root string) error {
-	if err := syscall.Mount("", "/", "", syscall.MS_REC|syscall.MS_PRIVATE, ""); err != nil {
+// makeRootPropagation keeps the mounts of the container from propagating to the host, while the
+// mounts of the host still propagate into the volumes which are slaves or shared. The root is an
+// rslave of the host, unless a volume is shared: like with docker the root is rshared then, so
+// that the volume stays a peer of its source on the host and the mounts made below it in the
+// container reach the host. The mount root is on is made private instead, so that the overlay and
+// the rest of the mounts of the container below root do not.
+func makeRootPropagation(root string) error {
+	mounts, err := conf.MountsFromEnv()
+	if err != nil {
+		return constant.ErrMountRootFS.Wrap(err)
+	}
+	shared := slices.ContainsFunc(mounts, func(m conf.Mount) bool {
+		return m.Propagation == conf.PropagationShared || m.Propagation == conf.PropagationRShared
+	})
+	if !shared {
+		if err = syscall.Mount("", "/", "", syscall.MS_REC|syscall.MS_SLAVE, ""); err != nil {
+			return constant.ErrMountRootFS.Wrap(err)
+		}
+		return nil
+	}
+	if err = syscall.Mount("", "/", "", syscall.MS_REC|syscall.MS_SHARED, ""); err != nil {
+		return constant.ErrMountRootFS.Wrap(err)
+	}
+	parent, err := util.MountPointOf(root)
+	if err != nil {
 		return constant.ErrMountRootFS.Wrap(err)
 	}
+	// pivot_root refuses a shared parent of the current root as well
+	for _, target := range []string{parent, "/"} {
+		if err = syscall.Mount("", target, "", syscall.MS_PRIVATE, ""); err != nil {
+			return constant.ErrMountRootFS.Wrap(err)
+		}
+	}
+	return nil
+}
 
+func pivotRoot(root string) error {
 	if err := syscall.Mount(root, root, "bind", syscall.MS_BIND|syscall.MS_REC, ""); err != nil {
 		return constant.ErrMountRootFS.Wrap(err)
 	}
@@ -56,6 +103,11 @@ func pivotRoot(root string) error {
 	}
 
 	pivotDir = filepath.Join("/", ".pivot_root")
+	// the old root holds the mounts of the host, which are peers of them with a shared root, the
+	// unmount must not propagate to the host
+	if err := syscall.Mount("", pivotDir, "", syscall.MS_REC|syscall.MS_SLAVE, ""); err != nil {
+		return constant.ErrMountRootFS.Wrap(err)
+	}
 	if err := syscall.Unmount(pivotDir, syscall.MNT_DETACH); err != nil {
 		return constant.ErrMountRootFS.Wrap(err)
 	}
@@ -82,5 +134,9 @@ func setupMount() error {
 		return err
 	}
 
-	return syscall.Mount("tmpfs", "/dev", "tmpfs", syscall.MS_NOSUID|syscall.MS_STRICTATIME, "mode=755")
+	if !conf.ReadOnlyRootfs.GetBoolean() {
+		return nil
+	}
+	// only the root itself turns read only, the mounts below it like the volumes keep their flags
+	return syscall.Mount("", "/", "", syscall.MS_BIND|syscall.MS_REMOUNT|syscall.MS_RDONLY, "")
 }
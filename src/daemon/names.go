@@ -0,0 +1,119 @@
+package daemon
+
+import (
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/0x822a5b87/tiny-docker/src/constant"
+	"github.com/0x822a5b87/tiny-docker/src/entity"
+	"github.com/0x822a5b87/tiny-docker/src/util"
+	"github.com/sirupsen/logrus"
+)
+
+// maxNameRetries bounds the attempts to generate an unused container name.
+const maxNameRetries = 10
+
+// nameIndex maps the names of all containers to their ids, it is rebuilt from the container
+// states when mini-dockerd starts.
+type nameIndex struct {
+	mu    sync.Mutex
+	names map[string]entity.ContainerId
+}
+
+var names = &nameIndex{names: make(map[string]entity.ContainerId)}
+
+func initNameIndex() {
+	containers, err := readAllContainers()
+	if err != nil {
+		logrus.Errorf("error load container names: %v", err)
+		return
+	}
+	names.mu.Lock()
+	defer names.mu.Unlock()
+	for _, c := range containers {
+		if c.Name != "" {
+			names.names[c.Name] = c.Id
+		}
+	}
+}
+
+// reserve assigns name to the container id, a random name is generated if name is empty.
+func (n *nameIndex) reserve(name string, id entity.ContainerId) (string, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if name != "" {
+		name = strings.TrimPrefix(name, "/")
+		if !util.IsValidContainerName(name) {
+			return "", constant.ErrInvalidContainerName.WrapMessage(name)
+		}
+		if owner, ok := n.names[name]; ok && owner != id {
+			return "", constant.ErrContainerNameInUse.WrapMessage(name)
+		}
+		n.names[name] = id
+		return name, nil
+	}
+
+	for i := 0; i < maxNameRetries; i++ {
+		name = util.GenerateContainerName(i)
+		if _, ok := n.names[name]; !ok {
+			n.names[name] = id
+			return name, nil
+		}
+	}
+	return "", constant.ErrContainerNameInUse.WrapMessage(name)
+}
+
+// release frees the name of a container which is removed.
+func (n *nameIndex) release(name string, id entity.ContainerId) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.names[name] == id {
+		delete(n.names, name)
+	}
+}
+
+func (n *nameIndex) lookup(name string) (entity.ContainerId, bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	id, ok := n.names[strings.TrimPrefix(name, "/")]
+	return id, ok
+}
+
+// resolveContainer finds a container by its full id, its name or a unique prefix of its id, in
+// this order, like docker does.
+func resolveContainer(ref entity.ContainerId) (*entity.Container, error) {
+	if ref == "" {
+		return nil, constant.ErrContainerNotFound.WrapMessage("empty reference")
+	}
+	// ids never contain these, which keeps a reference from escaping the state directory
+	if !strings.ContainsAny(string(ref), "/.") {
+		p := getContainerStatusFilePath(ref)
+		if _, err := os.Stat(p); err == nil {
+			return readContainerState(p)
+		}
+	}
+
+	if id, ok := names.lookup(string(ref)); ok {
+		return readContainerState(getContainerStatusFilePath(id))
+	}
+
+	entries, err := os.ReadDir(getContainerStatusFilePath(""))
+	if err != nil {
+		return nil, err
+	}
+	var matched []entity.ContainerId
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), string(ref)) {
+			matched = append(matched, entity.ContainerId(entry.Name()))
+		}
+	}
+	switch len(matched) {
+	case 0:
+		return nil, constant.ErrContainerNotFound.WrapMessage(string(ref))
+	case 1:
+		return readContainerState(getContainerStatusFilePath(matched[0]))
+	default:
+		return nil, constant.ErrAmbiguousContainer.WrapMessage(string(ref))
+	}
+}
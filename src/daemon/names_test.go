@@ -0,0 +1,95 @@
+package daemon
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/0x822a5b87/tiny-docker/src/conf"
+	"github.com/0x822a5b87/tiny-docker/src/constant"
+	"github.com/0x822a5b87/tiny-docker/src/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+// withTestContainers writes the states of containers into a temporary state directory and
+// indexes their names, in place of the ones of mini-dockerd.
+func withTestContainers(t *testing.T, containers ...entity.Container) {
+	t.Setenv(conf.RuntimeDockerdContainerStatus.String(), t.TempDir())
+	saved := names
+	names = &nameIndex{names: make(map[string]entity.ContainerId)}
+	t.Cleanup(func() { names = saved })
+	for i := range containers {
+		c := &containers[i]
+		assert.NoError(t, writeContainerState(getContainerStatusFilePath(c.Id), c))
+		if c.Name != "" {
+			_, err := names.reserve(c.Name, c.Id)
+			assert.NoError(t, err)
+		}
+	}
+}
+
+func TestResolveContainer(t *testing.T) {
+	withTestContainers(t,
+		entity.Container{Id: "abc123", Name: "web"},
+		entity.Container{Id: "abd456", Name: "abc123"},
+		entity.Container{Id: "web789", Name: "db"},
+	)
+
+	for ref, expected := range map[entity.ContainerId]entity.ContainerId{
+		// a full id comes before a name
+		"abc123": "abc123",
+		// a name comes before a prefix of an id
+		"web":  "abc123",
+		"/web": "abc123",
+		"db":   "web789",
+		"abd":  "abd456",
+		"we":   "web789",
+	} {
+		c, err := resolveContainer(ref)
+		assert.NoError(t, err, ref)
+		if assert.NotNil(t, c, ref) {
+			assert.Equal(t, expected, c.Id, ref)
+		}
+	}
+
+	_, err := resolveContainer("ab")
+	assert.True(t, errors.Is(err, constant.ErrAmbiguousContainer))
+	for _, ref := range []entity.ContainerId{"", "xyz", "../abc123", "abc123/"} {
+		_, err = resolveContainer(ref)
+		assert.True(t, errors.Is(err, constant.ErrContainerNotFound), ref)
+	}
+}
+
+func TestReserveName(t *testing.T) {
+	withTestContainers(t)
+
+	name, err := names.reserve("/web", "abc123")
+	assert.NoError(t, err)
+	assert.Equal(t, "web", name)
+	// a container may reserve its own name again
+	_, err = names.reserve("web", "abc123")
+	assert.NoError(t, err)
+	_, err = names.reserve("web", "def456")
+	assert.True(t, errors.Is(err, constant.ErrContainerNameInUse))
+
+	for _, name := range []string{"/", "w", "web/db", "web app", "-web"} {
+		_, err = names.reserve(name, "def456")
+		assert.True(t, errors.Is(err, constant.ErrInvalidContainerName), name)
+	}
+
+	// a generated name is free
+	name, err = names.reserve("", "def456")
+	assert.NoError(t, err)
+	id, ok := names.lookup(name)
+	assert.True(t, ok)
+	assert.Equal(t, entity.ContainerId("def456"), id)
+
+	// only the owner releases a name
+	names.release("web", "def456")
+	_, ok = names.lookup("web")
+	assert.True(t, ok)
+	names.release("web", "abc123")
+	_, ok = names.lookup("web")
+	assert.False(t, ok)
+	_, err = names.reserve("web", "def456")
+	assert.NoError(t, err)
+}
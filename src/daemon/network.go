@@ -16,7 +16,7 @@ func NetworkCreate(name string) (*entity.Network, error) {
 
 func NetworkRm(name string) (*entity.Network, error) {
 	conf.LoadBasicCommand()
-	network, err := networks.GetNetworkByName(name)
+	network, err := lookupNetwork(name)
 	if err != nil {
 		logrus.Error("network rm error: ", err)
 		return nil, err
@@ -27,5 +27,19 @@ func NetworkRm(name string) (*entity.Network, error) {
 
 func NetworkInspect(name string) (*entity.Network, error) {
 	conf.LoadBasicCommand()
-	return networks.GetNetworkByName(name)
+	return lookupNetwork(name)
+}
+
+func NetworkList() ([]*entity.Network, error) {
+	conf.LoadBasicCommand()
+	return networks.GetNetworks()
+}
+
+// lookupNetwork finds a network by its name or id.
+func lookupNetwork(ref string) (*entity.Network, error) {
+	network, err := networks.GetNetworkByName(ref)
+	if err == nil {
+		return network, nil
+	}
+	return networks.GetNetwork(entity.NetworkId(ref))
 }
@@ -0,0 +1,77 @@
+package daemon
+
+import (
+	"fmt"
+
+	"github.com/0x822a5b87/tiny-docker/src/constant"
+	"github.com/0x822a5b87/tiny-docker/src/entity"
+	"github.com/0x822a5b87/tiny-docker/src/subsystem/manager"
+	"github.com/sirupsen/logrus"
+)
+
+// pauseContainer freezes all processes of a running container with the cgroup freezer, it
+// returns once the kernel reports the cgroup frozen.
+func pauseContainer(ref entity.ContainerId) error {
+	c, err := resolveContainer(ref)
+	if err != nil {
+		return err
+	}
+	if c.Status != entity.ContainerRunning {
+		return constant.ErrIllegalContainerStatus.WrapMessage(fmt.Sprintf("container %s is %s, not running", c.Id, c.Status))
+	}
+	cgroupManager, err := manager.LoadCgroupManager(containerCgroup(c))
+	if err != nil {
+		return err
+	}
+	if err = cgroupManager.Freeze(); err != nil {
+		logrus.Errorf("error freeze container {%s}: %v", c.Id, err)
+		// do not leave the container half frozen
+		_ = cgroupManager.Thaw()
+		return err
+	}
+	setAliveStatus(c.Id, entity.ContainerPaused)
+	logrus.Infof("Pause container {%s}", c.Id)
+	return nil
+}
+
+// unpauseContainer resumes the processes of a paused container.
+func unpauseContainer(ref entity.ContainerId) error {
+	c, err := resolveContainer(ref)
+	if err != nil {
+		return err
+	}
+	if c.Status != entity.ContainerPaused {
+		return constant.ErrIllegalContainerStatus.WrapMessage(fmt.Sprintf("container %s is not paused", c.Id))
+	}
+	if err = thawContainer(c); err != nil {
+		return err
+	}
+	logrus.Infof("Unpause container {%s}", c.Id)
+	return nil
+}
+
+func thawContainer(c *entity.Container) error {
+	cgroupManager, err := manager.LoadCgroupManager(containerCgroup(c))
+	if err != nil {
+		return err
+	}
+	if err = cgroupManager.Thaw(); err != nil {
+		logrus.Errorf("error thaw container {%s}: %v", c.Id, err)
+		return err
+	}
+	setAliveStatus(c.Id, entity.ContainerRunning)
+	return nil
+}
+
+// setAliveStatus switches a container between running and paused, unless it exited meanwhile.
+func setAliveStatus(id entity.ContainerId, status entity.ContainerStatus) {
+	mu.Lock()
+	defer mu.Unlock()
+	p := getContainerStatusFilePath(id)
+	state, err := readContainerState(p)
+	if err != nil || !state.Status.Alive() {
+		return
+	}
+	state.Status = status
+	_ = writeContainerState(p, state)
+}
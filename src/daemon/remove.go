@@ -0,0 +1,145 @@
+package daemon
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/0x822a5b87/tiny-docker/src/conf"
+	"github.com/0x822a5b87/tiny-docker/src/constant"
+	"github.com/0x822a5b87/tiny-docker/src/entity"
+	"github.com/0x822a5b87/tiny-docker/src/subsystem/manager"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// a killed container may take a moment to leave its cgroup
+	cgroupRemoveRetries  = 40
+	cgroupRemoveInterval = 50 * time.Millisecond
+)
+
+// removeContainer deletes a container and everything it left behind: its overlay layers, cgroup,
+// network endpoint, logs, spec and state. Its references to volumes are dropped, and its
+// anonymous volumes removed if asked for. A running container is only removed with force.
+func removeContainer(command conf.RmCommand) error {
+	c, err := resolveContainer(command.Id)
+	if err != nil {
+		return err
+	}
+	if c.Status.Alive() || c.Status == entity.ContainerRestarting {
+		if !command.Force {
+			return constant.ErrIllegalContainerStatus.WrapMessage(
+				fmt.Sprintf("container %s is %s, stop it first or remove it with force", c.Id, c.Status))
+		}
+		if err = stopContainer(c.Id, 0); err != nil {
+			return err
+		}
+	}
+
+	var errs []error
+	if err = networks.Disconnect(c.Id); err != nil {
+		errs = append(errs, err)
+	}
+	if err = removeContainerCgroup(c); err != nil {
+		errs = append(errs, err)
+	}
+	if err = removeContainerFs(c.Id); err != nil {
+		errs = append(errs, err)
+	}
+	spec, err := readContainerSpec(c.Id)
+	switch {
+	case os.IsNotExist(err):
+		// the volumes the container uses are not known without its spec
+		logrus.Warnf("container {%s} has no spec, its volumes are not released", c.Id)
+	case err != nil:
+		errs = append(errs, err)
+	default:
+		if err = releaseVolumes(c.Id, spec.Mounts, command.Volumes); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		// keep the state, so the removal can be retried
+		return errors.Join(errs...)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if err = os.Remove(getContainerSpecFilePath(c.Id)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err = os.Remove(getContainerStatusFilePath(c.Id)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	names.release(c.Name, c.Id)
+	delete(restartDelays, c.Id)
+	logrus.Infof("Remove container {%s}", c.Id)
+	return nil
+}
+
+// autoRemoveContainer removes a container which exited if it was run with --rm.
+func autoRemoveContainer(id entity.ContainerId) {
+	spec, err := readContainerSpec(id)
+	if err != nil || !spec.AutoRemove {
+		return
+	}
+	// like docker, --rm takes the anonymous volumes of the container along
+	if err = removeContainer(conf.RmCommand{Id: id, Volumes: true}); err != nil {
+		logrus.Errorf("error auto remove container {%s}: %v", id, err)
+	}
+}
+
+func removeContainerCgroup(c *entity.Container) error {
+	id, cgroup := c.Id, containerCgroup(c)
+	// the scope of a container created by the systemd driver is gone with its last process unless
+	// something else is left in it
+	err := stopScope(cgroup)
+	if err != nil {
+		logrus.Warnf("error stop scope of container {%s}: %v", id, err)
+	}
+	for i := 0; i < cgroupRemoveRetries; i++ {
+		// a cgroup is removed with rmdir, it fails with EBUSY while processes are left in it
+		if err = manager.RemoveCgroup(cgroup); err == nil {
+			return nil
+		}
+		if !errors.Is(err, syscall.EBUSY) {
+			break
+		}
+		time.Sleep(cgroupRemoveInterval)
+	}
+	logrus.Errorf("error remove cgroup of container {%s}: %v", id, err)
+	return fmt.Errorf("remove cgroup of container %s: %w", id, err)
+}
+
+// removeContainerFs removes the overlay layers and the logs of a container, the volumes it
+// mounted are left to releaseVolumes.
+func removeContainerFs(id entity.ContainerId) error {
+	cfg := conf.GlobalConfig
+	cfg.Cmd = conf.Commands{Id: id}
+
+	// the overlay is mounted in the mount namespace of the container, it is only visible here if
+	// it was mounted on the host as well, e.g. by commit
+	mergePath := cfg.MergePath()
+	if err := syscall.Unmount(mergePath, syscall.MNT_DETACH); err != nil &&
+		!errors.Is(err, syscall.EINVAL) && !errors.Is(err, syscall.ENOENT) {
+		logrus.Errorf("error unmount %s: %v", mergePath, err)
+		return err
+	}
+
+	paths := []string{
+		mergePath,
+		cfg.WritePath(),
+		cfg.WorkPath(),
+		filepath.Dir(getContainerLogFilePath(id)),
+	}
+	for _, p := range paths {
+		if err := os.RemoveAll(p); err != nil {
+			logrus.Errorf("error remove %s: %v", p, err)
+			return err
+		}
+	}
+	return nil
+}
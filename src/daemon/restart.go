@@ -0,0 +1,157 @@
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/0x822a5b87/tiny-docker/src/conf"
+	"github.com/0x822a5b87/tiny-docker/src/entity"
+	"github.com/0x822a5b87/tiny-docker/src/subsystem/manager"
+	"github.com/0x822a5b87/tiny-docker/src/util"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// the delay before a container is restarted by its policy doubles with every restart, up to
+	// restartDelayMax, like it does in docker
+	restartDelayMin = 100 * time.Millisecond
+	restartDelayMax = time.Minute
+	// a container which ran at least this long restarts after restartDelayMin again
+	restartResetAfter = 10 * time.Second
+	// unknownExitCode is recorded for a container which exited while nobody could wait for it
+	unknownExitCode = 255
+)
+
+// restartDelays is guarded by mu
+var restartDelays = make(map[entity.ContainerId]time.Duration)
+
+// shouldRestart decides by the restart policy of the container whether it is started again after
+// it exited, mu must be held.
+func shouldRestart(state *entity.Container) bool {
+	if _, ok := restartingContainers[state.Id]; ok {
+		// restarted by the user, who starts it again right away
+		return false
+	}
+	spec, err := readContainerSpec(state.Id)
+	if err != nil {
+		return false
+	}
+	return spec.RestartPolicy.ShouldRestart(state.ExitCode, state.RestartCount, state.ManuallyStopped)
+}
+
+// nextRestartDelay returns the backoff before the next restart of the container, mu must be held.
+func nextRestartDelay(state *entity.Container) time.Duration {
+	delay, ok := restartDelays[state.Id]
+	ran := time.Duration(state.ExitAt-state.StartedAt) * time.Millisecond
+	if !ok || ran >= restartResetAfter {
+		delay = restartDelayMin
+	} else {
+		delay = min(2*delay, restartDelayMax)
+	}
+	restartDelays[state.Id] = delay
+	return delay
+}
+
+// scheduleRestart starts the container again after delay, unless it was started, stopped or
+// removed in the meantime, which all take it out of the restarting status.
+func scheduleRestart(id entity.ContainerId, delay time.Duration) {
+	logrus.Infof("Restart container {%s} in %v", id, delay)
+	time.AfterFunc(delay, func() {
+		mu.Lock()
+		p := getContainerStatusFilePath(id)
+		state, err := readContainerState(p)
+		if err != nil || state.Status != entity.ContainerRestarting {
+			mu.Unlock()
+			return
+		}
+		state.RestartCount++
+		err = writeContainerState(p, state)
+		mu.Unlock()
+		if err != nil {
+			return
+		}
+
+		if err = launchContainer(state); err != nil {
+			logrus.Errorf("error restart container {%s}: %v", id, err)
+			giveUpRestart(id)
+		}
+	})
+}
+
+// giveUpRestart leaves a container which failed to restart exited.
+func giveUpRestart(id entity.ContainerId) {
+	mu.Lock()
+	defer mu.Unlock()
+	p := getContainerStatusFilePath(id)
+	state, err := readContainerState(p)
+	if err != nil || state.Status != entity.ContainerRestarting {
+		return
+	}
+	state.Status = entity.ContainerExit
+	_ = writeContainerState(p, state)
+}
+
+// restoreContainers settles the containers that were running when mini-dockerd went down. A
+// container whose process is still alive is supervised again, the others exited while nobody
+// waited for them and are handled like any exit, which restarts them by their policy. A container
+// with the always policy is started again even if it was stopped by the user, that is what tells
+// it apart from unless-stopped.
+func restoreContainers() {
+	containers, err := readAllContainers()
+	if err != nil {
+		logrus.Errorf("error restore containers: %v", err)
+		return
+	}
+	for _, c := range containers {
+		switch c.Status {
+		case entity.ContainerRunning, entity.ContainerPaused:
+			restoreRunningContainer(c)
+		case entity.ContainerRestarting:
+			scheduleRestart(c.Id, restartDelayMin)
+		case entity.ContainerExit:
+			spec, err := readContainerSpec(c.Id)
+			if err != nil || spec.RestartPolicy.Name != conf.RestartAlways || !c.ManuallyStopped {
+				continue
+			}
+			if err = startContainer(c.Id); err != nil {
+				logrus.Errorf("error start container {%s}: %v", c.Id, err)
+			}
+		}
+	}
+}
+
+func restoreRunningContainer(c entity.Container) {
+	if manager.ContainsProcess(containerCgroup(&c), c.Pid) {
+		if isReportedByCli(c.Pid) {
+			logrus.Infof("Container {%s} runs in the foreground, its exit is reported by the CLI", c.Id)
+			watchCgroup(c.Id, containerCgroup(&c))
+			return
+		}
+		// the process is not a child of mini-dockerd any more, only its exit can be seen
+		if err := supervisor.watch(c.Id, c.Pid); err == nil {
+			watchCgroup(c.Id, containerCgroup(&c))
+			return
+		}
+	}
+	logrus.Warnf("Container {%s} exited while mini-dockerd was down", c.Id)
+	request := entity.ExitRequest{Id: c.Id, Pid: c.Pid, ExitCode: unknownExitCode}
+	if err := exitContainer(request); err != nil {
+		logrus.Errorf("error update exit of container {%s}: %v", c.Id, err)
+	}
+}
+
+// isReportedByCli tells whether the parent of a container process is the CLI which runs it in the
+// foreground.
+func isReportedByCli(pid int) bool {
+	ppid, err := util.ParentPid(pid)
+	if err != nil {
+		return false
+	}
+	parent, err := os.Readlink(fmt.Sprintf("/proc/%d/exe", ppid))
+	if err != nil {
+		return false
+	}
+	self, err := util.GetExecutableAbsolutePath()
+	return err == nil && parent == self
+}
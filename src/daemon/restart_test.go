@@ -0,0 +1,53 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/0x822a5b87/tiny-docker/src/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextRestartDelay(t *testing.T) {
+	saved := restartDelays
+	restartDelays = make(map[entity.ContainerId]time.Duration)
+	t.Cleanup(func() { restartDelays = saved })
+
+	// the exits of a container one after another, each ran for the given time
+	type step struct {
+		restartCount int
+		ran          time.Duration
+		delay        time.Duration
+	}
+	steps := []step{
+		{0, time.Second, restartDelayMin},
+		{1, time.Second, 2 * restartDelayMin},
+		{2, 0, 4 * restartDelayMin},
+		{3, restartResetAfter - time.Millisecond, 8 * restartDelayMin},
+		// a container which ran long enough starts over
+		{4, restartResetAfter, restartDelayMin},
+		{5, time.Second, 2 * restartDelayMin},
+		{6, time.Hour, restartDelayMin},
+	}
+	// the delay doubles up to the maximum
+	for i := 7; i < 20; i++ {
+		delay := min(restartDelayMin<<(i-6), restartDelayMax)
+		steps = append(steps, step{i, time.Second, delay})
+	}
+
+	startedAt := time.Now().UnixMilli()
+	for _, step := range steps {
+		state := &entity.Container{
+			Id:           "abc123",
+			RestartCount: step.restartCount,
+			StartedAt:    startedAt,
+			ExitAt:       startedAt + step.ran.Milliseconds(),
+		}
+		assert.Equal(t, step.delay, nextRestartDelay(state), step.restartCount)
+		startedAt = state.ExitAt + step.delay.Milliseconds()
+	}
+	assert.Equal(t, restartDelayMax, restartDelays["abc123"])
+
+	// the backoff is kept per container
+	assert.Equal(t, restartDelayMin, nextRestartDelay(&entity.Container{Id: "def456"}))
+}
@@ -1,19 +1,26 @@
 package daemon
 
 import (
+	"cmp"
+	"context"
+	"errors"
 	"io"
 	"log"
 	"os"
 	"os/exec"
 	"os/signal"
 	"path/filepath"
+	"strings"
 	"syscall"
+	"time"
 
+	"github.com/0x822a5b87/tiny-docker/src/client"
 	"github.com/0x822a5b87/tiny-docker/src/conf"
 	"github.com/0x822a5b87/tiny-docker/src/constant"
 	"github.com/0x822a5b87/tiny-docker/src/entity"
 	"github.com/0x822a5b87/tiny-docker/src/subsystem"
 	"github.com/0x822a5b87/tiny-docker/src/subsystem/cpu"
+	blkio "github.com/0x822a5b87/tiny-docker/src/subsystem/io"
 	"github.com/0x822a5b87/tiny-docker/src/subsystem/manager"
 	"github.com/0x822a5b87/tiny-docker/src/util"
 	"github.com/creack/pty"
@@ -22,7 +29,17 @@ import (
 	"golang.org/x/term"
 )
 
+// launchNotifyFd is the pipe a launcher uses to tell mini-dockerd that its container is running.
+const launchNotifyFd = 3
+
 func RunContainerCmd(commands conf.RunCommands) error {
+	return runContainerCmd(commands, func() {})
+}
+
+// runContainerCmd runs a container, started is invoked once the container is registered in
+// mini-dockerd. A container in the foreground is waited for here, a detached one is left to the
+// supervisor of mini-dockerd.
+func runContainerCmd(commands conf.RunCommands, started func()) error {
 	// NOTE THAT `runContainer` ONLY RUNS IN DAEMON PROCESS.
 	conf.LoadRunConfig(commands)
 	var err error
@@ -36,7 +53,7 @@ func RunContainerCmd(commands conf.RunCommands) error {
 		return err
 	}
 
-	if err = setupCgroup(os.Getpid(), commands.Args, commands.Cfg); err != nil {
+	if err = setupCgroup(os.Getpid(), commands.Cgroup, commands.Args, commands.Cfg); err != nil {
 		return err
 	}
 
@@ -45,52 +62,64 @@ func RunContainerCmd(commands conf.RunCommands) error {
 		return err
 	}
 
+	// in -it mode the container is started and registered while setting up its pty
 	if !(commands.Tty && !commands.Detach) {
 		if err = parent.Start(); err != nil {
 			logrus.Error("error start process: ", err)
 			return err
 		}
-		if err = SendContainerInitRequest(parent.Process.Pid); err != nil {
+		if err = registerContainer(parent.Process.Pid); err != nil {
 			logrus.Error("error send init request: ", err)
 			return err
 		}
 	}
+	started()
 
 	if commands.Detach {
-		err = SendWaitRequest(entity.WaitRequest{
-			Id:  conf.GlobalConfig.Cmd.Id,
-			Pid: parent.Process.Pid,
-		})
-		if err != nil {
-			logrus.Errorf("send wait request error : %s", err.Error())
-			return err
-		}
+		return nil
 	}
+	return reportExit(parent)
+}
 
-	if commands.Tty && !commands.Detach {
-		err = parent.Wait()
-		if err != nil {
-			logrus.Errorf("error wait for container in -it: %v", err)
-			return err
-		}
-		// exit container
-		if err = SendStopCurrentRequest(); err != nil {
-			logrus.Error("error send stop request: ", err)
-			return err
-		}
+// LaunchContainer starts a container from its persisted spec. It runs in a launcher process
+// forked by mini-dockerd, so the container is always detached. The launcher exits once the
+// container is running, which re-parents the container to mini-dockerd.
+func LaunchContainer(id entity.ContainerId) error {
+	spec, err := readContainerSpec(id)
+	if err != nil {
+		return err
 	}
-
-	return nil
+	spec.Tty = false
+	spec.Detach = true
+	spec.Cgroup = cmp.Or(spec.Cgroup, defaultContainerCgroup(id))
+
+	// the container must not inherit the notify pipe, or mini-dockerd never sees it closed
+	syscall.CloseOnExec(launchNotifyFd)
+	notify := os.NewFile(launchNotifyFd, "notify")
+	return runContainerCmd(*spec, func() {
+		_, _ = notify.Write([]byte{1})
+		_ = notify.Close()
+	})
 }
 
 func RunContainer(command string, args []string) error {
 	logrus.Infof("init container command: {%s}, args: {%v}", command, args)
 	var err error
 	_ = setupDetachMode()
+	if err = makeRootPropagation(conf.FsMergeLayerPath.Get()); err != nil {
+		return err
+	}
 	if err = setupUnionFsFromEnv(); err != nil {
 		return err
 	}
 	logrus.Info("setup layer success.")
+	// /dev is set up first, so that the volumes and the tmpfs below it are mounted over it
+	if err = setupDev(conf.FsMergeLayerPath.Get()); err != nil {
+		return err
+	}
+	if err = setupVolumes(conf.FsMergeLayerPath.Get()); err != nil {
+		return err
+	}
 	if err = setupMount(); err != nil {
 		return err
 	}
@@ -106,8 +135,11 @@ func RunContainer(command string, args []string) error {
 	return nil
 }
 
-func setupCgroup(pid int, commands []string, cfg conf.CgroupConfig) error {
-	cgroupManager, err := manager.NewCgroupManager(pid)
+func setupCgroup(pid int, cgroup string, commands []string, cfg conf.CgroupConfig) error {
+	if err := createCgroup(conf.GlobalConfig.Cmd.Id, cgroup, pid, cfg); err != nil {
+		return err
+	}
+	cgroupManager, err := manager.NewCgroupManager(cgroup, pid)
 	if err != nil {
 		return err
 	}
@@ -158,27 +190,117 @@ func setConf(cgroupManager *manager.CgroupManager, cfg conf.CgroupConfig) error
 		return err
 	}
 
-	err = setCpuShares(cgroupManager, cfg)
+	err = setCpuLimits(cgroupManager, cfg)
 	if err != nil {
 		return err
 	}
 
+	err = setIoLimits(cgroupManager, cfg)
+	if err != nil {
+		return err
+	}
+
+	err = cgroupManager.SetPidsMax(max(cfg.PidsLimit, 0))
+	if cfg.PidsLimit <= 0 && errors.Is(err, constant.ErrUnsupportedAction) {
+		// without the pids controller there is no limit to remove
+		return nil
+	}
+	return err
+}
+
+// setIoLimits throttles the block devices of cfg, the devices are resolved to their numbers on
+// every start since they may change across reboots.
+func setIoLimits(cgroupManager *manager.CgroupManager, cfg conf.CgroupConfig) error {
+	if cfg.BlkioWeight != 0 {
+		if err := cgroupManager.SetIoWeight(blkio.BlkioWeightToIoWeight(cfg.BlkioWeight)); err != nil {
+			return err
+		}
+	}
+	throttles := []struct {
+		devices []conf.ThrottleDevice
+		set     func(limit *blkio.DeviceLimit, rate uint64)
+	}{
+		{cfg.DeviceReadBps, func(limit *blkio.DeviceLimit, rate uint64) { limit.Rbps = rate }},
+		{cfg.DeviceWriteBps, func(limit *blkio.DeviceLimit, rate uint64) { limit.Wbps = rate }},
+		{cfg.DeviceReadIOps, func(limit *blkio.DeviceLimit, rate uint64) { limit.Riops = rate }},
+		{cfg.DeviceWriteIOps, func(limit *blkio.DeviceLimit, rate uint64) { limit.Wiops = rate }},
+	}
+	for _, throttle := range throttles {
+		for _, device := range throttle.devices {
+			major, minor, err := util.BlockDeviceNumber(device.Path)
+			if err != nil {
+				logrus.Errorf("error resolve device %s: %v", device.Path, err)
+				return err
+			}
+			limit := blkio.DeviceLimit{Major: major, Minor: minor}
+			throttle.set(&limit, device.Rate)
+			if err = cgroupManager.SetIoMax(limit); err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
 
 func setMemoryLimit(cgroupManager *manager.CgroupManager, cfg conf.CgroupConfig) error {
 	memoryLimit, _ := subsystem.SizeToBytes(cfg.MemoryLimit)
-	return cgroupManager.SetMemoryMax(int(memoryLimit))
+	err := cgroupManager.SetMemoryMax(int(memoryLimit))
+	if err != nil {
+		return err
+	}
+
+	for _, limit := range []struct {
+		value string
+		set   func(bytes int64) error
+	}{
+		{cfg.MemoryHigh, cgroupManager.SetMemoryHigh},
+		{cfg.MemoryReservation, cgroupManager.SetMemoryLow},
+		{cfg.MemoryMin, cgroupManager.SetMemoryMin},
+	} {
+		if limit.value == "" {
+			continue
+		}
+		bytes, err := subsystem.SizeToBytes(limit.value)
+		if err != nil {
+			return err
+		}
+		if err = limit.set(bytes); err != nil {
+			return err
+		}
+	}
+
+	if cfg.MemorySwap != "" {
+		swap, err := cfg.SwapMax()
+		if err != nil {
+			return err
+		}
+		if err = cgroupManager.SetMemorySwapMax(swap); err != nil {
+			return err
+		}
+	}
+
+	if cfg.OomGroup {
+		return cgroupManager.SetMemoryOomGroup(true)
+	}
+	return nil
 }
 
-func setCpuShares(cgroupManager *manager.CgroupManager, cfg conf.CgroupConfig) error {
+func setCpuLimits(cgroupManager *manager.CgroupManager, cfg conf.CgroupConfig) error {
 	v := cpu.MaxValue{}
-	err := v.From(cfg.CpuShares)
+	err := v.From(cfg.CpuMax)
 	if err != nil {
-		logrus.Errorf("set cpu shares error : %s", err.Error())
+		logrus.Errorf("set cpu max error : %s", err.Error())
+		return err
+	}
+	if err = cgroupManager.SetCpuMax(v.Quota, v.Period); err != nil {
 		return err
 	}
-	return cgroupManager.SetCpuMax(v.Quota, v.Period)
+	if cfg.CpuWeight != 0 {
+		if err = cgroupManager.SetCpuWeight(cfg.CpuWeight); err != nil {
+			return err
+		}
+	}
+	return cgroupManager.SetCpuset(cfg.CpusetCpus, cfg.CpusetMems)
 }
 
 func newContainerCmd() (*exec.Cmd, error) {
@@ -195,7 +317,8 @@ func newContainerCmd() (*exec.Cmd, error) {
 			unix.CLONE_NEWNS |
 			unix.CLONE_NEWNET |
 			unix.CLONE_NEWIPC,
-		Unshareflags: unix.CLONE_NEWNS,
+		// the mount namespace is not unshared again, which would make all mounts private before
+		// the container makes them slaves of the host
 	}
 
 	cmd.Dir = conf.GlobalConfig.MergePath()
@@ -235,6 +358,8 @@ func configureContainerProcessTerminalAndDaemonMode(cmd *exec.Cmd, interactive b
 	} else {
 		cmd.SysProcAttr.Setctty = false
 		cmd.Stdin = nil
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
 	}
 	return nil
 }
@@ -253,7 +378,7 @@ func setPty(cmd *exec.Cmd) error {
 	// Make sure to close the pty at the end.
 	defer func() { _ = ptmx.Close() }() // Best effort.
 
-	if err = SendContainerInitRequest(cmd.Process.Pid); err != nil {
+	if err = registerContainer(cmd.Process.Pid); err != nil {
 		logrus.Errorf("send init request error : %s", err.Error())
 		return err
 	}
@@ -286,6 +411,43 @@ func setPty(cmd *exec.Cmd) error {
 	return nil
 }
 
+// reportExit waits for the container process and reports how it terminated to mini-dockerd.
+func reportExit(parent *exec.Cmd) error {
+	// a non-zero exit is not an error of ours, the status is in ProcessState either way
+	if err := parent.Wait(); err != nil && parent.ProcessState == nil {
+		logrus.Errorf("error wait for container: %v", err)
+		return err
+	}
+	exitCode, signal := util.ExitStatus(parent.ProcessState.Sys().(syscall.WaitStatus))
+	request := entity.ExitRequest{
+		Id:       conf.GlobalConfig.Cmd.Id,
+		Pid:      parent.Process.Pid,
+		ExitCode: exitCode,
+		Signal:   signal,
+	}
+	if err := client.New().ContainerExit(context.Background(), request); err != nil {
+		logrus.Errorf("error send exit request: %v", err)
+		return err
+	}
+	return nil
+}
+
+// registerContainer reports the container process of the current run to mini-dockerd.
+func registerContainer(pid int) error {
+	c := entity.Container{
+		Id:        conf.GlobalConfig.Cmd.Id,
+		Pid:       pid,
+		Image:     conf.GlobalConfig.ImageName(),
+		Command:   strings.Join(conf.GlobalConfig.Cmd.Args, " "),
+		CreatedAt: time.Now().UnixMilli(),
+		StartedAt: time.Now().UnixMilli(),
+		Status:    entity.ContainerRunning,
+		Name:      conf.GlobalConfig.Cmd.Name,
+		Cgroup:    conf.GlobalConfig.Cmd.Cgroup,
+	}
+	return client.New().ContainerRegister(context.Background(), c)
+}
+
 func getContainerLogFilePath(id entity.ContainerId) string {
 	fileRoot := conf.RuntimeDockerdContainerLog.Get()
 	return filepath.Join(fileRoot, string(id), constant.ContainerLogFile)
@@ -1,26 +1,57 @@
 package daemon
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
 
 	"github.com/0x822a5b87/tiny-docker/src/conf"
+	"github.com/0x822a5b87/tiny-docker/src/constant"
 	"github.com/0x822a5b87/tiny-docker/src/entity"
+	"github.com/0x822a5b87/tiny-docker/src/subsystem/manager"
 	"github.com/0x822a5b87/tiny-docker/src/util"
 	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
 )
 
 var mu sync.Mutex
 
+// restartingContainers is guarded by mu
+var restartingContainers = make(map[entity.ContainerId]struct{})
+
+const (
+	// stopReportTimeout bounds the wait for the exit report of a killed container.
+	stopReportTimeout = 5 * time.Second
+	// attachPollInterval is how often the log of an attached container is checked for output.
+	attachPollInterval = 100 * time.Millisecond
+)
+
 func runContainer(c entity.Container) error {
+	// a container started from a created or exited one keeps its identity
+	if preState, err := readContainerState(getContainerStatusFilePath(c.Id)); err == nil {
+		c.CreatedAt = preState.CreatedAt
+		c.Name = preState.Name
+		c.RestartCount = preState.RestartCount
+	} else {
+		name, err := names.reserve(c.Name, c.Id)
+		if err != nil {
+			return err
+		}
+		c.Name = name
+	}
 	data, err := json.Marshal(c)
 	if err != nil {
-		logrus.Error("error serialize container: {%s}, {%v}", c, err)
+		logrus.Errorf("error serialize container: {%v}, {%v}", c, err)
 		return err
 	}
 	p := getContainerStatusFilePath(c.Id)
@@ -29,56 +60,417 @@ func runContainer(c entity.Container) error {
 		return err
 	}
 	logrus.Infof("Saving container in file {%s}", p)
+	watchCgroup(c.Id, containerCgroup(&c))
+	publishEvent(entity.EventStart, c, nil)
 	return nil
 }
 
-func stopContainers(containers []entity.Container) error {
-	// TODO it must return a error list
-	var err error
-	for _, container := range containers {
-		err = stopContainer(container.Id)
-		if err != nil {
-			logrus.Errorf("error stop container: %s", container.Id)
+// createContainer persists the spec of a new container, the container is started by startContainer.
+func createContainer(spec conf.RunCommands) (*entity.Container, error) {
+	fullID, _ := conf.GenContainerID()
+	spec.Id = entity.ContainerId(fullID)
+	if spec.AutoRemove && !spec.RestartPolicy.IsNone() {
+		return nil, constant.ErrConflictingOptions.WrapMessage("--restart and --rm")
+	}
+	if spec.StopSignal != "" {
+		if _, err := util.ParseSignal(spec.StopSignal); err != nil {
+			return nil, err
+		}
+	}
+	cgroup, err := conf.GlobalConfig.Cgroup.ContainerCgroup(spec.Id, spec.Cfg.CgroupParent)
+	if err != nil {
+		return nil, err
+	}
+	spec.Cgroup = cgroup
+	if err = validateCgroupConfig(cgroup, spec.Cfg); err != nil {
+		return nil, err
+	}
+	name, err := names.reserve(spec.Name, spec.Id)
+	if err != nil {
+		return nil, err
+	}
+	spec.Name = name
+	if err = acquireVolumes(spec.Id, spec.Mounts); err != nil {
+		names.release(name, spec.Id)
+		return nil, err
+	}
+	if err = writeContainerSpec(spec); err != nil {
+		_ = os.Remove(getContainerSpecFilePath(spec.Id))
+		_ = releaseVolumes(spec.Id, spec.Mounts, true)
+		names.release(name, spec.Id)
+		return nil, err
+	}
+
+	imageName := conf.ExtractNameFromTarPath(spec.Image)
+	c := &entity.Container{
+		Id:        spec.Id,
+		Image:     imageName,
+		Command:   strings.Join(spec.Args, " "),
+		CreatedAt: time.Now().UnixMilli(),
+		Status:    entity.ContainerCreated,
+		Name:      name,
+		Cgroup:    cgroup,
+	}
+	if err = writeContainerState(getContainerStatusFilePath(c.Id), c); err != nil {
+		// a spec without a state would be a container nobody can see or remove
+		_ = os.Remove(getContainerSpecFilePath(spec.Id))
+		_ = releaseVolumes(spec.Id, spec.Mounts, true)
+		names.release(name, spec.Id)
+		return nil, err
+	}
+	logrus.Infof("Create container {%s}", c.Id)
+	return c, nil
+}
+
+// startContainer starts a created or exited container on behalf of the user, which resets its
+// restart count and lifts a manual stop.
+func startContainer(ref entity.ContainerId) error {
+	state, err := resolveContainer(ref)
+	if err != nil {
+		return err
+	}
+	id := state.Id
+	if _, err = readContainerSpec(id); err != nil {
+		return err
+	}
+
+	mu.Lock()
+	p := getContainerStatusFilePath(id)
+	if state, err = readContainerState(p); err != nil {
+		mu.Unlock()
+		return err
+	}
+	if state.Status.Alive() {
+		mu.Unlock()
+		return constant.ErrIllegalContainerStatus.WrapMessage(fmt.Sprintf("container %s is already running", id))
+	}
+	if state.Status == entity.ContainerRestarting {
+		// cancels the pending restart
+		state.Status = entity.ContainerExit
+	}
+	state.RestartCount = 0
+	state.ManuallyStopped = false
+	delete(restartDelays, id)
+	err = writeContainerState(p, state)
+	mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return launchContainer(state)
+}
+
+// launchContainer runs a launcher process which sets up and starts the container from its spec.
+// The launcher signals through a pipe once the container is registered and exits, so that the
+// container is a child of mini-dockerd by the time it is handed to the supervisor.
+func launchContainer(c *entity.Container) error {
+	id := c.Id
+	// the cgroup of a previous run keeps its limits and event counters
+	if err := removeContainerCgroup(c); err != nil {
+		return err
+	}
+
+	ready, notify, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = ready.Close() }()
+
+	cmd, err := newLaunchCmd(id, notify)
+	if err != nil {
+		_ = notify.Close()
+		return err
+	}
+	err = cmd.Start()
+	_ = notify.Close()
+	if err != nil {
+		logrus.Errorf("error launch container {%s}: %v", id, err)
+		return err
+	}
+
+	buf := make([]byte, 1)
+	_, readErr := ready.Read(buf)
+	if err = cmd.Wait(); err != nil || readErr != nil {
+		logrus.Errorf("error launch container {%s}: %v", id, errors.Join(readErr, err))
+		return constant.ErrExecCommand.WrapMessage(fmt.Sprintf("container %s failed to start, see the log of mini-dockerd", id))
+	}
+
+	state, err := readContainerState(getContainerStatusFilePath(id))
+	if err != nil {
+		return err
+	}
+	if err = supervisor.watch(id, state.Pid); err != nil {
+		return err
+	}
+	logrus.Infof("Start container {%s}", id)
+	return nil
+}
+
+func newLaunchCmd(id entity.ContainerId, notify *os.File) (*exec.Cmd, error) {
+	execPath, err := util.GetExecutableAbsolutePath()
+	if err != nil {
+		return nil, err
+	}
+	cmd := exec.Command(execPath, constant.InitLaunch.String(), string(id))
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	// the notify pipe is fd 3 in the launcher
+	cmd.ExtraFiles = []*os.File{notify}
+	return cmd, nil
+}
+
+// stopContainerByRef stops the container the user refers to, see stopContainer.
+func stopContainerByRef(command conf.StopCommand) error {
+	c, err := resolveContainer(command.Id)
+	if err != nil {
+		return err
+	}
+	return stopContainer(c.Id, time.Duration(command.Timeout)*time.Second)
+}
+
+// stopContainer asks the container to terminate with its stop signal and kills it once timeout
+// passed, a zero timeout kills it right away. It returns after the exit of the container was
+// recorded. The container is not restarted by its restart policy afterwards.
+func stopContainer(id entity.ContainerId, timeout time.Duration) error {
+	p := getContainerStatusFilePath(id)
+	state, err := markManuallyStopped(id)
+	if err != nil {
+		return err
+	}
+	if !state.Status.Alive() {
+		return nil
+	}
+	if state.Status == entity.ContainerPaused {
+		// a frozen process does not handle the stop signal
+		if err = thawContainer(state); err != nil {
+			return err
 		}
 	}
-	return err
+
+	exited := subscribeExit(id)
+	defer unsubscribeExit(id, exited)
+	if sig := stopSignal(id); timeout > 0 && sig != unix.SIGKILL {
+		if err = killContainer(state, sig); err == nil && waitExit(exited, timeout) {
+			logrus.Infof("Stop container {%s}", id)
+			return nil
+		}
+	}
+
+	err = killContainer(state, unix.SIGKILL)
+	if err != nil && !errors.Is(err, syscall.ESRCH) {
+		return err
+	}
+	// the parent of the container reports the exit, unless it is gone as well
+	if err == nil && waitExit(exited, stopReportTimeout) {
+		logrus.Infof("Stop container {%s}", id)
+		return nil
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if state, err = readContainerState(p); err != nil {
+		return err
+	}
+	if state.Status.Alive() {
+		state.Status = entity.ContainerExit
+		state.ExitAt = time.Now().UnixMilli()
+		state.ExitCode, state.Signal = 128+int(syscall.SIGKILL), int(syscall.SIGKILL)
+		if err = writeContainerState(p, state); err != nil {
+			logrus.Errorf("error saving state: %v", err)
+			return err
+		}
+	}
+	notifyExit(id)
+	logrus.Infof("Stop container {%s} without an exit report", id)
+	return nil
 }
 
-func stopContainer(id entity.ContainerId) error {
+// markManuallyStopped keeps the restart policy from starting the container again, a pending
+// restart is cancelled.
+func markManuallyStopped(id entity.ContainerId) (*entity.Container, error) {
 	mu.Lock()
 	defer mu.Unlock()
 	p := getContainerStatusFilePath(id)
-	preState, err := readContainerState(p)
+	state, err := readContainerState(p)
 	if err != nil {
 		logrus.Errorf("error read pre state: %v", err)
-		return err
+		return nil, err
+	}
+	switch state.Status {
+	case entity.ContainerRestarting:
+		state.Status = entity.ContainerExit
+	case entity.ContainerRunning, entity.ContainerPaused:
+	default:
+		return state, nil
 	}
+	state.ManuallyStopped = true
+	return state, writeContainerState(p, state)
+}
 
-	if preState.Status == entity.ContainerRunning {
-		if err = util.KillProcessByPID(preState.Pid, 9); err != nil {
-			if errors.Is(err, syscall.ESRCH) {
-				logrus.Debugf("process %d does not exist, ignore error", preState.Pid)
-			} else {
-				return err
-			}
+func waitExit(exited chan struct{}, timeout time.Duration) bool {
+	select {
+	case <-exited:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// signalContainer sends a signal to a running container. Killing it, or sending its stop signal,
+// counts as a stop by the user so that its restart policy does not start it again.
+func signalContainer(command conf.KillCommand) error {
+	c, err := resolveContainer(command.Id)
+	if err != nil {
+		return err
+	}
+	sig := unix.SIGKILL
+	if command.Signal != "" {
+		if sig, err = util.ParseSignal(command.Signal); err != nil {
+			return err
 		}
 	}
+	if !c.Status.Alive() {
+		return constant.ErrIllegalContainerStatus.WrapMessage(fmt.Sprintf("container %s is not running", c.Id))
+	}
+	if sig == unix.SIGKILL || sig == stopSignal(c.Id) {
+		if c, err = markManuallyStopped(c.Id); err != nil {
+			return err
+		}
+	}
+	if err = killContainer(c, sig); err != nil {
+		return err
+	}
+	logrus.Infof("Send signal %d to container {%s}", sig, c.Id)
+	return nil
+}
 
-	preState.Status = entity.ContainerExit
-	preState.ExitAt = time.Now().UnixMilli()
+// stopSignal returns the signal which asks the container to exit.
+func stopSignal(id entity.ContainerId) unix.Signal {
+	spec, err := readContainerSpec(id)
+	if err != nil || spec.StopSignal == "" {
+		return unix.SIGTERM
+	}
+	sig, err := util.ParseSignal(spec.StopSignal)
+	if err != nil {
+		return unix.SIGTERM
+	}
+	return sig
+}
 
-	err = writeContainerState(p, preState)
+func killContainer(c *entity.Container, sig unix.Signal) error {
+	supervised, err := supervisor.signal(c.Id, sig)
+	if supervised {
+		return err
+	}
+	return util.KillProcessByPID(c.Pid, int(sig))
+}
+
+// restartContainer stops the container, if it is running, and starts it again.
+func restartContainer(command conf.RestartCommand) error {
+	c, err := resolveContainer(command.Id)
 	if err != nil {
-		logrus.Errorf("error saving state: %v", err)
 		return err
 	}
+	setRestarting(c.Id, true)
+	defer setRestarting(c.Id, false)
+	if err = stopContainer(c.Id, time.Duration(command.Timeout)*time.Second); err != nil {
+		return err
+	}
+	return startContainer(c.Id)
+}
 
-	logrus.Infof("Stop container {%s}", p)
-	return nil
+// a container being restarted is not removed by --rm when it exits
+func setRestarting(id entity.ContainerId, restarting bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	if restarting {
+		restartingContainers[id] = struct{}{}
+	} else {
+		delete(restartingContainers, id)
+	}
+}
+
+// inspectContainer returns the state of the container along with the usage read from its cgroup.
+func inspectContainer(ref entity.ContainerId) (*entity.Container, error) {
+	c, err := resolveContainer(ref)
+	if err != nil || !c.Status.Alive() {
+		return c, err
+	}
+	if c.PidsCurrent, err = manager.ReadPidsCurrent(containerCgroup(c)); err != nil {
+		logrus.Warnf("error read pids of container {%s}: %v", c.Id, err)
+	}
+	return c, nil
 }
 
-func logs(containerId entity.ContainerId) (string, error) {
-	logFile := getContainerLogFilePath(containerId)
+// attachContainer sends the output the container writes from now on until it exits, the container
+// is started first if start is set. It returns the state of the exited container.
+func attachContainer(ctx context.Context, ref entity.ContainerId, start bool, send func(string) error) (*entity.Container, error) {
+	c, err := resolveContainer(ref)
+	if err != nil {
+		return nil, err
+	}
+	exited := subscribeExit(c.Id)
+	defer unsubscribeExit(c.Id, exited)
+
+	logFile, err := util.EnsureOpenFilePath(getContainerLogFilePath(c.Id))
+	if err != nil {
+		return nil, err
+	}
+	_ = logFile.Close()
+	f, err := os.Open(getContainerLogFilePath(c.Id))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	if _, err = f.Seek(0, io.SeekEnd); err != nil {
+		return nil, err
+	}
+
+	if start {
+		if err = startContainer(c.Id); err != nil {
+			return nil, err
+		}
+	} else if !c.Status.Alive() {
+		return c, nil
+	}
+
+	ticker := time.NewTicker(attachPollInterval)
+	defer ticker.Stop()
+	buf := make([]byte, 32*1024)
+	for {
+		done := false
+		select {
+		case <-exited:
+			done = true
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+		// drain everything written so far, after the exit this is the rest of the output
+		for {
+			n, err := f.Read(buf)
+			if n > 0 {
+				if err = send(string(buf[:n])); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			if err != nil && err != io.EOF {
+				return nil, err
+			}
+			break
+		}
+		if done {
+			return readContainerState(getContainerStatusFilePath(c.Id))
+		}
+	}
+}
+
+func logs(ref entity.ContainerId) (string, error) {
+	c, err := resolveContainer(ref)
+	if err != nil {
+		return "", err
+	}
+	logFile := getContainerLogFilePath(c.Id)
 	data, err := os.ReadFile(logFile)
 	if err != nil {
 		logrus.Errorf("error read log file: %v", err)
@@ -87,46 +479,119 @@ func logs(containerId entity.ContainerId) (string, error) {
 	return string(data), nil
 }
 
-func wait(request entity.WaitRequest) {
-	pollInterval := 1 * time.Second
-	for {
-		if err := syscall.Kill(request.Pid, 0); err != nil {
-			// There is no need to worry about multiple stop operations, as the stop function
-			// includes a mutex lock and status check internally.
-			err = stopContainer(request.Id)
-			if err != nil {
-				logrus.Errorf("error stop container in wait: %s", request.Id)
-			} else {
-				logrus.Infof("detach process is exited.")
-			}
-			return
+// exitContainer records how the process of a container terminated and wakes up its waiters.
+func exitContainer(request entity.ExitRequest) error {
+	mu.Lock()
+	defer mu.Unlock()
+	p := getContainerStatusFilePath(request.Id)
+	state, err := readContainerState(p)
+	if err != nil {
+		return err
+	}
+	if request.Pid != state.Pid {
+		logrus.Warnf("ignore exit of pid %d, container {%s} runs as pid %d now", request.Pid, request.Id, state.Pid)
+		return nil
+	}
+
+	if state.Status.Alive() {
+		state.ExitAt = time.Now().UnixMilli()
+	}
+	state.Status = entity.ContainerExit
+	state.ExitCode = request.ExitCode
+	state.Signal = request.Signal
+	recordOomKills(state)
+	restart := shouldRestart(state)
+	if restart {
+		state.Status = entity.ContainerRestarting
+	}
+	if err = writeContainerState(p, state); err != nil {
+		return err
+	}
+	logrus.Infof("Container {%s} exited with code %d", request.Id, request.ExitCode)
+	notifyExit(request.Id)
+	publishEvent(entity.EventDie, *state, map[string]string{
+		"exitCode":  strconv.Itoa(state.ExitCode),
+		"oomKilled": strconv.FormatBool(state.OOMKilled),
+	})
+	if restart {
+		scheduleRestart(request.Id, nextRestartDelay(state))
+	} else if _, ok := restartingContainers[request.Id]; !ok {
+		// mu is held here, removal takes it again
+		go autoRemoveContainer(request.Id)
+	}
+	return nil
+}
+
+// recordOomKills records whether the kernel OOM killer killed processes of the container, the
+// watcher of its cgroup may not have seen the last kill yet.
+func recordOomKills(state *entity.Container) {
+	events, err := manager.ReadMemoryEvents(containerCgroup(state))
+	if err != nil {
+		logrus.Warnf("error read memory events of container {%s}: %v", state.Id, err)
+		return
+	}
+	state.OOMKilled = events.OomKill > 0
+	state.OOMKillCount = events.OomKill
+}
+
+var (
+	waitersMu   sync.Mutex
+	exitWaiters = make(map[entity.ContainerId][]chan struct{})
+)
+
+func subscribeExit(id entity.ContainerId) chan struct{} {
+	waitersMu.Lock()
+	defer waitersMu.Unlock()
+	ch := make(chan struct{})
+	exitWaiters[id] = append(exitWaiters[id], ch)
+	return ch
+}
+
+func unsubscribeExit(id entity.ContainerId, ch chan struct{}) {
+	waitersMu.Lock()
+	defer waitersMu.Unlock()
+	waiters := exitWaiters[id]
+	for i, waiter := range waiters {
+		if waiter == ch {
+			exitWaiters[id] = append(waiters[:i], waiters[i+1:]...)
+			break
 		}
+	}
+	if len(exitWaiters[id]) == 0 {
+		delete(exitWaiters, id)
+	}
+}
 
-		time.Sleep(pollInterval)
+func notifyExit(id entity.ContainerId) {
+	waitersMu.Lock()
+	defer waitersMu.Unlock()
+	for _, ch := range exitWaiters[id] {
+		close(ch)
 	}
+	delete(exitWaiters, id)
 }
 
-// strictWait a more strict version for wait
-func strictWait(request entity.WaitRequest) {
-	pollInterval := 1 * time.Second
+// waitContainer blocks until the container is not running and returns its final state.
+func waitContainer(ctx context.Context, ref entity.ContainerId) (*entity.Container, error) {
+	c, err := resolveContainer(ref)
+	if err != nil {
+		return nil, err
+	}
+	id := c.Id
 	for {
-		var waitStatus syscall.WaitStatus
-		_, err := syscall.Wait4(request.Pid, &waitStatus, syscall.WNOHANG|syscall.WEXITED, nil)
-
-		isProcessExit := func(status syscall.WaitStatus) bool { return status.Exited() }
-		isProcessEsrch := func(err error) bool { return err != nil && errors.Is(err, syscall.ESRCH) }
-
-		if isProcessExit(waitStatus) || isProcessEsrch(err) {
-			err = stopContainer(request.Id)
-			if err != nil {
-				logrus.Errorf("error stop container in wait: %s", request.Id)
-			} else {
-				logrus.Infof("detach process is exited.")
-			}
-			return
+		// subscribe before reading the state, so an exit in between is not missed
+		ch := subscribeExit(id)
+		state, err := readContainerState(getContainerStatusFilePath(id))
+		if err != nil || !state.Status.Alive() {
+			unsubscribeExit(id, ch)
+			return state, err
+		}
+		select {
+		case <-ch:
+		case <-ctx.Done():
+			unsubscribeExit(id, ch)
+			return nil, ctx.Err()
 		}
-
-		time.Sleep(pollInterval)
 	}
 }
 
@@ -143,7 +608,7 @@ func ps(command conf.PsCommand) ([]entity.Container, error) {
 
 	targetContainers := make([]entity.Container, 0)
 	for _, container := range allContainers {
-		if container.Status != entity.ContainerRunning {
+		if !container.Status.Alive() && container.Status != entity.ContainerRestarting {
 			continue
 		}
 		targetContainers = append(targetContainers, container)
@@ -186,6 +651,37 @@ func writeContainerState(p string, state *entity.Container) error {
 	return nil
 }
 
+func getContainerSpecFilePath(id entity.ContainerId) string {
+	fileRoot := conf.RuntimeDockerdContainerSpec.Get()
+	return filepath.Join(fileRoot, string(id))
+}
+
+func readContainerSpec(id entity.ContainerId) (*conf.RunCommands, error) {
+	data, err := os.ReadFile(getContainerSpecFilePath(id))
+	if err != nil {
+		logrus.Errorf("error read container spec : %v", err)
+		return nil, err
+	}
+	spec := &conf.RunCommands{}
+	if err = json.Unmarshal(data, spec); err != nil {
+		logrus.Errorf("error unmarshal container spec : %v", err)
+		return nil, err
+	}
+	return spec, nil
+}
+
+func writeContainerSpec(spec conf.RunCommands) error {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return err
+	}
+	p := getContainerSpecFilePath(spec.Id)
+	if err = util.EnsureFileExists(p); err != nil {
+		return err
+	}
+	return os.WriteFile(p, data, 0644)
+}
+
 func readAllContainers() ([]entity.Container, error) {
 	p := conf.RuntimeDockerdContainerStatus.Get()
 	containerData, err := util.ReadAllFilesInDir(p)
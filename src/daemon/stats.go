@@ -0,0 +1,149 @@
+package daemon
+
+import (
+	"context"
+	"time"
+
+	"github.com/0x822a5b87/tiny-docker/src/conf"
+	"github.com/0x822a5b87/tiny-docker/src/entity"
+	"github.com/0x822a5b87/tiny-docker/src/subsystem/manager"
+	"github.com/0x822a5b87/tiny-docker/src/subsystem/memory"
+	"github.com/0x822a5b87/tiny-docker/src/util"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+)
+
+// statsInterval is the time between two samples, the cpu usage is averaged over it.
+const statsInterval = time.Second
+
+// statsContainers samples the usage of containers every statsInterval and sends every sample
+// but the last, which is returned. A single sample is taken unless command.Stream is set, then
+// sampling goes on until ctx is cancelled. The first sample comes after statsInterval, since the
+// cpu usage is the difference to the one before.
+func statsContainers(ctx context.Context, command conf.StatsCommand, send func([]entity.ContainerStats) error) ([]entity.ContainerStats, error) {
+	containers, err := statsTargets(command.Ids)
+	if err != nil {
+		return nil, err
+	}
+	previous := make(map[entity.ContainerId]cpuSample)
+	sampleCpu(containers, previous)
+
+	ticker := time.NewTicker(statsInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+		if len(command.Ids) == 0 {
+			// pick up the containers started in the meantime
+			if containers, err = statsTargets(nil); err != nil {
+				return nil, err
+			}
+		}
+		stats := make([]entity.ContainerStats, 0, len(containers))
+		for _, c := range containers {
+			stats = append(stats, sampleStats(c, previous))
+		}
+		if !command.Stream {
+			return stats, nil
+		}
+		if err = send(stats); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// statsTargets resolves the containers to sample, all running ones if refs is empty.
+func statsTargets(refs []entity.ContainerId) ([]entity.Container, error) {
+	if len(refs) == 0 {
+		all, err := readAllContainers()
+		if err != nil {
+			return nil, err
+		}
+		containers := make([]entity.Container, 0, len(all))
+		for _, c := range all {
+			if c.Status.Alive() {
+				containers = append(containers, c)
+			}
+		}
+		return containers, nil
+	}
+	containers := make([]entity.Container, 0, len(refs))
+	for _, ref := range refs {
+		c, err := resolveContainer(ref)
+		if err != nil {
+			return nil, err
+		}
+		containers = append(containers, *c)
+	}
+	return containers, nil
+}
+
+type cpuSample struct {
+	at        time.Time
+	usageUsec uint64
+}
+
+func sampleCpu(containers []entity.Container, previous map[entity.ContainerId]cpuSample) {
+	for _, c := range containers {
+		if stats, err := manager.ReadStats(containerCgroup(&c)); err == nil {
+			previous[c.Id] = cpuSample{at: time.Now(), usageUsec: stats.Cpu.UsageUsec}
+		}
+	}
+}
+
+// sampleStats reads the usage of a container, a container which is not running uses nothing.
+func sampleStats(c entity.Container, previous map[entity.ContainerId]cpuSample) entity.ContainerStats {
+	sample := entity.ContainerStats{Id: c.Id, Name: c.Name}
+	stats, err := manager.ReadStats(containerCgroup(&c))
+	if err != nil {
+		logrus.Debugf("error read stats of container {%s}: %v", c.Id, err)
+		return sample
+	}
+
+	now := time.Now()
+	if prev, ok := previous[c.Id]; ok && stats.Cpu.UsageUsec >= prev.usageUsec {
+		if elapsed := now.Sub(prev.at).Microseconds(); elapsed > 0 {
+			sample.CpuPercent = float64(stats.Cpu.UsageUsec-prev.usageUsec) / float64(elapsed) * 100
+		}
+	}
+	previous[c.Id] = cpuSample{at: now, usageUsec: stats.Cpu.UsageUsec}
+
+	// like docker, the inactive page cache is not counted as it is reclaimed first
+	sample.MemoryUsage = stats.MemoryCurrent.Bytes
+	if stats.MemoryStat.InactiveFile < sample.MemoryUsage {
+		sample.MemoryUsage -= stats.MemoryStat.InactiveFile
+	}
+	sample.MemoryLimit = uint64(stats.MemoryMax.Bytes)
+	if stats.MemoryMax.Bytes == 0 || stats.MemoryMax.Bytes == memory.Unlimited {
+		sample.MemoryLimit = totalMemory()
+	}
+	if sample.MemoryLimit > 0 {
+		sample.MemoryPercent = float64(sample.MemoryUsage) / float64(sample.MemoryLimit) * 100
+	}
+
+	block := stats.Io.Total()
+	sample.BlockRead = block.Rbytes
+	sample.BlockWrite = block.Wbytes
+	sample.Pids = stats.Pids.Count
+
+	if endpoint, err := networks.Endpoint(c.Id); err == nil {
+		// the host side of the veth pair receives what the container sends
+		if rx, tx, err := util.LinkStatistics(endpoint.Name); err == nil {
+			sample.NetRx = tx
+			sample.NetTx = rx
+		}
+	}
+	return sample
+}
+
+// totalMemory is the memory limit of a container without one.
+func totalMemory() uint64 {
+	var info unix.Sysinfo_t
+	if err := unix.Sysinfo(&info); err != nil {
+		return 0
+	}
+	return info.Totalram * uint64(info.Unit)
+}
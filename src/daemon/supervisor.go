@@ -0,0 +1,141 @@
+package daemon
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/0x822a5b87/tiny-docker/src/entity"
+	"github.com/0x822a5b87/tiny-docker/src/util"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+)
+
+// mini-dockerd is a child subreaper, the launcher of a container exits right after the container
+// started so the container is re-parented to mini-dockerd. The supervisor holds a pidfd for every
+// such container and a single goroutine waits on all of them with epoll, a pidfd turns readable
+// once its process exits and the exit status is collected with waitid(P_PIDFD).
+//
+// Containers run in the foreground are children of the CLI, which reports their exit itself.
+//
+// Containers which survived a restart of mini-dockerd are supervised as well, but they are no
+// children of it any more: their exit is seen, their exit status is not.
+
+var supervisor *processSupervisor
+
+type supervisedProcess struct {
+	id    entity.ContainerId
+	pid   int
+	pidfd int
+}
+
+type processSupervisor struct {
+	mu    sync.Mutex
+	epfd  int
+	procs map[entity.ContainerId]*supervisedProcess
+	fds   map[int32]*supervisedProcess
+}
+
+func startSupervisor() error {
+	if err := unix.Prctl(unix.PR_SET_CHILD_SUBREAPER, 1, 0, 0, 0); err != nil {
+		logrus.Errorf("error set child subreaper: %v", err)
+		return err
+	}
+	epfd, err := unix.EpollCreate1(unix.EPOLL_CLOEXEC)
+	if err != nil {
+		logrus.Errorf("error create epoll: %v", err)
+		return err
+	}
+	supervisor = &processSupervisor{
+		epfd:  epfd,
+		procs: make(map[entity.ContainerId]*supervisedProcess),
+		fds:   make(map[int32]*supervisedProcess),
+	}
+	go supervisor.loop()
+	return nil
+}
+
+// watch starts supervising the container process pid, which should be a child of mini-dockerd.
+func (s *processSupervisor) watch(id entity.ContainerId, pid int) error {
+	pidfd, err := unix.PidfdOpen(pid, 0)
+	if err != nil {
+		logrus.Errorf("error open pidfd of container {%s}, pid %d: %v", id, pid, err)
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	event := unix.EpollEvent{Events: unix.EPOLLIN, Fd: int32(pidfd)}
+	if err = unix.EpollCtl(s.epfd, unix.EPOLL_CTL_ADD, pidfd, &event); err != nil {
+		_ = unix.Close(pidfd)
+		return err
+	}
+	p := &supervisedProcess{id: id, pid: pid, pidfd: pidfd}
+	s.procs[id] = p
+	s.fds[int32(pidfd)] = p
+	logrus.Infof("Supervise container {%s} with pid %d", id, pid)
+	return nil
+}
+
+// signal sends sig to a supervised container through its pidfd, which can not hit a process
+// that reused the pid. It reports false if the container is not supervised.
+func (s *processSupervisor) signal(id entity.ContainerId, sig unix.Signal) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.procs[id]
+	if !ok {
+		return false, nil
+	}
+	return true, unix.PidfdSendSignal(p.pidfd, sig, nil, 0)
+}
+
+func (s *processSupervisor) loop() {
+	events := make([]unix.EpollEvent, 16)
+	for {
+		n, err := unix.EpollWait(s.epfd, events, -1)
+		if err != nil {
+			if errors.Is(err, unix.EINTR) {
+				continue
+			}
+			logrus.Errorf("error wait epoll, container supervision stopped: %v", err)
+			return
+		}
+		for i := 0; i < n; i++ {
+			if p := s.remove(events[i].Fd); p != nil {
+				s.reap(p)
+			}
+		}
+	}
+}
+
+func (s *processSupervisor) remove(fd int32) *supervisedProcess {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.fds[fd]
+	if !ok {
+		return nil
+	}
+	delete(s.fds, fd)
+	// the container may run as a new process already
+	if s.procs[p.id] == p {
+		delete(s.procs, p.id)
+	}
+	_ = unix.EpollCtl(s.epfd, unix.EPOLL_CTL_DEL, p.pidfd, nil)
+	return p
+}
+
+func (s *processSupervisor) reap(p *supervisedProcess) {
+	defer func() { _ = unix.Close(p.pidfd) }()
+	exitCode, signal, err := util.WaitPidfd(p.pidfd)
+	if errors.Is(err, unix.ECHILD) {
+		logrus.Warnf("container {%s} is not a child of mini-dockerd, its exit status is unknown", p.id)
+		exitCode, signal, err = unknownExitCode, 0, nil
+	}
+	if err != nil {
+		logrus.Errorf("error reap container {%s}, pid %d: %v", p.id, p.pid, err)
+		return
+	}
+	request := entity.ExitRequest{Id: p.id, Pid: p.pid, ExitCode: exitCode, Signal: signal}
+	if err = exitContainer(request); err != nil {
+		logrus.Errorf("error update exit of container {%s}: %v", p.id, err)
+	}
+}
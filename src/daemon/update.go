@@ -0,0 +1,141 @@
+package daemon
+
+import (
+	"cmp"
+	"fmt"
+	"path/filepath"
+	"strconv"
+
+	"github.com/0x822a5b87/tiny-docker/src/conf"
+	"github.com/0x822a5b87/tiny-docker/src/constant"
+	"github.com/0x822a5b87/tiny-docker/src/subsystem"
+	"github.com/0x822a5b87/tiny-docker/src/subsystem/manager"
+	"github.com/0x822a5b87/tiny-docker/src/util"
+	"github.com/sirupsen/logrus"
+)
+
+// minMemoryLimit is the smallest memory limit docker accepts, a container can hardly start below it.
+const minMemoryLimit = 6 * 1024 * 1024
+
+// updateContainer changes the resource limits of a container. The limits are written to the
+// cgroup of a running container right away and kept in its spec, so the next start applies them
+// as well.
+func updateContainer(command conf.UpdateCommand) error {
+	c, err := resolveContainer(command.Id)
+	if err != nil {
+		return err
+	}
+	spec, err := readContainerSpec(c.Id)
+	if err != nil {
+		return err
+	}
+	cgroup := containerCgroup(c)
+	cfg, err := updatedCgroupConfig(cgroup, spec.Cfg, command)
+	if err != nil {
+		return err
+	}
+
+	if c.Status.Alive() {
+		if err = applyCgroupConfig(cgroup, cfg); err != nil {
+			logrus.Errorf("error update cgroup of container {%s}: %v", c.Id, err)
+			return err
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	// the spec may be read by a start in the meantime, but it is only written here
+	if spec, err = readContainerSpec(c.Id); err != nil {
+		return err
+	}
+	spec.Cfg = cfg
+	if err = writeContainerSpec(*spec); err != nil {
+		return err
+	}
+	logrus.Infof("Update container {%s}: %+v", c.Id, cfg)
+	return nil
+}
+
+// updatedCgroupConfig validates the limits of the update and merges them into cfg, an empty
+// limit keeps the current one.
+func updatedCgroupConfig(cgroup string, cfg conf.CgroupConfig, command conf.UpdateCommand) (conf.CgroupConfig, error) {
+	if command.Memory != "" {
+		bytes, err := subsystem.SizeToBytes(command.Memory)
+		if err != nil {
+			return cfg, constant.ErrMalformedArgs.WrapMessage(fmt.Sprintf("invalid memory %s: %v", command.Memory, err))
+		}
+		if bytes < minMemoryLimit {
+			return cfg, constant.ErrMalformedArgs.WrapMessage("minimum memory limit allowed is 6MB")
+		}
+		cfg.MemoryLimit = command.Memory
+	}
+	cfg.MemorySwap = cmp.Or(command.MemorySwap, cfg.MemorySwap)
+	cfg.MemoryReservation = cmp.Or(command.MemoryReservation, cfg.MemoryReservation)
+	if command.Cpus != "" {
+		cpuMax, err := conf.ParseCpus(command.Cpus)
+		if err != nil {
+			return cfg, err
+		}
+		cfg.CpuMax = cpuMax
+	}
+	if command.CpuShares != "" {
+		shares, err := strconv.ParseInt(command.CpuShares, 10, 64)
+		if err != nil {
+			return cfg, constant.ErrMalformedArgs.WrapMessage(fmt.Sprintf("invalid cpu shares %s", command.CpuShares))
+		}
+		if cfg.CpuWeight, err = conf.CpuSharesToWeight(shares); err != nil {
+			return cfg, err
+		}
+	}
+	if command.CpusetCpus != "" || command.CpusetMems != "" {
+		if err := manager.ValidateCpuset(filepath.Dir(cgroup), command.CpusetCpus, command.CpusetMems); err != nil {
+			return cfg, err
+		}
+		cfg.CpusetCpus = cmp.Or(command.CpusetCpus, cfg.CpusetCpus)
+		cfg.CpusetMems = cmp.Or(command.CpusetMems, cfg.CpusetMems)
+	}
+	if command.PidsLimit != "" {
+		limit, err := strconv.ParseInt(command.PidsLimit, 10, 64)
+		if err != nil {
+			return cfg, constant.ErrMalformedArgs.WrapMessage(fmt.Sprintf("invalid pids limit %s", command.PidsLimit))
+		}
+		cfg.PidsLimit = limit
+	}
+	return cfg, cfg.ValidateMemory()
+}
+
+// validateCgroupConfig checks the limits a container is created with in cgroup, so that they do not
+// fail its start.
+func validateCgroupConfig(cgroup string, cfg conf.CgroupConfig) error {
+	if err := cfg.ValidateMemory(); err != nil {
+		return err
+	}
+	if err := conf.ValidateCpuWeight(cfg.CpuWeight); err != nil {
+		return err
+	}
+	if err := manager.ValidateCpuset(filepath.Dir(cgroup), cfg.CpusetCpus, cfg.CpusetMems); err != nil {
+		return err
+	}
+	if err := conf.ValidateBlkioWeight(cfg.BlkioWeight); err != nil {
+		return err
+	}
+	for _, devices := range [][]conf.ThrottleDevice{cfg.DeviceReadBps, cfg.DeviceWriteBps, cfg.DeviceReadIOps, cfg.DeviceWriteIOps} {
+		for _, device := range devices {
+			if _, _, err := util.BlockDeviceNumber(device.Path); err != nil {
+				return constant.ErrMalformedArgs.Wrap(err)
+			}
+		}
+	}
+	return nil
+}
+
+func applyCgroupConfig(cgroup string, cfg conf.CgroupConfig) error {
+	cgroupManager, err := manager.LoadCgroupManager(cgroup)
+	if err != nil {
+		return err
+	}
+	if err = setConf(cgroupManager, cfg); err != nil {
+		return err
+	}
+	return cgroupManager.SyncLimits()
+}
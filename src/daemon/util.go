@@ -25,19 +25,19 @@ func setupUnionFsFromEnv() error {
 	return nil
 }
 
-//func setupUnionFsFromConfig() error {
-//	readPath := conf.GlobalConfig.ReadPath()
-//	writePath := conf.GlobalConfig.WritePath()
-//	workPath := conf.GlobalConfig.WorkPath()
-//	mergePath := conf.GlobalConfig.MergePath()
-//	logrus.Infof("read path: {%s}, write path: {%s}, work path: {%s}, merge path : {%s}", readPath, writePath, workPath, mergePath)
-//	// mount -t overlay overlay -o lowerdir=...,upperdir=...,workdir=... /root/tiny-docker/busybox/merged
-//	if err := util.MountOverlayFS(readPath, writePath, workPath, mergePath); err != nil {
-//		logrus.Errorf("mount proc error : %s", err.Error())
-//		return err
-//	}
-//	return nil
-//}
+func setupUnionFsFromConfig() error {
+	readPath := conf.GlobalConfig.ReadPath()
+	writePath := conf.GlobalConfig.WritePath()
+	workPath := conf.GlobalConfig.WorkPath()
+	mergePath := conf.GlobalConfig.MergePath()
+	logrus.Infof("read path: {%s}, write path: {%s}, work path: {%s}, merge path : {%s}", readPath, writePath, workPath, mergePath)
+	// mount -t overlay overlay -o lowerdir=...,upperdir=...,workdir=... /root/tiny-docker/busybox/merged
+	if err := util.MountOverlayFS(readPath, writePath, workPath, mergePath); err != nil {
+		logrus.Errorf("mount proc error : %s", err.Error())
+		return err
+	}
+	return nil
+}
 
 func clearUnionFsFromConfig() error {
 	mergePath := conf.GlobalConfig.MergePath()
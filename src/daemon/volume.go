@@ -0,0 +1,246 @@
+package daemon
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/0x822a5b87/tiny-docker/src/conf"
+	"github.com/0x822a5b87/tiny-docker/src/constant"
+	"github.com/0x822a5b87/tiny-docker/src/entity"
+	"github.com/0x822a5b87/tiny-docker/src/util"
+	"github.com/0x822a5b87/tiny-docker/src/volume"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+)
+
+// propagationFlags are the mount flags which set the propagation of a volume.
+var propagationFlags = map[conf.Propagation]uintptr{
+	conf.PropagationPrivate:  unix.MS_PRIVATE,
+	conf.PropagationRPrivate: unix.MS_PRIVATE | unix.MS_REC,
+	conf.PropagationShared:   unix.MS_SHARED,
+	conf.PropagationRShared:  unix.MS_SHARED | unix.MS_REC,
+	conf.PropagationSlave:    unix.MS_SLAVE,
+	conf.PropagationRSlave:   unix.MS_SLAVE | unix.MS_REC,
+}
+
+func VolumeCreate(command conf.VolumeCommand) (*entity.Volume, error) {
+	conf.LoadBasicCommand()
+	return volumes.CreateVolume(command.Name, command.Driver, command.Options)
+}
+
+func VolumeRm(name string) (*entity.Volume, error) {
+	conf.LoadBasicCommand()
+	return volumes.RemoveVolume(name)
+}
+
+func VolumeInspect(name string) (*entity.Volume, error) {
+	conf.LoadBasicCommand()
+	return volumes.GetVolume(name)
+}
+
+func VolumeList() ([]*entity.Volume, error) {
+	conf.LoadBasicCommand()
+	return volumes.GetVolumes()
+}
+
+func VolumePrune(all bool) ([]*entity.Volume, error) {
+	conf.LoadBasicCommand()
+	return volumes.PruneVolumes(all)
+}
+
+// acquireVolumes references the named volumes a container mounts for as long as it exists,
+// creating those which do not exist yet.
+func acquireVolumes(id entity.ContainerId, mounts []conf.Mount) error {
+	for i, m := range mounts {
+		if m.Name == "" {
+			continue
+		}
+		if _, err := volumes.Acquire(m.Name, m.Anonymous, id); err != nil {
+			_ = releaseVolumes(id, mounts[:i], true)
+			return err
+		}
+	}
+	return nil
+}
+
+// releaseVolumes drops the references of a container to its named volumes, its anonymous
+// volumes are removed as well if removeAnonymous is set.
+func releaseVolumes(id entity.ContainerId, mounts []conf.Mount, removeAnonymous bool) error {
+	var errs []error
+	for _, m := range mounts {
+		if m.Name == "" {
+			continue
+		}
+		if err := volumes.Release(m.Name, id, removeAnonymous); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// tmpfsFlags are the mount flags set and cleared by the options of a tmpfs.
+var tmpfsFlags = map[string]struct{ set, clear uintptr }{
+	"ro":      {set: unix.MS_RDONLY},
+	"rw":      {clear: unix.MS_RDONLY},
+	"exec":    {clear: unix.MS_NOEXEC},
+	"noexec":  {set: unix.MS_NOEXEC},
+	"suid":    {clear: unix.MS_NOSUID},
+	"nosuid":  {set: unix.MS_NOSUID},
+	"dev":     {clear: unix.MS_NODEV},
+	"nodev":   {set: unix.MS_NODEV},
+	"noatime": {set: unix.MS_NOATIME},
+}
+
+// setupVolumes mounts the volumes and the tmpfs of the container below root, the merged
+// directory of its overlay, so that they move along with root when the container pivots into
+// it. They are mounted from the top down, so that a tmpfs does not hide a volume below it.
+func setupVolumes(root string) error {
+	mounts, err := conf.MountsFromEnv()
+	if err != nil {
+		return constant.ErrMountVolume.Wrap(err)
+	}
+	tmpfs, err := conf.TmpfsFromEnv()
+	if err != nil {
+		return constant.ErrMountVolume.Wrap(err)
+	}
+	type containerMount struct {
+		destination string
+		mount       func() error
+	}
+	all := make([]containerMount, 0, len(mounts)+len(tmpfs))
+	for _, m := range mounts {
+		// a named volume is resolved when the container starts
+		if m.Name != "" {
+			m.Source = volume.Mountpoint(m.Name)
+		}
+		all = append(all, containerMount{m.Destination, func() error {
+			if err := mountVolume(root, m); err != nil {
+				logrus.Errorf("error mount volume %s at %s: %v", m.Source, m.Destination, err)
+				return constant.ErrMountVolume.WrapMessage(fmt.Sprintf("%s at %s: %v", m.Source, m.Destination, err))
+			}
+			logrus.Infof("mount volume %s at %s, read only {%v}", m.Source, m.Destination, m.ReadOnly)
+			return nil
+		}})
+	}
+	for _, t := range tmpfs {
+		all = append(all, containerMount{t.Destination, func() error {
+			if err := mountTmpfs(root, t); err != nil {
+				logrus.Errorf("error mount tmpfs at %s: %v", t.Destination, err)
+				return constant.ErrMountVolume.WrapMessage(fmt.Sprintf("tmpfs at %s: %v", t.Destination, err))
+			}
+			logrus.Infof("mount tmpfs at %s, options {%v}", t.Destination, t.Options)
+			return nil
+		}})
+	}
+	slices.SortStableFunc(all, func(a, b containerMount) int {
+		return cmp.Compare(strings.Count(a.destination, "/"), strings.Count(b.destination, "/"))
+	})
+	for _, m := range all {
+		if err = m.mount(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func mountVolume(root string, m conf.Mount) error {
+	info, err := os.Stat(m.Source)
+	if os.IsNotExist(err) && m.Name == "" {
+		// a missing host path is created as a directory, like docker does, a missing volume is
+		// an error since mini-dockerd created it along with the container
+		if err = os.MkdirAll(m.Source, 0755); err != nil {
+			return err
+		}
+		info, err = os.Stat(m.Source)
+	}
+	if err != nil {
+		return err
+	}
+	// the destination is resolved in the image, whose symlinks must not lead onto the host
+	target, err := util.SecureJoin(root, m.Destination)
+	if err != nil {
+		return err
+	}
+	if m.Name != "" {
+		if err = copyUp(target, m.Source); err != nil {
+			return err
+		}
+	}
+	if err = createMountPoint(target, info.IsDir()); err != nil {
+		return err
+	}
+
+	if err = unix.Mount(m.Source, target, "", unix.MS_BIND|unix.MS_REC, ""); err != nil {
+		return err
+	}
+	if m.ReadOnly {
+		// a bind mount takes the flags of its source, only a remount makes it read only
+		if err = unix.Mount("", target, "", unix.MS_BIND|unix.MS_REMOUNT|unix.MS_RDONLY, ""); err != nil {
+			return err
+		}
+	}
+	flags, ok := propagationFlags[m.Propagation]
+	if !ok {
+		flags = propagationFlags[conf.PropagationRPrivate]
+	}
+	return unix.Mount("", target, "", flags, "")
+}
+
+func mountTmpfs(root string, t conf.Tmpfs) error {
+	target, err := util.SecureJoin(root, t.Destination)
+	if err != nil {
+		return err
+	}
+	if err = createMountPoint(target, true); err != nil {
+		return err
+	}
+	var flags uintptr
+	data := make([]string, 0, len(t.Options))
+	for _, option := range t.Options {
+		if f, ok := tmpfsFlags[option]; ok {
+			// a later option overrides an earlier one, like noexec by exec
+			flags = flags&^f.clear | f.set
+			continue
+		}
+		data = append(data, option)
+	}
+	return unix.Mount("tmpfs", target, "tmpfs", flags, strings.Join(data, ","))
+}
+
+// createMountPoint creates the directory or the empty file a volume is mounted on.
+func createMountPoint(target string, dir bool) error {
+	if dir {
+		return os.MkdirAll(target, 0755)
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(target, os.O_CREATE|os.O_RDONLY, 0644)
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// copyUp fills an empty named volume with what the image has at its mount point, like docker
+// does, so that mounting a fresh volume does not hide the content of the image.
+func copyUp(target, source string) error {
+	info, err := os.Stat(target)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil || !info.IsDir() {
+		return err
+	}
+	entries, err := os.ReadDir(source)
+	if err != nil || len(entries) > 0 {
+		return err
+	}
+	logrus.Infof("copy %s of the image into volume %s", target, source)
+	return util.CopyDir(target, source)
+}
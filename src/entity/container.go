@@ -2,25 +2,53 @@ package entity
 
 type ContainerStatus string
 
+var ContainerCreated ContainerStatus = "created"
 var ContainerRunning ContainerStatus = "running"
 var ContainerExit ContainerStatus = "exit"
 
+// ContainerPaused is a running container whose processes are frozen by the cgroup freezer.
+var ContainerPaused ContainerStatus = "paused"
+
+// ContainerRestarting is a container which exited and waits to be started again by its restart policy.
+var ContainerRestarting ContainerStatus = "restarting"
+
+// Alive reports whether the process of the container exists, which is the case for a paused one.
+func (s ContainerStatus) Alive() bool {
+	return s == ContainerRunning || s == ContainerPaused
+}
+
 type ContainerId string
 
 type Container struct {
-	Id        ContainerId     `json:"id"`
-	Pid       int             `json:"pid"`
-	Image     string          `json:"image"`
-	Command   string          `json:"command"`
-	CreatedAt int64           `json:"created_at"`
-	ExitAt    int64           `json:"exit_at"`
-	Status    ContainerStatus `json:"status"`
-	Name      string          `json:"name"`
+	Id           ContainerId     `json:"id"`
+	Pid          int             `json:"pid"`
+	Image        string          `json:"image"`
+	Command      string          `json:"command"`
+	CreatedAt    int64           `json:"created_at"`
+	StartedAt    int64           `json:"started_at"`
+	ExitAt       int64           `json:"exit_at"`
+	Status       ContainerStatus `json:"status"`
+	Name         string          `json:"name"`
+	ExitCode     int             `json:"exit_code"`
+	Signal       int             `json:"signal"`
+	OOMKilled    bool            `json:"oom_killed"`
+	OOMKillCount int64           `json:"oom_kill_count"`
+	RestartCount int             `json:"restart_count"`
+	// ManuallyStopped is set when the user stopped the container, its restart policy is ignored
+	// until the container is started again.
+	ManuallyStopped bool `json:"manually_stopped"`
+	// Cgroup is the cgroup of the container relative to the root of a hierarchy, it is chosen by
+	// the cgroup driver when the container is created.
+	Cgroup string `json:"cgroup,omitempty"`
+	// PidsCurrent is the number of processes of a running container, it is only filled in by inspect.
+	PidsCurrent int64 `json:"pids_current,omitempty"`
 }
 
-// WaitRequest this request is used to indicate that a detached process is running, and
-// requires the daemon process to wait for it.
-type WaitRequest struct {
-	Id  ContainerId `json:"id"`
-	Pid int         `json:"pid"`
+// ExitRequest reports how the process of a container terminated. ExitCode follows the shell
+// convention, a process killed by a signal exits with 128 + signal.
+type ExitRequest struct {
+	Id       ContainerId `json:"id"`
+	Pid      int         `json:"pid"`
+	ExitCode int         `json:"exit_code"`
+	Signal   int         `json:"signal"`
 }
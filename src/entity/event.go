@@ -0,0 +1,20 @@
+package entity
+
+type EventAction string
+
+const (
+	EventStart EventAction = "start"
+	EventDie   EventAction = "die"
+	// EventOom is published when the OOM killer killed a process of a container.
+	EventOom EventAction = "oom"
+)
+
+// Event is something that happened to a container, like an event of docker events.
+type Event struct {
+	Action EventAction `json:"action"`
+	Id     ContainerId `json:"id"`
+	Name   string      `json:"name"`
+	// Time is the unix time of the event in milliseconds.
+	Time       int64             `json:"time"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
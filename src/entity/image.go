@@ -0,0 +1,7 @@
+package entity
+
+type Image struct {
+	Name      string `json:"name"`
+	CreatedAt int64  `json:"created_at"`
+	Size      int64  `json:"size"`
+}
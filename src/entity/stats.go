@@ -0,0 +1,18 @@
+package entity
+
+// ContainerStats is a sample of the resource usage of a container, like a line of docker stats.
+type ContainerStats struct {
+	Id   ContainerId `json:"id"`
+	Name string      `json:"name"`
+	// CpuPercent is the cpu time used since the previous sample, 100 is a whole cpu.
+	CpuPercent float64 `json:"cpu_percent"`
+	// MemoryUsage leaves out the inactive page cache, which the kernel reclaims first.
+	MemoryUsage   uint64  `json:"memory_usage"`
+	MemoryLimit   uint64  `json:"memory_limit"`
+	MemoryPercent float64 `json:"memory_percent"`
+	NetRx         uint64  `json:"net_rx"`
+	NetTx         uint64  `json:"net_tx"`
+	BlockRead     uint64  `json:"block_read"`
+	BlockWrite    uint64  `json:"block_write"`
+	Pids          int64   `json:"pids"`
+}
@@ -0,0 +1,24 @@
+package entity
+
+const (
+	VolumeLocal = "local"
+	VolumeLoop  = "loop"
+)
+
+type Volume struct {
+	Name       string `json:"name"`
+	Driver     string `json:"driver"`
+	Mountpoint string `json:"mountpoint"`
+	CreatedAt  int64  `json:"created_at"`
+	// Options are the options of the driver given with --opt, like size for the loop driver.
+	Options map[string]string `json:"options,omitempty"`
+	// Anonymous volumes are created for -v /container/path, rm -v removes them along with their container.
+	Anonymous bool `json:"anonymous,omitempty"`
+	// Containers are the containers which mount the volume, it cannot be removed while there are any.
+	Containers []ContainerId `json:"containers,omitempty"`
+}
+
+// InUse reports whether a container mounts the volume.
+func (v *Volume) InUse() bool {
+	return len(v.Containers) > 0
+}
@@ -1,51 +1,95 @@
 package handler
 
 import (
-	"encoding/json"
+	"bufio"
+	"context"
 	"net"
+	"sync/atomic"
+	"time"
 
-	"github.com/0x822a5b87/tiny-docker/src/conf"
 	"github.com/0x822a5b87/tiny-docker/src/constant"
 	"github.com/sirupsen/logrus"
 )
 
-func SendRequest(req *Request) (error, Response) {
-	if !isUdsServerRunning() {
-		logrus.Fatalf("UDS server is not running")
-		return constant.ErrIllegalUdsServerStatus, Response{}
-	}
+var requestId atomic.Uint64
 
-	// connect client
-	conn, err := net.DialUnix(constant.OS, nil, &net.UnixAddr{
-		Name: conf.RuntimeDockerdUdsFile.Get(),
-		Net:  constant.OS,
-	})
+// Conn is a client connection to mini-dockerd which has already completed the handshake.
+type Conn struct {
+	conn   *net.UnixConn
+	reader *bufio.Reader
+}
+
+func Dial(ctx context.Context, udsPath string) (*Conn, error) {
+	var dialer net.Dialer
+	c, err := dialer.DialContext(ctx, constant.OS, udsPath)
 	if err != nil {
-		logrus.Errorf("failed to dial mini-dockerd: %v\n", err.Error())
-		return err, Response{}
+		return nil, err
+	}
+	conn := &Conn{conn: c.(*net.UnixConn), reader: bufio.NewReader(c)}
+	if err = conn.handshake(ctx); err != nil {
+		_ = conn.Close()
+		return nil, err
 	}
-	defer conn.Close()
+	return conn, nil
+}
 
-	reqData, _ := json.Marshal(req)
-	_, err = conn.Write(reqData)
-	if err != nil {
-		logrus.Errorf("failed to send uds request: %v\n", err.Error())
-		return err, Response{}
+func (c *Conn) Close() error {
+	return c.conn.Close()
+}
+
+// Do sends req and invokes fn for every response frame of it, the last frame has More unset.
+// A non-nil error returned by fn aborts the call.
+func (c *Conn) Do(ctx context.Context, req *Request, fn func(Response) error) error {
+	stop := c.watch(ctx)
+	defer stop()
+
+	req.Id = requestId.Add(1)
+	if err := writeFrame(c.conn, req); err != nil {
+		return c.ctxErr(ctx, err)
 	}
 
-	buf := make([]byte, 1024*10)
-	n, err := conn.Read(buf)
-	if err != nil {
-		logrus.Errorf("failed to read uds response: %v\n", err)
-		return err, Response{}
+	for {
+		var rsp Response
+		if err := readFrame(c.reader, &rsp); err != nil {
+			return c.ctxErr(ctx, err)
+		}
+		if rsp.Id != req.Id {
+			logrus.Warnf("drop response frame of unknown request: %d", rsp.Id)
+			continue
+		}
+		if err := fn(rsp); err != nil {
+			return err
+		}
+		if !rsp.More {
+			return nil
+		}
 	}
+}
 
-	var rsp Response
-	err = json.Unmarshal(buf[:n], &rsp)
-	if err != nil {
-		logrus.Errorf("error unmarshal uds response: %v\n", err.Error())
-		return err, Response{}
+func (c *Conn) handshake(ctx context.Context) error {
+	stop := c.watch(ctx)
+	defer stop()
+
+	if err := writeFrame(c.conn, Hello{Version: ProtocolVersion}); err != nil {
+		return c.ctxErr(ctx, err)
+	}
+	var hello Hello
+	if err := readFrame(c.reader, &hello); err != nil {
+		return c.ctxErr(ctx, err)
 	}
+	return checkHello(hello)
+}
 
-	return nil, rsp
+// watch interrupts any blocking read or write on the connection once ctx is done.
+func (c *Conn) watch(ctx context.Context) (stop func() bool) {
+	return context.AfterFunc(ctx, func() {
+		_ = c.conn.SetDeadline(time.Now())
+	})
+}
+
+func (c *Conn) ctxErr(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
 }
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/0x822a5b87/tiny-docker/src/constant"
+)
+
+// Every message exchanged on dockerd.sock is a frame: a 4-byte big-endian payload length
+// followed by exactly that many bytes of JSON.
+//
+// A connection starts with a Hello frame from each side. After that the client writes Request
+// frames and the server answers each of them with one or more Response frames carrying the same
+// request id; every frame but the last one has More set.
+const (
+	ProtocolVersion = 1
+
+	frameHeaderSize = 4
+	maxFrameSize    = 16 * 1024 * 1024
+)
+
+type Hello struct {
+	Version int `json:"version"`
+}
+
+func writeFrame(w io.Writer, v any) error {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if len(payload) > maxFrameSize {
+		return constant.ErrFrameTooLarge
+	}
+	frame := make([]byte, frameHeaderSize+len(payload))
+	binary.BigEndian.PutUint32(frame, uint32(len(payload)))
+	copy(frame[frameHeaderSize:], payload)
+	_, err = w.Write(frame)
+	return err
+}
+
+func readFrame(r io.Reader, v any) error {
+	header := make([]byte, frameHeaderSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return err
+	}
+	size := binary.BigEndian.Uint32(header)
+	if size > maxFrameSize {
+		return constant.ErrFrameTooLarge
+	}
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		if err == io.EOF {
+			return io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	return json.Unmarshal(payload, v)
+}
+
+func checkHello(hello Hello) error {
+	if hello.Version != ProtocolVersion {
+		return constant.ErrProtocolVersion.WrapMessage(fmt.Sprintf("expected %d, got %d", ProtocolVersion, hello.Version))
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/0x822a5b87/tiny-docker/src/constant"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFrameRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	req := Request{Id: 7, Act: constant.Ps, Params: strings.Repeat("x", 64*1024)}
+	assert.NoError(t, writeFrame(&buf, req))
+
+	var got Request
+	assert.NoError(t, readFrame(&buf, &got))
+	assert.Equal(t, req, got)
+
+	buf.Reset()
+	buf.Write([]byte{0xff, 0xff, 0xff, 0xff})
+	assert.ErrorIs(t, readFrame(&buf, &got), constant.ErrFrameTooLarge)
+}
+
+func TestHandshakeVersion(t *testing.T) {
+	assert.NoError(t, checkHello(Hello{Version: ProtocolVersion}))
+	assert.Error(t, checkHello(Hello{Version: ProtocolVersion + 1}))
+}
+
+func TestUnaryAndStreamRequest(t *testing.T) {
+	const unaryAct constant.Action = "__test_unary__"
+	const streamAct constant.Action = "__test_stream__"
+	AddHandler(unaryAct, func(req Request) (Response, error) {
+		params, err := ParamsFromRequest[[]string](&req)
+		if err != nil {
+			return ErrorResponse(err, constant.ErrMalformedUdsReq)
+		}
+		return SuccessResponse(params)
+	})
+	AddStreamHandler(streamAct, func(req Request, stream *Stream) (Response, error) {
+		for i := 0; i < 3; i++ {
+			if err := stream.Send(i); err != nil {
+				return ErrorResponse(err, constant.ErrMalformedUdsRsp)
+			}
+		}
+		return SuccessResponse(3)
+	})
+
+	udsPath := filepath.Join(t.TempDir(), "test.sock")
+	listener, err := net.ListenUnix(constant.OS, &net.UnixAddr{Name: udsPath, Net: constant.OS})
+	assert.NoError(t, err)
+	defer func() { _ = listener.Close() }()
+	go func() {
+		for {
+			conn, err := listener.AcceptUnix()
+			if err != nil {
+				return
+			}
+			go handleClient(conn)
+		}
+	}()
+
+	ctx := context.Background()
+	conn, err := Dial(ctx, udsPath)
+	assert.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+
+	// a payload far larger than any single read used to accept
+	env := make([]string, 0)
+	for i := 0; i < 5000; i++ {
+		env = append(env, "KEY=VALUE")
+	}
+	req, err := ParamsIntoRequest(unaryAct, env)
+	assert.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		var rsps []Response
+		err = conn.Do(ctx, req, func(rsp Response) error {
+			rsps = append(rsps, rsp)
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Len(t, rsps, 1)
+		assert.Equal(t, req.Id, rsps[0].Id)
+		data, err := DataFromResponse[[]string](rsps[0])
+		assert.NoError(t, err)
+		assert.Equal(t, env, data)
+	}
+
+	req, err = ParamsIntoRequest(streamAct, struct{}{})
+	assert.NoError(t, err)
+	values := make([]int, 0)
+	err = conn.Do(ctx, req, func(rsp Response) error {
+		v, err := DataFromResponse[int](rsp)
+		values = append(values, v)
+		return err
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 1, 2, 3}, values)
+}
@@ -8,10 +8,18 @@ import (
 
 type ActionHandler func(req Request) (rsp Response, err error)
 
+// StreamHandler serves a request whose result is delivered as a sequence of frames. Intermediate
+// results are pushed with Stream.Send, the returned Response is sent as the last frame.
+type StreamHandler func(req Request, stream *Stream) (rsp Response, err error)
+
 func AddHandler(action constant.Action, ac ActionHandler) {
 	registry[action] = ac
 }
 
+func AddStreamHandler(action constant.Action, sh StreamHandler) {
+	streamRegistry[action] = sh
+}
+
 func handleRequest(req Request) (Response, error) {
 	h, ok := registry[req.Act]
 	if !ok {
@@ -19,3 +27,8 @@ func handleRequest(req Request) (Response, error) {
 	}
 	return h(req)
 }
+
+func isStreamRequest(req Request) bool {
+	_, ok := streamRegistry[req.Act]
+	return ok
+}
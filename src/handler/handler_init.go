@@ -3,7 +3,9 @@ package handler
 import "github.com/0x822a5b87/tiny-docker/src/constant"
 
 var registry map[constant.Action]ActionHandler
+var streamRegistry map[constant.Action]StreamHandler
 
 func init() {
 	registry = make(map[constant.Action]ActionHandler)
+	streamRegistry = make(map[constant.Action]StreamHandler)
 }
@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"net"
+	"net/http"
+	"regexp"
+	"sync"
+
+	"github.com/0x822a5b87/tiny-docker/src/constant"
+	"github.com/sirupsen/logrus"
+)
+
+// The HTTP API shares dockerd.sock with the framed protocol, a connection is handed over to the
+// HTTP server when its first bytes look like an HTTP method.
+var httpMethodPrefixes = []string{"GET ", "HEAD", "POST", "PUT ", "DELE", "PATC", "OPTI"}
+
+// Docker clients prefix every path with the API version they speak, e.g. /v1.43/containers/json.
+var apiVersionPrefix = regexp.MustCompile(`^/v[0-9]+\.[0-9]+/`)
+
+var router = http.NewServeMux()
+
+var apiListener *connListener
+
+func AddRoute(pattern string, h http.HandlerFunc) {
+	router.HandleFunc(pattern, h)
+}
+
+// Dispatch runs an action through the same registry as the UDS protocol so that the HTTP API
+// does not duplicate any of the daemon's logic.
+func Dispatch[D any, T any](act constant.Action, data D) (T, error) {
+	var t T
+	req, err := ParamsIntoRequest(act, data)
+	if err != nil {
+		return t, err
+	}
+	rsp, _ := handleRequest(*req)
+	if err = rsp.Err(); err != nil {
+		return t, err
+	}
+	return DataFromResponse[T](rsp)
+}
+
+func WriteJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		logrus.Errorf("error write http response: %v", err)
+	}
+}
+
+// WriteError replies with the error body used by the Docker Engine API.
+func WriteError(w http.ResponseWriter, err error) {
+	WriteJSON(w, httpStatus(err), map[string]string{"message": err.Error()})
+}
+
+func httpStatus(err error) int {
+	switch {
+	case errors.Is(err, constant.ErrResourceNotFound), errors.Is(err, constant.ErrResourceNotExists),
+		errors.Is(err, constant.ErrContainerNotFound), errors.Is(err, constant.ErrVolumeNotFound),
+		errors.Is(err, fs.ErrNotExist):
+		return http.StatusNotFound
+	case errors.Is(err, constant.ErrResourceExists), errors.Is(err, constant.ErrDeviceIsBusy),
+		errors.Is(err, constant.ErrIllegalContainerStatus), errors.Is(err, constant.ErrContainerNameInUse),
+		errors.Is(err, constant.ErrVolumeExists), errors.Is(err, constant.ErrVolumeInUse):
+		return http.StatusConflict
+	case errors.Is(err, constant.ErrMalformedUdsReq), errors.Is(err, constant.ErrMalformedArgs),
+		errors.Is(err, constant.ErrInvalidContainerName), errors.Is(err, constant.ErrAmbiguousContainer),
+		errors.Is(err, constant.ErrInvalidRestartPolicy), errors.Is(err, constant.ErrConflictingOptions),
+		errors.Is(err, constant.ErrInvalidVolume), errors.Is(err, constant.ErrInvalidDevice):
+		return http.StatusBadRequest
+	case errors.Is(err, constant.ErrUnsupportedAction):
+		return http.StatusNotImplemented
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func serveHttp(listener *connListener) {
+	server := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r.URL.Path = apiVersionPrefix.ReplaceAllString(r.URL.Path, "/")
+			router.ServeHTTP(w, r)
+		}),
+	}
+	if err := server.Serve(listener); err != nil && !errors.Is(err, net.ErrClosed) {
+		logrus.Errorf("http server exited: %v", err)
+	}
+}
+
+func isHttpRequest(reader *bufio.Reader) bool {
+	prefix, err := reader.Peek(4)
+	if err != nil {
+		return false
+	}
+	for _, method := range httpMethodPrefixes {
+		if string(prefix) == method {
+			return true
+		}
+	}
+	return false
+}
+
+// bufferedConn keeps the bytes that were peeked while sniffing the protocol.
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
+
+// connListener is a net.Listener fed with connections accepted by the UDS server.
+type connListener struct {
+	addr  net.Addr
+	conns chan net.Conn
+	done  chan struct{}
+	once  sync.Once
+}
+
+func newConnListener(addr net.Addr) *connListener {
+	return &connListener{
+		addr:  addr,
+		conns: make(chan net.Conn),
+		done:  make(chan struct{}),
+	}
+}
+
+func (l *connListener) push(conn net.Conn) {
+	select {
+	case l.conns <- conn:
+	case <-l.done:
+		_ = conn.Close()
+	}
+}
+
+func (l *connListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.done:
+		return nil, net.ErrClosed
+	}
+}
+
+func (l *connListener) Close() error {
+	l.once.Do(func() { close(l.done) })
+	return nil
+}
+
+func (l *connListener) Addr() net.Addr {
+	return l.addr
+}
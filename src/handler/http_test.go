@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/0x822a5b87/tiny-docker/src/constant"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHttpAndFramesShareSocket(t *testing.T) {
+	const act constant.Action = "__test_http__"
+	AddHandler(act, func(req Request) (Response, error) {
+		return ErrorResponse(constant.ErrResourceNotFound, constant.ErrResourceNotFound)
+	})
+	AddRoute("GET /test/{name}", func(w http.ResponseWriter, r *http.Request) {
+		if _, err := Dispatch[string, string](act, r.PathValue("name")); err != nil {
+			WriteError(w, err)
+			return
+		}
+		WriteJSON(w, http.StatusOK, "unreachable")
+	})
+
+	udsPath := filepath.Join(t.TempDir(), "test.sock")
+	listener, err := net.ListenUnix(constant.OS, &net.UnixAddr{Name: udsPath, Net: constant.OS})
+	assert.NoError(t, err)
+	defer func() { _ = listener.Close() }()
+	apiListener = newConnListener(listener.Addr())
+	defer func() { _ = apiListener.Close() }()
+	go serveHttp(apiListener)
+	go func() {
+		for {
+			conn, err := listener.AcceptUnix()
+			if err != nil {
+				return
+			}
+			go handleClient(conn)
+		}
+	}()
+
+	client := http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, constant.OS, udsPath)
+		},
+	}}
+	rsp, err := client.Get("http://tiny-docker/v1.43/test/foo")
+	assert.NoError(t, err)
+	body, _ := io.ReadAll(rsp.Body)
+	_ = rsp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, rsp.StatusCode)
+	assert.Contains(t, string(body), "message")
+
+	// the framed protocol still works on the same socket
+	conn, err := Dial(context.Background(), udsPath)
+	assert.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+	req, err := ParamsIntoRequest(act, "foo")
+	assert.NoError(t, err)
+	err = conn.Do(context.Background(), req, func(rsp Response) error {
+		return rsp.Err()
+	})
+	assert.ErrorIs(t, err, constant.ErrResourceNotFound)
+}
@@ -7,6 +7,7 @@ import (
 )
 
 type Request struct {
+	Id     uint64          `json:"id"`
 	Act    constant.Action `json:"act"`
 	Params string          `json:"params"`
 }
@@ -2,15 +2,19 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
 
 	"github.com/0x822a5b87/tiny-docker/src/constant"
 )
 
 type Response struct {
+	Id   uint64 `json:"id"`
 	Code int    `json:"code"` // 0=成功，非0=失败
 	Msg  string `json:"msg"`
 	Data any    `json:"data"`
+	More bool   `json:"more"` // true if more frames of the same request follow
 }
 
 func DataIntoResponse[T any](code int, msg string, data T) (Response, error) {
@@ -29,15 +33,17 @@ func DataIntoResponse[T any](code int, msg string, data T) (Response, error) {
 func DataFromResponse[T any](rsp Response) (T, error) {
 	var t T
 	var dataBytes []byte
+	var rawJSON string
+	isJSONString := false
 
 	switch v := rsp.Data.(type) {
 	case string:
-		var rawJSON string
 		err := json.Unmarshal([]byte(v), &rawJSON)
 		if err != nil {
 			dataBytes = []byte(v)
 		} else {
 			dataBytes = []byte(rawJSON)
+			isJSONString = true
 		}
 	case []byte:
 		dataBytes = v
@@ -47,6 +53,10 @@ func DataFromResponse[T any](rsp Response) (T, error) {
 
 	err := json.Unmarshal(dataBytes, &t)
 	if err != nil {
+		// the data itself is a plain string
+		if s, ok := any(rawJSON).(T); ok && isJSONString {
+			return s, nil
+		}
 		s, ok := rsp.Data.(T)
 		if !ok {
 			return t, fmt.Errorf("failed to unmarshal to %T: %v, raw data: %s", t, err, string(dataBytes))
@@ -64,9 +74,42 @@ func ErrorMessageResponse(msg string, wrapErr constant.Err) (Response, error) {
 	return ErrorResponse(fmt.Errorf("%s", msg), wrapErr)
 }
 
+// ErrorResponse reports err with the code of wrapErr, unless err is caused by a more specific
+// constant.Err, in which case the code of the cause is reported so clients can tell errors apart.
 func ErrorResponse(err error, wrapErr constant.Err) (Response, error) {
+	code := wrapErr.ErrorCode
+	var cause constant.Err
+	if errors.As(err, &cause) {
+		code = cause.ErrorCode
+	} else if errors.Is(err, fs.ErrNotExist) {
+		code = constant.ErrResourceNotFound.ErrorCode
+	}
 	return Response{
-		Code: wrapErr.ErrorCode,
+		Code: code,
 		Msg:  wrapErr.Wrap(err).Error(),
 	}, err
 }
+
+// RemoteError is an error reported by mini-dockerd, it matches the constant.Err it was created
+// from with errors.Is.
+type RemoteError struct {
+	Code int
+	Msg  string
+}
+
+func (e *RemoteError) Error() string {
+	return e.Msg
+}
+
+func (e *RemoteError) Is(target error) bool {
+	t, ok := target.(constant.Err)
+	return ok && t.ErrorCode == e.Code
+}
+
+// Err returns the error carried by rsp, or nil if the request succeeded.
+func (r Response) Err() error {
+	if r.Code == constant.UdsStatusOk {
+		return nil
+	}
+	return &RemoteError{Code: r.Code, Msg: r.Msg}
+}
@@ -1,8 +1,12 @@
 package handler
 
 import (
+	"bufio"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net"
 	"os"
 	"syscall"
@@ -13,7 +17,9 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-func CreateUdsServer() error {
+// CreateUdsServer serves mini-dockerd on its unix socket, ready is called once the socket accepts
+// connections, it may be nil.
+func CreateUdsServer(ready func()) error {
 	if isUdsServerRunning() {
 		logrus.Fatalf("UDS server is already running")
 		return nil
@@ -55,7 +61,14 @@ func CreateUdsServer() error {
 		return err
 	}
 
+	apiListener = newConnListener(listener.Addr())
+	defer func() { _ = apiListener.Close() }()
+	go serveHttp(apiListener)
+
 	logrus.Infof("start UDS for mini-dockerd on : %s", udsPath)
+	if ready != nil {
+		go ready()
+	}
 
 	for {
 		conn, err := listener.AcceptUnix()
@@ -97,26 +110,65 @@ func isUdsServerRunning() bool {
 }
 
 func handleClient(conn *net.UnixConn) {
-	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	if isHttpRequest(reader) {
+		if apiListener == nil {
+			logrus.Errorf("http api is not served")
+			_ = conn.Close()
+			return
+		}
+		apiListener.push(&bufferedConn{Conn: conn, reader: reader})
+		return
+	}
+	handleFrames(conn, reader)
+}
 
-	buf := make([]byte, 1024)
-	n, err := conn.Read(buf)
-	if err != nil {
-		logrus.Errorf("error read client data：%v\n", err)
+func handleFrames(conn *net.UnixConn, reader *bufio.Reader) {
+	defer func() { _ = conn.Close() }()
+
+	if err := serverHandshake(conn, reader); err != nil {
+		logrus.Errorf("error handshake with client: %v", err)
 		return
 	}
 
-	var req Request
-	err = json.Unmarshal(buf[:n], &req)
-	if err != nil {
-		resp, _ := ErrorResponse(err, constant.ErrMalformedUdsReq)
-		if err = sendResponse(conn, resp); err != nil {
-			logrus.Error("error send response: %s\n", err.Error())
+	for {
+		var req Request
+		err := readFrame(reader, &req)
+		if err != nil {
+			var syntaxErr *json.SyntaxError
+			var typeErr *json.UnmarshalTypeError
+			if !errors.As(err, &syntaxErr) && !errors.As(err, &typeErr) {
+				if !errors.Is(err, io.EOF) {
+					logrus.Errorf("error read client data: %v", err)
+				}
+				return
+			}
+			resp, _ := ErrorResponse(err, constant.ErrMalformedUdsReq)
+			if err = sendResponse(conn, resp); err != nil {
+				logrus.Errorf("error send response: %s", err.Error())
+				return
+			}
+			continue
 		}
-		return
+
+		// a stream holds the connection until it is finished, so it is always the last request.
+		if isStreamRequest(req) {
+			handleStream(conn, reader, req)
+			return
+		}
+		handleRsp(conn, req)
 	}
+}
 
-	handleRsp(conn, req)
+func serverHandshake(conn *net.UnixConn, reader *bufio.Reader) error {
+	var hello Hello
+	if err := readFrame(reader, &hello); err != nil {
+		return err
+	}
+	if err := writeFrame(conn, Hello{Version: ProtocolVersion}); err != nil {
+		return err
+	}
+	return checkHello(hello)
 }
 
 func handleRsp(conn *net.UnixConn, req Request) {
@@ -124,13 +176,31 @@ func handleRsp(conn *net.UnixConn, req Request) {
 	if err != nil {
 		logrus.Errorf("handle request error: %s, rsp : %v\n", err.Error(), rsp)
 	}
+	rsp.Id = req.Id
 	if err = sendResponse(conn, rsp); err != nil {
-		logrus.Error("error send response: %s\n", err.Error())
+		logrus.Errorf("error send response: %s", err.Error())
+	}
+}
+
+func handleStream(conn *net.UnixConn, reader *bufio.Reader, req Request) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// nothing is expected from the client while streaming, so any read result means it is gone.
+	go func() {
+		_, _ = reader.ReadByte()
+		cancel()
+	}()
+
+	stream := newStream(ctx, conn, req.Id)
+	rsp, err := streamRegistry[req.Act](req, stream)
+	if err != nil {
+		logrus.Errorf("handle stream request error: %s, rsp : %v\n", err.Error(), rsp)
+	}
+	if err = stream.close(rsp); err != nil {
+		logrus.Errorf("error close stream: %s", err.Error())
 	}
 }
 
 func sendResponse(conn *net.UnixConn, resp Response) error {
-	respData, _ := json.Marshal(resp)
-	_, err := conn.Write(respData)
-	return err
+	return writeFrame(conn, resp)
 }
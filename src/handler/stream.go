@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+// Stream is the sending side of a streamed response. It is only valid inside the StreamHandler
+// it was passed to.
+type Stream struct {
+	ctx context.Context
+	mu  sync.Mutex
+	w   io.Writer
+	id  uint64
+}
+
+func newStream(ctx context.Context, w io.Writer, id uint64) *Stream {
+	return &Stream{ctx: ctx, w: w, id: id}
+}
+
+// Context is cancelled once the client goes away, handlers that block waiting for new data
+// should select on it.
+func (s *Stream) Context() context.Context {
+	return s.ctx
+}
+
+// Send pushes an intermediate result to the client.
+func (s *Stream) Send(data any) error {
+	rsp, err := SuccessResponse(data)
+	if err != nil {
+		return err
+	}
+	rsp.More = true
+	return s.write(rsp)
+}
+
+func (s *Stream) close(rsp Response) error {
+	rsp.More = false
+	return s.write(rsp)
+}
+
+func (s *Stream) write(rsp Response) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	rsp.Id = s.id
+	return writeFrame(s.w, rsp)
+}
@@ -24,13 +24,26 @@ func main() {
 
 		runCommand,
 		initContainerCommand,
+		initLaunchCommand,
 
 		commitCommand,
 		psCommand,
+		startCommand,
+		restartCommand,
 		stopCommand,
+		killCommand,
+		pauseCommand,
+		unpauseCommand,
+		updateCommand,
+		statsCommand,
+		eventsCommand,
+		rmCommand,
+		inspectCommand,
 		logsCommand,
+		waitCommand,
 		execCommand,
 		networkCommand,
+		volumeCommand,
 	}
 
 	app.Before = func(c *cli.Context) error {
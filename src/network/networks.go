@@ -9,6 +9,7 @@ import (
 	"github.com/0x822a5b87/tiny-docker/src/entity"
 	"github.com/0x822a5b87/tiny-docker/src/util"
 	"github.com/sirupsen/logrus"
+	"github.com/vishvananda/netlink"
 )
 
 func NewNetworks() (*Networks, error) {
@@ -75,6 +76,14 @@ func (n *Networks) GetNetworkByName(networkName string) (*entity.Network, error)
 	return n.networkStore.GetByName(networkName)
 }
 
+func (n *Networks) GetNetwork(id entity.NetworkId) (*entity.Network, error) {
+	return n.networkStore.Get(id)
+}
+
+func (n *Networks) GetNetworks() ([]*entity.Network, error) {
+	return n.networkStore.GetAll()
+}
+
 func (n *Networks) DeleteNetwork(id entity.NetworkId) error {
 	nw, err := n.networkStore.Get(id)
 	if err != nil {
@@ -121,7 +130,79 @@ func (n *Networks) Connect(networkName string, container entity.Container) error
 		return err
 	}
 	vethHost, vethNs := n.getVethInfo(container)
-	return util.Connect(vethNs, vethHost, network.Name, ipNet, container.Pid)
+	if err = util.Connect(vethNs, vethHost, network.Name, ipNet, container.Pid); err != nil {
+		return err
+	}
+
+	n.Lock()
+	defer n.Unlock()
+	ip := ipNet.IP
+	endpoint = &entity.Endpoint{
+		Id:      entity.EndpointId(container.Id),
+		Name:    vethHost,
+		IP:      &ip,
+		Network: network,
+	}
+	n.endpointsInNetwork[network.Id] = append(n.endpointsInNetwork[network.Id], endpoint)
+	return n.endpointStore.Update(endpoint.Id, endpoint)
+}
+
+// Endpoint returns the endpoint of a container, it is named after the host side of its veth pair.
+func (n *Networks) Endpoint(id entity.ContainerId) (*entity.Endpoint, error) {
+	return n.endpointStore.Get(entity.EndpointId(id))
+}
+
+// Disconnect releases the endpoint of a container: its IP goes back to the IPAM of the network and
+// the host side of its veth pair is deleted. A container without endpoint is ignored.
+func (n *Networks) Disconnect(id entity.ContainerId) error {
+	n.Lock()
+	defer n.Unlock()
+	endpointId := entity.EndpointId(id)
+	endpoint, err := n.endpointStore.Get(endpointId)
+	if IsResourceNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if network := endpoint.Network; network != nil {
+		if err = n.releaseIP(network, endpoint); err != nil {
+			logrus.Errorf("[Disconnect]error releasing ip of %s: %s", endpoint.Name, err)
+			return err
+		}
+		endpoints := n.getEndpointsOfNetwork(network.Id)
+		for i, e := range endpoints {
+			if e.Id == endpointId {
+				n.endpointsInNetwork[network.Id] = append(endpoints[:i], endpoints[i+1:]...)
+				break
+			}
+		}
+	}
+
+	// the veth pair is usually gone along with the network namespace of the container
+	if link, _ := netlink.LinkByName(endpoint.Name); link != nil {
+		if err = netlink.LinkDel(link); err != nil {
+			logrus.Errorf("[Disconnect]error deleting veth %s: %s", endpoint.Name, err)
+			return err
+		}
+	}
+	return n.endpointStore.Delete(endpointId)
+}
+
+// Assume that all callers have acquired the lock when calling this function.
+func (n *Networks) releaseIP(network *entity.Network, endpoint *entity.Endpoint) error {
+	if endpoint.IP == nil || network.IPNet == nil {
+		return nil
+	}
+	ipam, err := n.ipamStore.Get(network.Id)
+	if err != nil {
+		return err
+	}
+	if err = ipam.ReleaseIP(&net.IPNet{IP: *endpoint.IP, Mask: network.IPNet.Mask}); err != nil {
+		return err
+	}
+	return n.ipamStore.Update(network.Id, ipam)
 }
 
 func (n *Networks) getEndpointsOfNetwork(id entity.NetworkId) []*entity.Endpoint {
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/0x822a5b87/tiny-docker/src/entity"
+	"github.com/0x822a5b87/tiny-docker/src/util"
+	"github.com/sirupsen/logrus"
+)
+
+func printContainerTable(containers []entity.Container) {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	defer func() { _ = writer.Flush() }()
+
+	header := "CONTAINER ID\tIMAGE\tCOMMAND\tCREATED\tSTATUS\tNAMES"
+	if _, err := fmt.Fprintln(writer, header); err != nil {
+		return
+	}
+
+	for _, c := range containers {
+		formattedCmd := fmt.Sprintf("\"%s\"", c.Command)
+		createdStr := util.FormatTimeAgo(time.UnixMilli(c.CreatedAt))
+		statusStr := util.FormatContainerStatus(c)
+
+		line := fmt.Sprintf("%s\t%s\t%s\t%s\t%s\t%s",
+			c.Id,
+			c.Image,
+			formattedCmd,
+			createdStr,
+			statusStr,
+			c.Name,
+		)
+		if _, err := fmt.Fprintln(writer, line); err != nil {
+			logrus.Errorf("error writing container table: %v", err)
+		}
+	}
+}
+
+func printStatsTable(stats []entity.ContainerStats) {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	defer func() { _ = writer.Flush() }()
+
+	header := "CONTAINER ID\tNAME\tCPU %\tMEM USAGE / LIMIT\tMEM %\tNET I/O\tBLOCK I/O\tPIDS"
+	if _, err := fmt.Fprintln(writer, header); err != nil {
+		return
+	}
+
+	for _, s := range stats {
+		line := fmt.Sprintf("%s\t%s\t%.2f%%\t%s / %s\t%.2f%%\t%s / %s\t%s / %s\t%d",
+			s.Id,
+			s.Name,
+			s.CpuPercent,
+			util.FormatBinarySize(s.MemoryUsage),
+			util.FormatBinarySize(s.MemoryLimit),
+			s.MemoryPercent,
+			util.FormatDecimalSize(s.NetRx),
+			util.FormatDecimalSize(s.NetTx),
+			util.FormatDecimalSize(s.BlockRead),
+			util.FormatDecimalSize(s.BlockWrite),
+			s.Pids,
+		)
+		if _, err := fmt.Fprintln(writer, line); err != nil {
+			logrus.Errorf("error writing stats table: %v", err)
+		}
+	}
+}
+
+func printVolumeTable(volumes []*entity.Volume) {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	defer func() { _ = writer.Flush() }()
+
+	header := "DRIVER\tVOLUME NAME"
+	if _, err := fmt.Fprintln(writer, header); err != nil {
+		return
+	}
+
+	for _, v := range volumes {
+		if _, err := fmt.Fprintf(writer, "%s\t%s\n", v.Driver, v.Name); err != nil {
+			logrus.Errorf("error writing volume table: %v", err)
+		}
+	}
+}
+
+// printEvent prints an event like docker events does:
+//
+//	2026-10-18T10:04:05.123+08:00 container oom 9195b4... (name=web, oomKillCount=1)
+func printEvent(event entity.Event) {
+	attributes := []string{"name=" + event.Name}
+	keys := make([]string, 0, len(event.Attributes))
+	for k := range event.Attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		attributes = append(attributes, k+"="+event.Attributes[k])
+	}
+	fmt.Printf("%s container %s %s (%s)\n",
+		time.UnixMilli(event.Time).Format("2006-01-02T15:04:05.000Z07:00"),
+		event.Action,
+		event.Id,
+		strings.Join(attributes, ", "))
+}
+
+func printJSON(v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
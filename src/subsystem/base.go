@@ -7,6 +7,13 @@ type Value interface {
 	Into() string
 }
 
+// LinesValue is a Value whose file takes a single line per write, like io.max, the lines are
+// written one by one.
+type LinesValue interface {
+	Value
+	Lines() []string
+}
+
 type BaseSubsystem interface {
 	Name() string
 	Empty() bool
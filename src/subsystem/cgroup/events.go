@@ -0,0 +1,44 @@
+package cgroup
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/0x822a5b87/tiny-docker/src/subsystem"
+)
+
+// EventsValue is the content of cgroup.events, it is read only so there is no subsystem for it.
+type EventsValue struct {
+	Populated bool
+	Frozen    bool
+}
+
+func (e *EventsValue) From(s string) error {
+	for _, line := range strings.Split(strings.TrimSpace(s), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		switch fields[0] {
+		case "populated":
+			e.Populated = fields[1] == "1"
+		case "frozen":
+			e.Frozen = fields[1] == "1"
+		}
+	}
+	return nil
+}
+
+func (e *EventsValue) Into() string {
+	return fmt.Sprintf("populated %d\nfrozen %d\n", boolToInt(e.Populated), boolToInt(e.Frozen))
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// add compiler check
+var _ subsystem.Value = (*EventsValue)(nil)
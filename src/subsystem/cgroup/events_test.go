@@ -0,0 +1,20 @@
+package cgroup
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventsValue(t *testing.T) {
+	v := &EventsValue{}
+	assert.NoError(t, v.From("populated 1\nfrozen 0\n"))
+	assert.Equal(t, EventsValue{Populated: true}, *v)
+
+	assert.NoError(t, v.From("populated 0\nfrozen 1\n"))
+	assert.Equal(t, EventsValue{Frozen: true}, *v)
+
+	parsed := &EventsValue{}
+	assert.NoError(t, parsed.From(v.Into()))
+	assert.Equal(t, v, parsed)
+}
@@ -0,0 +1,75 @@
+package cgroup
+
+import (
+	"strings"
+
+	"github.com/0x822a5b87/tiny-docker/src/constant"
+	"github.com/0x822a5b87/tiny-docker/src/subsystem"
+)
+
+type FreezeItem bool
+
+// FreezeValue is the content of cgroup.freeze, writing 1 freezes all processes of the cgroup and
+// its descendants. The cgroup is frozen once cgroup.events reports it, see EventsValue.
+type FreezeValue struct {
+	Frozen bool
+}
+
+func (f *FreezeValue) From(s string) error {
+	switch strings.TrimSpace(s) {
+	case "0":
+		f.Frozen = false
+	case "1":
+		f.Frozen = true
+	default:
+		return constant.ErrMalformedType
+	}
+	return nil
+}
+
+func (f *FreezeValue) Into() string {
+	if f.Frozen {
+		return "1"
+	}
+	return "0"
+}
+
+func NewFreezeValueSubsystem(data string) (*FreezeValueSubsystem, error) {
+	v := &FreezeValue{}
+	if err := v.From(data); err != nil {
+		return nil, err
+	}
+	return &FreezeValueSubsystem{
+		value: v,
+	}, nil
+}
+
+type FreezeValueSubsystem struct {
+	value *FreezeValue
+}
+
+func (f *FreezeValueSubsystem) Name() string {
+	return constant.CgroupFreeze
+}
+
+func (f *FreezeValueSubsystem) Get() (*FreezeValue, error) {
+	return f.value, nil
+}
+
+func (f *FreezeValueSubsystem) Set(item FreezeItem) error {
+	f.value.Frozen = bool(item)
+	return nil
+}
+
+func (f *FreezeValueSubsystem) Del(item FreezeItem) error {
+	f.value.Frozen = false
+	return nil
+}
+
+func (f *FreezeValueSubsystem) Empty() bool {
+	return f.value == nil
+}
+
+// add compiler check
+var _ subsystem.Value = (*FreezeValue)(nil)
+var _ subsystem.Subsystem[FreezeItem, *FreezeValue] = (*FreezeValueSubsystem)(nil)
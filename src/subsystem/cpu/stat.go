@@ -0,0 +1,61 @@
+package cpu
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/0x822a5b87/tiny-docker/src/subsystem"
+)
+
+// StatValue is the content of cpu.stat, it is read only so there is no subsystem for it. The times
+// are in microseconds.
+type StatValue struct {
+	UsageUsec     uint64
+	UserUsec      uint64
+	SystemUsec    uint64
+	NrPeriods     uint64
+	NrThrottled   uint64
+	ThrottledUsec uint64
+}
+
+func (s *StatValue) From(data string) error {
+	for _, line := range strings.Split(strings.TrimSpace(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		value, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return err
+		}
+		switch fields[0] {
+		case "usage_usec":
+			s.UsageUsec = value
+		case "user_usec":
+			s.UserUsec = value
+		case "system_usec":
+			s.SystemUsec = value
+		case "nr_periods":
+			s.NrPeriods = value
+		case "nr_throttled":
+			s.NrThrottled = value
+		case "throttled_usec":
+			s.ThrottledUsec = value
+		}
+	}
+	return nil
+}
+
+func (s *StatValue) Into() string {
+	return strings.Join([]string{
+		"usage_usec " + strconv.FormatUint(s.UsageUsec, 10),
+		"user_usec " + strconv.FormatUint(s.UserUsec, 10),
+		"system_usec " + strconv.FormatUint(s.SystemUsec, 10),
+		"nr_periods " + strconv.FormatUint(s.NrPeriods, 10),
+		"nr_throttled " + strconv.FormatUint(s.NrThrottled, 10),
+		"throttled_usec " + strconv.FormatUint(s.ThrottledUsec, 10),
+	}, "\n")
+}
+
+// add compiler check
+var _ subsystem.Value = (*StatValue)(nil)
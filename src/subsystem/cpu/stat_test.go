@@ -0,0 +1,33 @@
+package cpu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatValue(t *testing.T) {
+	data := "usage_usec 3000000\nuser_usec 1500000\nsystem_usec 500000\ncore_sched.force_idle_usec 0\n" +
+		"nr_periods 10\nnr_throttled 2\nthrottled_usec 40000\nnr_bursts 0\nburst_usec 0\n"
+	v := &StatValue{}
+	assert.NoError(t, v.From(data))
+	assert.Equal(t, StatValue{
+		UsageUsec:     3000000,
+		UserUsec:      1500000,
+		SystemUsec:    500000,
+		NrPeriods:     10,
+		NrThrottled:   2,
+		ThrottledUsec: 40000,
+	}, *v)
+
+	// the statistics of cgroup v1 have no throttling entries
+	v = &StatValue{}
+	assert.NoError(t, v.From("usage_usec 3000000\nuser_usec 1500000\nsystem_usec 500000\n"))
+	assert.Equal(t, uint64(0), v.NrPeriods)
+
+	parsed := &StatValue{}
+	assert.NoError(t, parsed.From(v.Into()))
+	assert.Equal(t, v, parsed)
+
+	assert.Error(t, v.From("usage_usec abc"))
+}
@@ -0,0 +1,105 @@
+package cpu
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/0x822a5b87/tiny-docker/src/constant"
+	"github.com/0x822a5b87/tiny-docker/src/subsystem"
+)
+
+const (
+	// the range of cpu.weight, see cgroup-v2.rst
+	MinWeight     = 1
+	MaxWeight     = 10000
+	DefaultWeight = 100
+
+	// the range of --cpu-shares, like docker
+	MinShares = 2
+	MaxShares = 262144
+)
+
+type WeightItem uint64
+
+// WeightValue is the content of cpu.weight, the share of cpu time the cgroup gets relative to its
+// siblings when they compete for it.
+type WeightValue struct {
+	Weight uint64
+}
+
+func (w *WeightValue) From(s string) error {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		w.Weight = DefaultWeight
+		return nil
+	}
+	weight, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return err
+	}
+	w.Weight = weight
+	return nil
+}
+
+func (w *WeightValue) Into() string {
+	return strconv.FormatUint(w.Weight, 10)
+}
+
+func NewWeightValueSubsystem(data string) (*WeightValueSubsystem, error) {
+	v := &WeightValue{}
+	if err := v.From(data); err != nil {
+		return nil, err
+	}
+	return &WeightValueSubsystem{
+		value: v,
+	}, nil
+}
+
+type WeightValueSubsystem struct {
+	value *WeightValue
+}
+
+func (w *WeightValueSubsystem) Name() string {
+	return constant.CpuWeight
+}
+
+func (w *WeightValueSubsystem) Get() (*WeightValue, error) {
+	return w.value, nil
+}
+
+func (w *WeightValueSubsystem) Set(item WeightItem) error {
+	if item < MinWeight || item > MaxWeight {
+		return constant.ErrOutOfRange
+	}
+	w.value.Weight = uint64(item)
+	return nil
+}
+
+func (w *WeightValueSubsystem) Del(item WeightItem) error {
+	w.value.Weight = DefaultWeight
+	return nil
+}
+
+func (w *WeightValueSubsystem) Empty() bool {
+	return w.value == nil || w.value.Weight == DefaultWeight
+}
+
+// SharesToWeight maps the cpu shares of docker, 2 to 262144, onto cpu.weight, 1 to 10000, the way
+// runc does. 0 keeps the default weight.
+func SharesToWeight(shares uint64) uint64 {
+	if shares == 0 {
+		return 0
+	}
+	return 1 + (shares-MinShares)*(MaxWeight-1)/(MaxShares-MinShares)
+}
+
+// WeightToShares maps cpu.weight back onto cpu.shares of cgroup v1, it rounds up so that
+// SharesToWeight gives the same weight again.
+func WeightToShares(weight uint64) uint64 {
+	const scale = MaxWeight - 1
+	return MinShares + ((weight-1)*(MaxShares-MinShares)+scale-1)/scale
+}
+
+// add compiler check
+var _ subsystem.Value = (*WeightValue)(nil)
+var _ subsystem.Subsystem[WeightItem, *WeightValue] = (*WeightValueSubsystem)(nil)
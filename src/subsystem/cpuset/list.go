@@ -0,0 +1,166 @@
+package cpuset
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/0x822a5b87/tiny-docker/src/constant"
+	"github.com/0x822a5b87/tiny-docker/src/subsystem"
+)
+
+// maxId bounds the ids of a list, so that a typo like 0-1000000000 does not allocate a huge list.
+const maxId = 1 << 16
+
+type ListItem ListValue
+
+// ListValue is the content of cpuset.cpus, cpuset.mems and their effective counterparts, a list
+// of cpu or memory node ids in the list format of the kernel:
+//
+//	0-3,6
+//
+// An empty list lets the cgroup use whatever its parent may use.
+type ListValue struct {
+	Ids []int
+}
+
+func (l *ListValue) From(s string) error {
+	ids, err := ParseList(s)
+	if err != nil {
+		return err
+	}
+	l.Ids = ids
+	return nil
+}
+
+func (l *ListValue) Into() string {
+	return FormatList(l.Ids)
+}
+
+// Contains reports whether every id of other is in l.
+func (l *ListValue) Contains(other ListValue) bool {
+	for _, id := range other.Ids {
+		if _, found := slices.BinarySearch(l.Ids, id); !found {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseList parses a list like 0-3,6 into sorted ids without duplicates.
+func ParseList(s string) ([]int, error) {
+	var ids []int
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return ids, nil
+	}
+	for _, part := range strings.Split(s, ",") {
+		first, last, isRange := strings.Cut(part, "-")
+		start, err := parseId(first)
+		if err != nil {
+			return nil, err
+		}
+		end := start
+		if isRange {
+			if end, err = parseId(last); err != nil {
+				return nil, err
+			}
+			if end < start {
+				return nil, constant.ErrMalformedType.WrapMessage(fmt.Sprintf("invalid range %s", part))
+			}
+		}
+		for id := start; id <= end; id++ {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	return slices.Compact(ids), nil
+}
+
+// FormatList formats sorted ids in the list format, runs of consecutive ids become ranges.
+func FormatList(ids []int) string {
+	var parts []string
+	for i := 0; i < len(ids); {
+		j := i
+		for j+1 < len(ids) && ids[j+1] == ids[j]+1 {
+			j++
+		}
+		if i == j {
+			parts = append(parts, strconv.Itoa(ids[i]))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d-%d", ids[i], ids[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ",")
+}
+
+func parseId(s string) (int, error) {
+	id, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || id < 0 || id >= maxId {
+		return 0, constant.ErrMalformedType.WrapMessage(fmt.Sprintf("invalid id %s", s))
+	}
+	return id, nil
+}
+
+func NewCpusValueSubsystem(data string) (*CpusValueSubsystem, error) {
+	v := &ListValue{}
+	if err := v.From(data); err != nil {
+		return nil, err
+	}
+	return &CpusValueSubsystem{listSubsystem{value: v}}, nil
+}
+
+func NewMemsValueSubsystem(data string) (*MemsValueSubsystem, error) {
+	v := &ListValue{}
+	if err := v.From(data); err != nil {
+		return nil, err
+	}
+	return &MemsValueSubsystem{listSubsystem{value: v}}, nil
+}
+
+// CpusValueSubsystem is cpuset.cpus, the cpus the processes of the cgroup may run on.
+type CpusValueSubsystem struct {
+	listSubsystem
+}
+
+func (c *CpusValueSubsystem) Name() string {
+	return constant.CpusetCpus
+}
+
+// MemsValueSubsystem is cpuset.mems, the memory nodes the processes of the cgroup may allocate on.
+type MemsValueSubsystem struct {
+	listSubsystem
+}
+
+func (m *MemsValueSubsystem) Name() string {
+	return constant.CpusetMems
+}
+
+type listSubsystem struct {
+	value *ListValue
+}
+
+func (l *listSubsystem) Get() (*ListValue, error) {
+	return l.value, nil
+}
+
+func (l *listSubsystem) Set(item ListItem) error {
+	l.value.Ids = item.Ids
+	return nil
+}
+
+func (l *listSubsystem) Del(item ListItem) error {
+	l.value.Ids = nil
+	return nil
+}
+
+func (l *listSubsystem) Empty() bool {
+	return l.value == nil || len(l.value.Ids) == 0
+}
+
+// add compiler check
+var _ subsystem.Value = (*ListValue)(nil)
+var _ subsystem.Subsystem[ListItem, *ListValue] = (*CpusValueSubsystem)(nil)
+var _ subsystem.Subsystem[ListItem, *ListValue] = (*MemsValueSubsystem)(nil)
@@ -0,0 +1,34 @@
+package cpuset
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseList(t *testing.T) {
+	ids, err := ParseList("0-3,6")
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 1, 2, 3, 6}, ids)
+
+	ids, err = ParseList("5,1-2,2\n")
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 5}, ids)
+	assert.Equal(t, "1-2,5", FormatList(ids))
+
+	ids, err = ParseList("")
+	assert.NoError(t, err)
+	assert.Empty(t, ids)
+
+	for _, s := range []string{"a", "3-1", "-1", "0-", "1,,2"} {
+		_, err = ParseList(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestListContains(t *testing.T) {
+	effective := ListValue{}
+	assert.NoError(t, effective.From("0-7"))
+	assert.True(t, effective.Contains(ListValue{Ids: []int{0, 3, 7}}))
+	assert.False(t, effective.Contains(ListValue{Ids: []int{8}}))
+}
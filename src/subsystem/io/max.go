@@ -0,0 +1,164 @@
+package io
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/0x822a5b87/tiny-docker/src/constant"
+	"github.com/0x822a5b87/tiny-docker/src/subsystem"
+)
+
+// DeviceLimit throttles a block device, a rate of 0 means no limit, which the kernel calls max.
+type DeviceLimit struct {
+	Major uint32
+	Minor uint32
+	Rbps  uint64
+	Wbps  uint64
+	Riops uint64
+	Wiops uint64
+}
+
+func (d DeviceLimit) String() string {
+	return fmt.Sprintf("%d:%d rbps=%s wbps=%s riops=%s wiops=%s", d.Major, d.Minor,
+		formatRate(d.Rbps), formatRate(d.Wbps), formatRate(d.Riops), formatRate(d.Wiops))
+}
+
+// merge takes the rates set in other, a rate of 0 in other keeps the one of d.
+func (d *DeviceLimit) merge(other DeviceLimit) {
+	for _, pair := range []struct {
+		dst *uint64
+		src uint64
+	}{{&d.Rbps, other.Rbps}, {&d.Wbps, other.Wbps}, {&d.Riops, other.Riops}, {&d.Wiops, other.Wiops}} {
+		if pair.src != 0 {
+			*pair.dst = pair.src
+		}
+	}
+}
+
+type MaxItem DeviceLimit
+
+// MaxValue is the content of io.max, one line per throttled device:
+//
+//	8:16 rbps=2097152 wbps=max riops=max wiops=120
+type MaxValue struct {
+	Devices []DeviceLimit
+}
+
+func (m *MaxValue) From(s string) error {
+	m.Devices = nil
+	for _, line := range strings.Split(strings.TrimSpace(s), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		var d DeviceLimit
+		if _, err := fmt.Sscanf(fields[0], "%d:%d", &d.Major, &d.Minor); err != nil {
+			return constant.ErrMalformedType
+		}
+		for _, field := range fields[1:] {
+			key, value, ok := strings.Cut(field, "=")
+			if !ok {
+				return constant.ErrMalformedType
+			}
+			rate, err := parseRate(value)
+			if err != nil {
+				return err
+			}
+			switch key {
+			case "rbps":
+				d.Rbps = rate
+			case "wbps":
+				d.Wbps = rate
+			case "riops":
+				d.Riops = rate
+			case "wiops":
+				d.Wiops = rate
+			}
+		}
+		m.Devices = append(m.Devices, d)
+	}
+	return nil
+}
+
+func (m *MaxValue) Into() string {
+	return strings.Join(m.Lines(), "\n")
+}
+
+// Lines returns a line per device, the kernel takes a single device per write.
+func (m *MaxValue) Lines() []string {
+	lines := make([]string, 0, len(m.Devices))
+	for _, d := range m.Devices {
+		lines = append(lines, d.String())
+	}
+	return lines
+}
+
+func NewMaxValueSubsystem(data string) (*MaxValueSubsystem, error) {
+	v := &MaxValue{}
+	if err := v.From(data); err != nil {
+		return nil, err
+	}
+	return &MaxValueSubsystem{
+		value: v,
+	}, nil
+}
+
+type MaxValueSubsystem struct {
+	value *MaxValue
+}
+
+func (m *MaxValueSubsystem) Name() string {
+	return constant.IoMax
+}
+
+func (m *MaxValueSubsystem) Get() (*MaxValue, error) {
+	return m.value, nil
+}
+
+// Set merges the rates of item into the limits of its device.
+func (m *MaxValueSubsystem) Set(item MaxItem) error {
+	for i := range m.value.Devices {
+		d := &m.value.Devices[i]
+		if d.Major == item.Major && d.Minor == item.Minor {
+			d.merge(DeviceLimit(item))
+			return nil
+		}
+	}
+	m.value.Devices = append(m.value.Devices, DeviceLimit(item))
+	return nil
+}
+
+// Del lifts all limits of the device of item, the device is kept so that max is written for it.
+func (m *MaxValueSubsystem) Del(item MaxItem) error {
+	for i := range m.value.Devices {
+		d := &m.value.Devices[i]
+		if d.Major == item.Major && d.Minor == item.Minor {
+			*d = DeviceLimit{Major: d.Major, Minor: d.Minor}
+			return nil
+		}
+	}
+	return constant.ErrResourceNotFound
+}
+
+func (m *MaxValueSubsystem) Empty() bool {
+	return m.value == nil || len(m.value.Devices) == 0
+}
+
+func parseRate(s string) (uint64, error) {
+	if s == constant.LiteralMax {
+		return 0, nil
+	}
+	return strconv.ParseUint(s, 10, 64)
+}
+
+func formatRate(rate uint64) string {
+	if rate == 0 {
+		return constant.LiteralMax
+	}
+	return strconv.FormatUint(rate, 10)
+}
+
+// add compiler check
+var _ subsystem.LinesValue = (*MaxValue)(nil)
+var _ subsystem.Subsystem[MaxItem, *MaxValue] = (*MaxValueSubsystem)(nil)
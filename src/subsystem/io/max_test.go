@@ -0,0 +1,65 @@
+package io
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaxValue(t *testing.T) {
+	v := &MaxValue{}
+	assert.NoError(t, v.From("8:16 rbps=2097152 wbps=max riops=max wiops=120\n253:0 rbps=max wbps=1048576 riops=10 wiops=max\n"))
+	assert.Equal(t, []DeviceLimit{
+		{Major: 8, Minor: 16, Rbps: 2097152, Wiops: 120},
+		{Major: 253, Minor: 0, Wbps: 1048576, Riops: 10},
+	}, v.Devices)
+	assert.Equal(t, []string{
+		"8:16 rbps=2097152 wbps=max riops=max wiops=120",
+		"253:0 rbps=max wbps=1048576 riops=10 wiops=max",
+	}, v.Lines())
+
+	parsed := &MaxValue{}
+	assert.NoError(t, parsed.From(v.Into()))
+	assert.Equal(t, v, parsed)
+
+	// a device without limits is written with max for all of them
+	assert.Equal(t, "8:0 rbps=max wbps=max riops=max wiops=max", DeviceLimit{Major: 8}.String())
+
+	// a cgroup which throttles nothing has an empty io.max
+	assert.NoError(t, v.From(""))
+	assert.Empty(t, v.Devices)
+	assert.Empty(t, v.Lines())
+
+	for _, data := range []string{"sda rbps=1", "8:16 rbps", "8:16 rbps=-1", "8:16 wbps=fast"} {
+		assert.Error(t, v.From(data), data)
+	}
+}
+
+func TestMaxValueSubsystemSet(t *testing.T) {
+	ss, err := NewMaxValueSubsystem("8:16 rbps=2097152 wbps=max riops=max wiops=120")
+	assert.NoError(t, err)
+	assert.False(t, ss.Empty())
+
+	// the rates which are set are merged into the limits of the device
+	assert.NoError(t, ss.Set(MaxItem{Major: 8, Minor: 16, Wbps: 1048576, Wiops: 60}))
+	// another device gets a line of its own
+	assert.NoError(t, ss.Set(MaxItem{Major: 8, Minor: 0, Riops: 10}))
+	v, err := ss.Get()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"8:16 rbps=2097152 wbps=1048576 riops=max wiops=60",
+		"8:0 rbps=max wbps=max riops=10 wiops=max",
+	}, v.Lines())
+
+	// the device is kept, so that max is written for it
+	assert.NoError(t, ss.Del(MaxItem{Major: 8, Minor: 16}))
+	assert.Equal(t, "8:16 rbps=max wbps=max riops=max wiops=max", v.Lines()[0])
+	assert.Error(t, ss.Del(MaxItem{Major: 8, Minor: 32}))
+
+	_, err = NewMaxValueSubsystem("8:16 rbps=")
+	assert.Error(t, err)
+
+	ss, err = NewMaxValueSubsystem("")
+	assert.NoError(t, err)
+	assert.True(t, ss.Empty())
+}
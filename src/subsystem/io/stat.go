@@ -0,0 +1,88 @@
+package io
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/0x822a5b87/tiny-docker/src/constant"
+	"github.com/0x822a5b87/tiny-docker/src/subsystem"
+)
+
+// DeviceStat counts the bytes and operations of the cgroup on a block device since it was created.
+type DeviceStat struct {
+	Major  uint32
+	Minor  uint32
+	Rbytes uint64
+	Wbytes uint64
+	Rios   uint64
+	Wios   uint64
+}
+
+// StatValue is the content of io.stat, one line per device the cgroup used. It is read only so
+// there is no subsystem for it.
+//
+//	8:16 rbytes=1459200 wbytes=314773504 rios=192 wios=353 dbytes=0 dios=0
+type StatValue struct {
+	Devices []DeviceStat
+}
+
+func (s *StatValue) From(data string) error {
+	s.Devices = nil
+	for _, line := range strings.Split(strings.TrimSpace(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		var d DeviceStat
+		if _, err := fmt.Sscanf(fields[0], "%d:%d", &d.Major, &d.Minor); err != nil {
+			return constant.ErrMalformedType
+		}
+		for _, field := range fields[1:] {
+			key, value, ok := strings.Cut(field, "=")
+			if !ok {
+				return constant.ErrMalformedType
+			}
+			n, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				return err
+			}
+			switch key {
+			case "rbytes":
+				d.Rbytes = n
+			case "wbytes":
+				d.Wbytes = n
+			case "rios":
+				d.Rios = n
+			case "wios":
+				d.Wios = n
+			}
+		}
+		s.Devices = append(s.Devices, d)
+	}
+	return nil
+}
+
+func (s *StatValue) Into() string {
+	lines := make([]string, 0, len(s.Devices))
+	for _, d := range s.Devices {
+		lines = append(lines, fmt.Sprintf("%d:%d rbytes=%d wbytes=%d rios=%d wios=%d",
+			d.Major, d.Minor, d.Rbytes, d.Wbytes, d.Rios, d.Wios))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Total sums the counters of all devices.
+func (s *StatValue) Total() DeviceStat {
+	var total DeviceStat
+	for _, d := range s.Devices {
+		total.Rbytes += d.Rbytes
+		total.Wbytes += d.Wbytes
+		total.Rios += d.Rios
+		total.Wios += d.Wios
+	}
+	return total
+}
+
+// add compiler check
+var _ subsystem.Value = (*StatValue)(nil)
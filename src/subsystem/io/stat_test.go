@@ -0,0 +1,32 @@
+package io
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatValue(t *testing.T) {
+	data := "8:16 rbytes=1459200 wbytes=314773504 rios=192 wios=353 dbytes=0 dios=0\n" +
+		"253:0 rbytes=4096 wbytes=0 rios=1 wios=0 dbytes=0 dios=0\n"
+	v := &StatValue{}
+	assert.NoError(t, v.From(data))
+	assert.Equal(t, []DeviceStat{
+		{Major: 8, Minor: 16, Rbytes: 1459200, Wbytes: 314773504, Rios: 192, Wios: 353},
+		{Major: 253, Minor: 0, Rbytes: 4096, Rios: 1},
+	}, v.Devices)
+	assert.Equal(t, DeviceStat{Rbytes: 1463296, Wbytes: 314773504, Rios: 193, Wios: 353}, v.Total())
+
+	parsed := &StatValue{}
+	assert.NoError(t, parsed.From(v.Into()))
+	assert.Equal(t, v, parsed)
+
+	// a cgroup which has done no io has an empty io.stat
+	assert.NoError(t, v.From(""))
+	assert.Empty(t, v.Devices)
+	assert.Equal(t, DeviceStat{}, v.Total())
+
+	for _, data := range []string{"sda rbytes=1", "8:16 rbytes", "8:16 rbytes=abc"} {
+		assert.Error(t, v.From(data), data)
+	}
+}
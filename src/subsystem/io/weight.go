@@ -0,0 +1,126 @@
+package io
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/0x822a5b87/tiny-docker/src/constant"
+	"github.com/0x822a5b87/tiny-docker/src/subsystem"
+)
+
+const (
+	// the range of io.weight, see cgroup-v2.rst
+	MinWeight     = 1
+	MaxWeight     = 10000
+	DefaultWeight = 100
+)
+
+type WeightItem uint64
+
+// WeightValue is the content of io.weight, the default weight of the cgroup followed by the
+// weights of single devices:
+//
+//	default 100
+//	8:16 200
+type WeightValue struct {
+	Default uint64
+	Devices map[string]uint64
+}
+
+func (w *WeightValue) From(s string) error {
+	w.Default = DefaultWeight
+	w.Devices = make(map[string]uint64)
+	for _, line := range strings.Split(strings.TrimSpace(s), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return constant.ErrMalformedType
+		}
+		weight, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return err
+		}
+		if fields[0] == "default" {
+			w.Default = weight
+		} else {
+			w.Devices[fields[0]] = weight
+		}
+	}
+	return nil
+}
+
+func (w *WeightValue) Into() string {
+	return strings.Join(w.Lines(), "\n")
+}
+
+// Lines returns the default weight first and a line per device, the kernel takes one per write.
+func (w *WeightValue) Lines() []string {
+	lines := []string{fmt.Sprintf("default %d", w.Default)}
+	for device, weight := range w.Devices {
+		lines = append(lines, fmt.Sprintf("%s %d", device, weight))
+	}
+	return lines
+}
+
+func NewWeightValueSubsystem(data string) (*WeightValueSubsystem, error) {
+	v := &WeightValue{}
+	if err := v.From(data); err != nil {
+		return nil, err
+	}
+	return &WeightValueSubsystem{
+		value: v,
+	}, nil
+}
+
+type WeightValueSubsystem struct {
+	value *WeightValue
+}
+
+func (w *WeightValueSubsystem) Name() string {
+	return constant.IoWeight
+}
+
+func (w *WeightValueSubsystem) Get() (*WeightValue, error) {
+	return w.value, nil
+}
+
+// Set changes the default weight of the cgroup.
+func (w *WeightValueSubsystem) Set(item WeightItem) error {
+	if item < MinWeight || item > MaxWeight {
+		return constant.ErrOutOfRange
+	}
+	w.value.Default = uint64(item)
+	return nil
+}
+
+func (w *WeightValueSubsystem) Del(item WeightItem) error {
+	w.value.Default = DefaultWeight
+	return nil
+}
+
+// Empty reports whether the weights are the defaults of the kernel, which need not be written.
+func (w *WeightValueSubsystem) Empty() bool {
+	return w.value == nil || (w.value.Default == DefaultWeight && len(w.value.Devices) == 0)
+}
+
+// BlkioWeightToIoWeight maps the blkio weight of docker, 10 to 1000, onto io.weight, 1 to 10000,
+// the way runc does.
+func BlkioWeightToIoWeight(blkioWeight uint16) uint64 {
+	if blkioWeight == 0 {
+		return 0
+	}
+	return 1 + (uint64(blkioWeight)-10)*9999/990
+}
+
+// IoWeightToBlkioWeight maps io.weight back onto blkio.weight of cgroup v1, it gives the blkio
+// weight again which BlkioWeightToIoWeight was given.
+func IoWeightToBlkioWeight(weight uint64) uint16 {
+	return uint16(10 + ((weight-1)*990+9998)/9999)
+}
+
+// add compiler check
+var _ subsystem.LinesValue = (*WeightValue)(nil)
+var _ subsystem.Subsystem[WeightItem, *WeightValue] = (*WeightValueSubsystem)(nil)
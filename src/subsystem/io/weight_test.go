@@ -0,0 +1,66 @@
+package io
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeightValue(t *testing.T) {
+	v := &WeightValue{}
+	assert.NoError(t, v.From("default 200\n8:16 300\n253:0 50\n"))
+	assert.Equal(t, uint64(200), v.Default)
+	assert.Equal(t, map[string]uint64{"8:16": 300, "253:0": 50}, v.Devices)
+	// the default comes first, the devices follow in any order
+	lines := v.Lines()
+	assert.Equal(t, "default 200", lines[0])
+	assert.ElementsMatch(t, []string{"8:16 300", "253:0 50"}, lines[1:])
+
+	parsed := &WeightValue{}
+	assert.NoError(t, parsed.From(v.Into()))
+	assert.Equal(t, v, parsed)
+
+	// the weight is the default of the kernel without a default line
+	assert.NoError(t, v.From(""))
+	assert.Equal(t, uint64(DefaultWeight), v.Default)
+	assert.Empty(t, v.Devices)
+	assert.Equal(t, []string{"default 100"}, v.Lines())
+
+	for _, data := range []string{"default", "default 100 200", "default -1", "8:16 heavy"} {
+		assert.Error(t, v.From(data), data)
+	}
+}
+
+func TestWeightValueSubsystem(t *testing.T) {
+	ss, err := NewWeightValueSubsystem("default 100\n")
+	assert.NoError(t, err)
+	assert.True(t, ss.Empty())
+
+	for _, weight := range []WeightItem{0, MaxWeight + 1} {
+		assert.Error(t, ss.Set(weight), weight)
+	}
+	for _, weight := range []WeightItem{MinWeight, 500, MaxWeight} {
+		assert.NoError(t, ss.Set(weight), weight)
+		v, err := ss.Get()
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(weight), v.Default)
+	}
+	assert.False(t, ss.Empty())
+
+	assert.NoError(t, ss.Del(0))
+	assert.True(t, ss.Empty())
+
+	// the weight of a device is written as well
+	ss, err = NewWeightValueSubsystem("default 100\n8:16 300\n")
+	assert.NoError(t, err)
+	assert.False(t, ss.Empty())
+}
+
+func TestBlkioWeight(t *testing.T) {
+	assert.Equal(t, uint64(0), BlkioWeightToIoWeight(0))
+	assert.Equal(t, uint64(MinWeight), BlkioWeightToIoWeight(10))
+	assert.Equal(t, uint64(MaxWeight), BlkioWeightToIoWeight(1000))
+	for blkioWeight := uint16(10); blkioWeight <= 1000; blkioWeight++ {
+		assert.Equal(t, blkioWeight, IoWeightToBlkioWeight(BlkioWeightToIoWeight(blkioWeight)), blkioWeight)
+	}
+}
@@ -0,0 +1,85 @@
+package manager
+
+import (
+	"errors"
+	"time"
+
+	"github.com/0x822a5b87/tiny-docker/src/constant"
+	"github.com/0x822a5b87/tiny-docker/src/subsystem/cgroup"
+	"golang.org/x/sys/unix"
+)
+
+// eventsPollInterval is how often cgroup.events is read where it cannot be watched.
+const eventsPollInterval = 10 * time.Millisecond
+
+// ReadEvents reads cgroup.events of the cgroup.
+func ReadEvents(f CgroupFileSystem) (*cgroup.EventsValue, error) {
+	err, data := f.Read(constant.CgroupEvents)
+	if err != nil {
+		return nil, err
+	}
+	v := &cgroup.EventsValue{}
+	err = v.From(data)
+	return v, err
+}
+
+// WaitEvents blocks until cgroup.events satisfies done, or fails once timeout passed. The kernel
+// generates a modify event on cgroup.events whenever its content changes, which is watched with
+// inotify instead of polling the file. cgroup v1 has no such file, it is polled there.
+func WaitEvents(f CgroupFileSystem, done func(*cgroup.EventsValue) bool, timeout time.Duration) error {
+	p, err := f.WatchPath(constant.CgroupEvents)
+	if errors.Is(err, constant.ErrUnsupportedAction) {
+		return pollEvents(f, done, timeout)
+	}
+	if err != nil {
+		return err
+	}
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = unix.Close(fd) }()
+	if _, err = unix.InotifyAddWatch(fd, p, unix.IN_MODIFY); err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(timeout)
+	buf := make([]byte, 4096)
+	for {
+		// the file is read after the watch is added, so a change in between is not missed
+		events, err := ReadEvents(f)
+		if err != nil {
+			return err
+		}
+		if done(events) {
+			return nil
+		}
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return constant.ErrCgroupEventTimeout.WrapMessage(p)
+		}
+		fds := []unix.PollFd{{Fd: int32(fd), Events: unix.POLLIN}}
+		if _, err = unix.Poll(fds, int(remaining.Milliseconds())+1); err != nil && !errors.Is(err, unix.EINTR) {
+			return err
+		}
+		// drain the events, only the content of the file matters
+		_, _ = unix.Read(fd, buf)
+	}
+}
+
+func pollEvents(f CgroupFileSystem, done func(*cgroup.EventsValue) bool, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		events, err := ReadEvents(f)
+		if err != nil {
+			return err
+		}
+		if done(events) {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return constant.ErrCgroupEventTimeout.WrapMessage(constant.CgroupEvents)
+		}
+		time.Sleep(eventsPollInterval)
+	}
+}
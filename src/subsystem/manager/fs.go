@@ -1,22 +1,55 @@
 package manager
 
 import (
+	"errors"
 	"os"
+	"path/filepath"
+	"syscall"
 
+	"github.com/0x822a5b87/tiny-docker/src/constant"
 	"github.com/0x822a5b87/tiny-docker/src/util"
+	"github.com/sirupsen/logrus"
 )
 
-type CgroupFileSystem struct {
-	Path       string
-	AutoCreate bool
+// CgroupFileSystem reads and writes the files of a cgroup by their names and in their format of
+// cgroup v2, the subsystems are the same whichever way the host mounts cgroups.
+type CgroupFileSystem interface {
+	Read(name string) (error, string)
+	Write(name string, data string) error
+	// WriteLines writes a line at a time, the kernel parses a single line of some files per write.
+	WriteLines(name string, lines []string) error
+	// Exists reports whether the kernel provides the file, some depend on its version and config.
+	Exists(name string) bool
+	// WatchPath returns the path of a file to watch with inotify, it fails with
+	// ErrUnsupportedAction if the content of the file is made up from other files.
+	WatchPath(name string) (string, error)
+	// Remove removes the cgroup, it fails with EBUSY while processes are left in it.
+	Remove() error
 }
 
-func NewCgroupFileSystem(basePath string, autoCreate bool) *CgroupFileSystem {
-	return &CgroupFileSystem{Path: basePath, AutoCreate: autoCreate}
+// NewCgroupFileSystem opens a cgroup by its path relative to the root of a hierarchy, the backend
+// is chosen by the cgroup mode of the host. A hybrid host is handled like one with cgroup v1, its
+// unified hierarchy has no controllers.
+func NewCgroupFileSystem(cgroup string, autoCreate bool) CgroupFileSystem {
+	if util.DetectCgroupMode() == util.CgroupUnified {
+		return &unifiedFileSystem{path: filepath.Join(constant.CgroupRootPath, cgroup), autoCreate: autoCreate}
+	}
+	return &legacyFileSystem{cgroup: cgroup, autoCreate: autoCreate}
+}
+
+// RemoveCgroup removes the cgroup of a container, a cgroup which does not exist is not an error.
+func RemoveCgroup(cgroup string) error {
+	return NewCgroupFileSystem(cgroup, false).Remove()
 }
 
-func (f *CgroupFileSystem) Read(name string) (error, string) {
-	cgroupPath, err := util.GetCgroupPath(name, f.Path, f.AutoCreate)
+// unifiedFileSystem is a cgroup of the unified hierarchy of cgroup v2.
+type unifiedFileSystem struct {
+	path       string
+	autoCreate bool
+}
+
+func (f *unifiedFileSystem) Read(name string) (error, string) {
+	cgroupPath, err := util.GetCgroupPath(name, f.path, f.autoCreate)
 	if err != nil {
 		return err, ""
 	}
@@ -29,10 +62,55 @@ func (f *CgroupFileSystem) Read(name string) (error, string) {
 	return nil, string(data)
 }
 
-func (f *CgroupFileSystem) Write(name string, data string) error {
-	cgroupPath, err := util.GetCgroupPath(name, f.Path, f.AutoCreate)
+func (f *unifiedFileSystem) Write(name string, data string) error {
+	cgroupPath, err := util.GetCgroupPath(name, f.path, f.autoCreate)
 	if err != nil {
 		return err
 	}
 	return os.WriteFile(cgroupPath, []byte(data), 0644)
 }
+
+func (f *unifiedFileSystem) WriteLines(name string, lines []string) error {
+	cgroupPath, err := util.GetCgroupPath(name, f.path, f.autoCreate)
+	if err != nil {
+		return err
+	}
+	return writeLines(cgroupPath, lines)
+}
+
+func (f *unifiedFileSystem) Exists(name string) bool {
+	_, err := os.Stat(filepath.Join(f.path, name))
+	return err == nil
+}
+
+func (f *unifiedFileSystem) WatchPath(name string) (string, error) {
+	return util.GetCgroupPath(name, f.path, false)
+}
+
+func (f *unifiedFileSystem) Remove() error {
+	return removeCgroupDir(f.path)
+}
+
+// removeCgroupDir removes a cgroup with rmdir, the files in it are not real files.
+func removeCgroupDir(path string) error {
+	if err := syscall.Rmdir(path); err != nil && !errors.Is(err, syscall.ENOENT) {
+		return err
+	}
+	return nil
+}
+
+func writeLines(cgroupPath string, lines []string) error {
+	f, err := os.OpenFile(cgroupPath, os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	for _, line := range lines {
+		// a write per line, the kernel parses one line of these files at a time
+		if _, err = f.WriteString(line); err != nil {
+			logrus.Errorf("error write value : {%s}", line)
+			return err
+		}
+	}
+	return nil
+}
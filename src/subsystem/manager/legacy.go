@@ -0,0 +1,663 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/0x822a5b87/tiny-docker/src/constant"
+	"github.com/0x822a5b87/tiny-docker/src/subsystem/cpu"
+	"github.com/0x822a5b87/tiny-docker/src/subsystem/io"
+	"github.com/0x822a5b87/tiny-docker/src/util"
+)
+
+const (
+	// legacyUnlimited is the least value cgroup v1 reports for a memory limit which is not set, the
+	// kernel rounds the limit down to a multiple of the page size.
+	legacyUnlimited = 1 << 62
+	// userHz is the unit of cpuacct.stat, which is fixed at 100 for user space.
+	userHz = 100
+)
+
+// legacyControllers are the controllers of cgroup v1 a container joins, the processes of the
+// cgroup are read from the first one that is mounted.
+var legacyControllers = []string{"memory", "cpu", "cpuacct", "cpuset", "pids", "blkio", "freezer"}
+
+// legacyFileSystem is a cgroup of cgroup v1, which has a hierarchy per controller. A file of cgroup
+// v2 is mapped onto the files of a controller, one without counterpart does not exist:
+//
+//	cpu.max         cpu.cfs_quota_us and cpu.cfs_period_us
+//	cpu.weight      cpu.shares
+//	memory.max      memory.limit_in_bytes
+//	memory.low      memory.soft_limit_in_bytes
+//	memory.swap.max memory.memsw.limit_in_bytes
+//	io.max          blkio.throttle.*_device
+//	io.weight       blkio.weight
+//	cgroup.freeze   freezer.state
+type legacyFileSystem struct {
+	cgroup     string
+	autoCreate bool
+}
+
+// legacyFile is the counterpart of a file of cgroup v2. An empty controller stands for the first
+// of legacyControllers that is mounted. read and write convert the content of file, or combine it
+// with the files next to it, a file with the same content in both versions has neither.
+type legacyFile struct {
+	controller string
+	file       string
+	read       func(f *legacyFileSystem, path string) (string, error)
+	write      func(f *legacyFileSystem, path string, data string) error
+}
+
+var legacyFiles = map[string]legacyFile{
+	constant.CgroupProcs:         {file: "cgroup.procs", write: writeLegacyProcs},
+	constant.CgroupFreeze:        {controller: "freezer", file: "freezer.state", read: readLegacyFreeze, write: writeLegacyFreeze},
+	constant.CgroupEvents:        {controller: "freezer", file: "freezer.state", read: readLegacyEvents},
+	constant.CpuMax:              {controller: "cpu", file: "cpu.cfs_quota_us", read: readLegacyCpuMax, write: writeLegacyCpuMax},
+	constant.CpuWeight:           {controller: "cpu", file: "cpu.shares", read: readLegacyCpuWeight, write: writeLegacyCpuWeight},
+	constant.CpuStat:             {controller: "cpuacct", file: "cpuacct.usage", read: readLegacyCpuStat},
+	constant.CpusetCpus:          {controller: "cpuset", file: "cpuset.cpus"},
+	constant.CpusetMems:          {controller: "cpuset", file: "cpuset.mems"},
+	constant.CpusetCpusEffective: {controller: "cpuset", file: "cpuset.effective_cpus"},
+	constant.CpusetMemsEffective: {controller: "cpuset", file: "cpuset.effective_mems"},
+	constant.MemoryMax:           {controller: "memory", file: "memory.limit_in_bytes", read: readLegacyLimit, write: writeLegacyMemoryMax},
+	constant.MemoryLow:           {controller: "memory", file: "memory.soft_limit_in_bytes", read: readLegacyLimit, write: writeLegacyLimit},
+	constant.MemorySwapMax:       {controller: "memory", file: "memory.memsw.limit_in_bytes", read: readLegacySwapMax, write: writeLegacySwapMax},
+	constant.MemoryCurrent:       {controller: "memory", file: "memory.usage_in_bytes"},
+	constant.MemoryStat:          {controller: "memory", file: "memory.stat", read: readLegacyMemoryStat},
+	constant.MemoryEvents:        {controller: "memory", file: "memory.oom_control", read: readLegacyMemoryEvents},
+	constant.PidsMax:             {controller: "pids", file: "pids.max"},
+	constant.PidsCurrent:         {controller: "pids", file: "pids.current"},
+	constant.IoMax:               {controller: "blkio", file: "blkio.throttle.read_bps_device", read: readLegacyIoMax, write: writeLegacyIoMax},
+	constant.IoWeight:            {controller: "blkio", file: "blkio.weight", read: readLegacyIoWeight, write: writeLegacyIoWeight},
+	constant.IoStat:              {controller: "blkio", file: "blkio.throttle.io_service_bytes", read: readLegacyIoStat},
+}
+
+func (f *legacyFileSystem) Read(name string) (error, string) {
+	file, path, err := f.resolve(name)
+	if err != nil {
+		return err, ""
+	}
+	if file.read != nil {
+		data, err := file.read(f, path)
+		return err, data
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err, ""
+	}
+	return nil, string(data)
+}
+
+func (f *legacyFileSystem) Write(name string, data string) error {
+	file, path, err := f.resolve(name)
+	if err != nil {
+		return err
+	}
+	if file.write != nil {
+		return file.write(f, path, data)
+	}
+	if file.read != nil {
+		return constant.ErrUnsupportedAction.WrapMessage(name)
+	}
+	return os.WriteFile(path, []byte(data), 0644)
+}
+
+func (f *legacyFileSystem) WriteLines(name string, lines []string) error {
+	for _, line := range lines {
+		if err := f.Write(name, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *legacyFileSystem) Exists(name string) bool {
+	_, path, err := f.resolve(name)
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return err == nil
+}
+
+func (f *legacyFileSystem) WatchPath(name string) (string, error) {
+	file, path, err := f.resolve(name)
+	if err != nil {
+		return "", err
+	}
+	if file.read != nil {
+		return "", constant.ErrUnsupportedAction.WrapMessage("watch " + name)
+	}
+	return path, nil
+}
+
+func (f *legacyFileSystem) Remove() error {
+	for _, controller := range joinedControllers() {
+		if err := removeCgroupDir(filepath.Join(util.CgroupV1Mounts()[controller], f.cgroup)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolve returns the counterpart of a file of cgroup v2 and its path.
+func (f *legacyFileSystem) resolve(name string) (legacyFile, string, error) {
+	file, ok := legacyFiles[name]
+	if !ok {
+		return file, "", notProvided(name)
+	}
+	controller := file.controller
+	if controller == "" {
+		if controller = primaryController(); controller == "" {
+			return file, "", notProvided(name)
+		}
+	}
+	dir, err := f.dir(controller)
+	if err != nil {
+		return file, "", err
+	}
+	return file, filepath.Join(dir, file.file), nil
+}
+
+// dir returns the cgroup in the hierarchy of a controller, which is created if autoCreate is set.
+func (f *legacyFileSystem) dir(controller string) (string, error) {
+	mount, ok := util.CgroupV1Mounts()[controller]
+	if !ok {
+		return "", notProvided(controller)
+	}
+	dir := filepath.Join(mount, f.cgroup)
+	_, err := os.Stat(dir)
+	if err == nil || !f.autoCreate || !errors.Is(err, os.ErrNotExist) {
+		return dir, err
+	}
+	if err = os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	if controller == "cpuset" {
+		return dir, inheritCpuset(mount, f.cgroup)
+	}
+	return dir, nil
+}
+
+// joinedControllers returns a controller of every hierarchy a container joins, controllers mounted
+// together share a hierarchy.
+func joinedControllers() []string {
+	var controllers []string
+	seen := make(map[string]bool)
+	for _, controller := range legacyControllers {
+		mount, ok := util.CgroupV1Mounts()[controller]
+		if !ok || seen[mount] {
+			continue
+		}
+		seen[mount] = true
+		controllers = append(controllers, controller)
+	}
+	return controllers
+}
+
+func primaryController() string {
+	for _, controller := range legacyControllers {
+		if _, ok := util.CgroupV1Mounts()[controller]; ok {
+			return controller
+		}
+	}
+	return ""
+}
+
+// inheritCpuset copies cpuset.cpus and cpuset.mems from the parent into every cgroup down to the
+// given one which has none, a cgroup of cgroup v1 takes no process before they are set.
+func inheritCpuset(mount string, cgroup string) error {
+	parent := mount
+	for _, name := range strings.Split(cgroup, string(filepath.Separator)) {
+		dir := filepath.Join(parent, name)
+		for _, file := range []string{"cpuset.cpus", "cpuset.mems"} {
+			data, err := os.ReadFile(filepath.Join(dir, file))
+			if err != nil {
+				return err
+			}
+			if strings.TrimSpace(string(data)) != "" {
+				continue
+			}
+			if data, err = os.ReadFile(filepath.Join(parent, file)); err != nil {
+				return err
+			}
+			if err = os.WriteFile(filepath.Join(dir, file), data, 0644); err != nil {
+				return err
+			}
+		}
+		parent = dir
+	}
+	return nil
+}
+
+func notProvided(name string) error {
+	return fmt.Errorf("%s is not provided by cgroup v1: %w", name, os.ErrNotExist)
+}
+
+// writeLegacyProcs moves the processes into the cgroup of every hierarchy, the kernel takes a
+// single pid per write.
+func writeLegacyProcs(f *legacyFileSystem, _ string, data string) error {
+	for _, controller := range joinedControllers() {
+		dir, err := f.dir(controller)
+		if err != nil {
+			return err
+		}
+		for _, pid := range strings.Fields(data) {
+			if err := os.WriteFile(filepath.Join(dir, constant.CgroupProcs), []byte(pid), 0644); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// readLegacyFreeze maps freezer.state onto cgroup.freeze, a cgroup which is still FREEZING has
+// been asked to freeze.
+func readLegacyFreeze(_ *legacyFileSystem, path string) (string, error) {
+	state, err := readLegacyString(path)
+	if err != nil {
+		return "", err
+	}
+	if state == "THAWED" {
+		return "0", nil
+	}
+	return "1", nil
+}
+
+func writeLegacyFreeze(_ *legacyFileSystem, path string, data string) error {
+	state := "THAWED"
+	if strings.TrimSpace(data) == "1" {
+		state = "FROZEN"
+	}
+	return os.WriteFile(path, []byte(state), 0644)
+}
+
+// readLegacyEvents makes up cgroup.events from the processes of the cgroup and freezer.state.
+func readLegacyEvents(_ *legacyFileSystem, path string) (string, error) {
+	state, err := readLegacyString(path)
+	if err != nil {
+		return "", err
+	}
+	procs, err := readLegacyString(siblingPath(path, constant.CgroupProcs))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("populated %d\nfrozen %d\n", boolToInt(procs != ""), boolToInt(state == "FROZEN")), nil
+}
+
+func readLegacyCpuMax(_ *legacyFileSystem, path string) (string, error) {
+	quota, err := readLegacyInt(path)
+	if err != nil {
+		return "", err
+	}
+	period, err := readLegacyInt(siblingPath(path, "cpu.cfs_period_us"))
+	if err != nil {
+		return "", err
+	}
+	if quota < 0 {
+		return fmt.Sprintf("%s %d", constant.LiteralMax, period), nil
+	}
+	return fmt.Sprintf("%d %d", quota, period), nil
+}
+
+// writeLegacyCpuMax writes the period first, the kernel checks the quota against it.
+func writeLegacyCpuMax(_ *legacyFileSystem, path string, data string) error {
+	v := cpu.MaxValue{}
+	if err := v.From(data); err != nil {
+		return err
+	}
+	if err := os.WriteFile(siblingPath(path, "cpu.cfs_period_us"), []byte(strconv.Itoa(v.Period)), 0644); err != nil {
+		return err
+	}
+	quota := v.Quota
+	if quota == math.MaxInt {
+		quota = -1
+	}
+	return os.WriteFile(path, []byte(strconv.Itoa(quota)), 0644)
+}
+
+func readLegacyCpuWeight(_ *legacyFileSystem, path string) (string, error) {
+	shares, err := readLegacyInt(path)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatUint(cpu.SharesToWeight(uint64(shares)), 10), nil
+}
+
+// writeLegacyCpuWeight leaves cpu.shares as it is if it maps onto the weight already, the
+// mapping is not one to one and writing the weight back would change the shares.
+func writeLegacyCpuWeight(_ *legacyFileSystem, path string, data string) error {
+	weight, err := strconv.ParseUint(strings.TrimSpace(data), 10, 64)
+	if err != nil {
+		return err
+	}
+	if shares, err := readLegacyInt(path); err == nil && cpu.SharesToWeight(uint64(shares)) == weight {
+		return nil
+	}
+	return os.WriteFile(path, []byte(strconv.FormatUint(cpu.WeightToShares(weight), 10)), 0644)
+}
+
+// readLegacyCpuStat makes up cpu.stat from cpuacct, which counts in nanoseconds and USER_HZ, and
+// the throttling statistics of the cpu controller.
+func readLegacyCpuStat(f *legacyFileSystem, path string) (string, error) {
+	usage, err := readLegacyInt(path)
+	if err != nil {
+		return "", err
+	}
+	times, err := readLegacyKeyValues(siblingPath(path, "cpuacct.stat"))
+	if err != nil {
+		return "", err
+	}
+	stat := fmt.Sprintf("usage_usec %d\nuser_usec %d\nsystem_usec %d\n",
+		usage/1000, times["user"]*1000000/userHz, times["system"]*1000000/userHz)
+	if dir, err := f.dir("cpu"); err == nil {
+		if throttling, err := readLegacyKeyValues(filepath.Join(dir, "cpu.stat")); err == nil {
+			stat += fmt.Sprintf("nr_periods %d\nnr_throttled %d\nthrottled_usec %d\n",
+				throttling["nr_periods"], throttling["nr_throttled"], throttling["throttled_time"]/1000)
+		}
+	}
+	return stat, nil
+}
+
+// readLegacyLimit maps a memory limit which is not set onto max.
+func readLegacyLimit(_ *legacyFileSystem, path string) (string, error) {
+	bytes, err := readLegacyInt(path)
+	if err != nil {
+		return "", err
+	}
+	if bytes >= legacyUnlimited {
+		return constant.LiteralMax, nil
+	}
+	return strconv.FormatInt(bytes, 10), nil
+}
+
+func writeLegacyLimit(_ *legacyFileSystem, path string, data string) error {
+	bytes, err := parseLegacyLimit(data)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(strconv.FormatInt(bytes, 10)), 0644)
+}
+
+// writeLegacyMemoryMax raises memory.memsw.limit_in_bytes first if it is below the new limit, the
+// kernel keeps the limit of memory and swap above the one of memory.
+func writeLegacyMemoryMax(f *legacyFileSystem, path string, data string) error {
+	bytes, err := parseLegacyLimit(data)
+	if err != nil {
+		return err
+	}
+	memsw := siblingPath(path, "memory.memsw.limit_in_bytes")
+	if current, err := readLegacyInt(memsw); err == nil && (bytes < 0 || bytes > current) {
+		if err = os.WriteFile(memsw, []byte(strconv.FormatInt(bytes, 10)), 0644); err != nil {
+			return err
+		}
+	}
+	return writeLegacyLimit(f, path, data)
+}
+
+// readLegacySwapMax tells the swap limit from the limit of memory and swap together.
+func readLegacySwapMax(_ *legacyFileSystem, path string) (string, error) {
+	memsw, err := readLegacyInt(path)
+	if err != nil {
+		return "", err
+	}
+	memory, err := readLegacyInt(siblingPath(path, "memory.limit_in_bytes"))
+	if err != nil {
+		return "", err
+	}
+	if memsw >= legacyUnlimited {
+		return constant.LiteralMax, nil
+	}
+	return strconv.FormatInt(memsw-memory, 10), nil
+}
+
+// writeLegacySwapMax limits memory and swap together to the memory limit plus the swap, so it
+// must be written after memory.max.
+func writeLegacySwapMax(_ *legacyFileSystem, path string, data string) error {
+	swap, err := parseLegacyLimit(data)
+	if err != nil {
+		return err
+	}
+	memory, err := readLegacyInt(siblingPath(path, "memory.limit_in_bytes"))
+	if err != nil {
+		return err
+	}
+	memsw := int64(-1)
+	if swap >= 0 && memory < legacyUnlimited {
+		memsw = memory + swap
+	}
+	return os.WriteFile(path, []byte(strconv.FormatInt(memsw, 10)), 0644)
+}
+
+// readLegacyMemoryStat renames the entries of memory.stat which have a counterpart in cgroup v2.
+func readLegacyMemoryStat(_ *legacyFileSystem, path string) (string, error) {
+	stat, err := readLegacyKeyValues(path)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("anon %d\nfile %d\nshmem %d\nactive_file %d\ninactive_file %d\n",
+		stat["rss"], stat["cache"], stat["shmem"], stat["active_file"], stat["inactive_file"]), nil
+}
+
+// readLegacyMemoryEvents makes up memory.events from the number of OOM kills in
+// memory.oom_control and the number of times the limit was hit.
+func readLegacyMemoryEvents(_ *legacyFileSystem, path string) (string, error) {
+	control, err := readLegacyKeyValues(path)
+	if err != nil {
+		return "", err
+	}
+	failures, err := readLegacyInt(siblingPath(path, "memory.failcnt"))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("max %d\noom_kill %d\n", failures, control["oom_kill"]), nil
+}
+
+// legacyThrottleFiles are the files io.max is split into, in the order of the rates of io.max.
+var legacyThrottleFiles = []string{
+	"blkio.throttle.read_bps_device",
+	"blkio.throttle.write_bps_device",
+	"blkio.throttle.read_iops_device",
+	"blkio.throttle.write_iops_device",
+}
+
+// readLegacyIoMax joins the throttle files of blkio into a line per device.
+func readLegacyIoMax(_ *legacyFileSystem, path string) (string, error) {
+	devices := make(map[string]*io.DeviceLimit)
+	var order []string
+	for i, file := range legacyThrottleFiles {
+		data, err := os.ReadFile(siblingPath(path, file))
+		if err != nil {
+			return "", err
+		}
+		for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			fields := strings.Fields(line)
+			if len(fields) != 2 {
+				continue
+			}
+			rate, err := strconv.ParseUint(fields[1], 10, 64)
+			if err != nil {
+				return "", err
+			}
+			d, ok := devices[fields[0]]
+			if !ok {
+				d = &io.DeviceLimit{}
+				if _, err = fmt.Sscanf(fields[0], "%d:%d", &d.Major, &d.Minor); err != nil {
+					return "", constant.ErrMalformedType
+				}
+				devices[fields[0]] = d
+				order = append(order, fields[0])
+			}
+			switch i {
+			case 0:
+				d.Rbps = rate
+			case 1:
+				d.Wbps = rate
+			case 2:
+				d.Riops = rate
+			case 3:
+				d.Wiops = rate
+			}
+		}
+	}
+	lines := make([]string, 0, len(order))
+	for _, device := range order {
+		lines = append(lines, devices[device].String())
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// writeLegacyIoMax writes every rate of the devices into its throttle file, a rate of 0 removes
+// the limit in both versions.
+func writeLegacyIoMax(_ *legacyFileSystem, path string, data string) error {
+	v := io.MaxValue{}
+	if err := v.From(data); err != nil {
+		return err
+	}
+	for _, d := range v.Devices {
+		for i, rate := range []uint64{d.Rbps, d.Wbps, d.Riops, d.Wiops} {
+			line := fmt.Sprintf("%d:%d %d", d.Major, d.Minor, rate)
+			if err := os.WriteFile(siblingPath(path, legacyThrottleFiles[i]), []byte(line), 0644); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func readLegacyIoWeight(_ *legacyFileSystem, path string) (string, error) {
+	weight, err := readLegacyInt(path)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("default %d", io.BlkioWeightToIoWeight(uint16(weight))), nil
+}
+
+// writeLegacyIoWeight writes the default weight, blkio.weight has no weights per device.
+func writeLegacyIoWeight(_ *legacyFileSystem, path string, data string) error {
+	v := io.WeightValue{}
+	if err := v.From(data); err != nil {
+		return err
+	}
+	if len(v.Devices) > 0 {
+		return constant.ErrUnsupportedAction.WrapMessage("io.weight of a device")
+	}
+	if current, err := readLegacyInt(path); err == nil && io.BlkioWeightToIoWeight(uint16(current)) == v.Default {
+		return nil
+	}
+	return os.WriteFile(path, []byte(strconv.Itoa(int(io.IoWeightToBlkioWeight(v.Default)))), 0644)
+}
+
+// readLegacyIoStat makes up io.stat from the bytes and operations counted by the throttling of
+// blkio, which counts them whether a device is throttled or not.
+func readLegacyIoStat(_ *legacyFileSystem, path string) (string, error) {
+	devices := make(map[string]*io.DeviceStat)
+	var order []string
+	for _, file := range []string{"blkio.throttle.io_service_bytes", "blkio.throttle.io_serviced"} {
+		data, err := os.ReadFile(siblingPath(path, file))
+		if err != nil {
+			return "", err
+		}
+		for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			// 8:16 Read 1459200, the lines without a device are the totals
+			fields := strings.Fields(line)
+			if len(fields) != 3 {
+				continue
+			}
+			count, err := strconv.ParseUint(fields[2], 10, 64)
+			if err != nil {
+				return "", err
+			}
+			d, ok := devices[fields[0]]
+			if !ok {
+				d = &io.DeviceStat{}
+				if _, err = fmt.Sscanf(fields[0], "%d:%d", &d.Major, &d.Minor); err != nil {
+					return "", constant.ErrMalformedType
+				}
+				devices[fields[0]] = d
+				order = append(order, fields[0])
+			}
+			bytes := file == "blkio.throttle.io_service_bytes"
+			switch {
+			case fields[1] == "Read" && bytes:
+				d.Rbytes = count
+			case fields[1] == "Write" && bytes:
+				d.Wbytes = count
+			case fields[1] == "Read":
+				d.Rios = count
+			case fields[1] == "Write":
+				d.Wios = count
+			}
+		}
+	}
+	lines := make([]string, 0, len(order))
+	for _, device := range order {
+		d := devices[device]
+		lines = append(lines, fmt.Sprintf("%s rbytes=%d wbytes=%d rios=%d wios=%d", device, d.Rbytes, d.Wbytes, d.Rios, d.Wios))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// parseLegacyLimit parses a memory limit of cgroup v2, max is -1 in cgroup v1.
+func parseLegacyLimit(data string) (int64, error) {
+	data = strings.TrimSpace(data)
+	if data == constant.LiteralMax {
+		return -1, nil
+	}
+	bytes, err := strconv.ParseInt(data, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if bytes >= legacyUnlimited {
+		return -1, nil
+	}
+	return bytes, nil
+}
+
+func siblingPath(path string, name string) string {
+	return filepath.Join(filepath.Dir(path), name)
+}
+
+func readLegacyString(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	return strings.TrimSpace(string(data)), err
+}
+
+func readLegacyInt(path string) (int64, error) {
+	s, err := readLegacyString(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(s, 10, 64)
+}
+
+// readLegacyKeyValues reads a file with a key and a number per line, like memory.stat.
+func readLegacyKeyValues(path string) (map[string]int64, error) {
+	s, err := readLegacyString(path)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]int64)
+	for _, line := range strings.Split(s, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		if value, err := strconv.ParseInt(fields[1], 10, 64); err == nil {
+			values[fields[0]] = value
+		}
+	}
+	return values, nil
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,222 @@
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0x822a5b87/tiny-docker/src/subsystem/cpu"
+	"github.com/stretchr/testify/assert"
+)
+
+// legacyUnlimitedBytes is what cgroup v1 reports for a memory limit which is not set.
+const legacyUnlimitedBytes = "9223372036854771712"
+
+// newLegacyFixture writes the files of a cgroup v1 into a temporary directory, a file is read and
+// written by the conversions through its path.
+func newLegacyFixture(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, data := range files {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(data), 0644))
+	}
+	return dir
+}
+
+func readFixture(t *testing.T, dir string, name string) string {
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	assert.NoError(t, err)
+	return string(data)
+}
+
+func TestLegacyCpuMax(t *testing.T) {
+	dir := newLegacyFixture(t, map[string]string{"cpu.cfs_quota_us": "-1\n", "cpu.cfs_period_us": "100000\n"})
+	quota := filepath.Join(dir, "cpu.cfs_quota_us")
+
+	data, err := readLegacyCpuMax(nil, quota)
+	assert.NoError(t, err)
+	assert.Equal(t, "max 100000", data)
+
+	assert.NoError(t, writeLegacyCpuMax(nil, quota, "25000 50000"))
+	assert.Equal(t, "50000", readFixture(t, dir, "cpu.cfs_period_us"))
+	assert.Equal(t, "25000", readFixture(t, dir, "cpu.cfs_quota_us"))
+	data, err = readLegacyCpuMax(nil, quota)
+	assert.NoError(t, err)
+	assert.Equal(t, "25000 50000", data)
+
+	assert.NoError(t, writeLegacyCpuMax(nil, quota, "max 100000"))
+	assert.Equal(t, "-1", readFixture(t, dir, "cpu.cfs_quota_us"))
+	assert.Error(t, writeLegacyCpuMax(nil, quota, "25000"))
+}
+
+func TestSharesWeightRoundTrip(t *testing.T) {
+	for weight := uint64(cpu.MinWeight); weight <= cpu.MaxWeight; weight++ {
+		shares := cpu.WeightToShares(weight)
+		assert.True(t, shares >= cpu.MinShares && shares <= cpu.MaxShares, weight)
+		assert.Equal(t, weight, cpu.SharesToWeight(shares), weight)
+	}
+	assert.Equal(t, uint64(1), cpu.SharesToWeight(cpu.MinShares))
+	assert.Equal(t, uint64(cpu.MaxWeight), cpu.SharesToWeight(cpu.MaxShares))
+}
+
+func TestLegacyCpuWeight(t *testing.T) {
+	dir := newLegacyFixture(t, map[string]string{"cpu.shares": "1024\n"})
+	shares := filepath.Join(dir, "cpu.shares")
+
+	data, err := readLegacyCpuWeight(nil, shares)
+	assert.NoError(t, err)
+	assert.Equal(t, "39", data)
+
+	// the shares which map onto the weight already are kept
+	assert.NoError(t, writeLegacyCpuWeight(nil, shares, "39"))
+	assert.Equal(t, "1024\n", readFixture(t, dir, "cpu.shares"))
+
+	assert.NoError(t, writeLegacyCpuWeight(nil, shares, "100"))
+	data, err = readLegacyCpuWeight(nil, shares)
+	assert.NoError(t, err)
+	assert.Equal(t, "100", data)
+}
+
+func TestLegacyMemoryLimits(t *testing.T) {
+	dir := newLegacyFixture(t, map[string]string{
+		"memory.limit_in_bytes":       legacyUnlimitedBytes,
+		"memory.memsw.limit_in_bytes": legacyUnlimitedBytes,
+	})
+	memory := filepath.Join(dir, "memory.limit_in_bytes")
+	memsw := filepath.Join(dir, "memory.memsw.limit_in_bytes")
+
+	data, err := readLegacyLimit(nil, memory)
+	assert.NoError(t, err)
+	assert.Equal(t, "max", data)
+	data, err = readLegacySwapMax(nil, memsw)
+	assert.NoError(t, err)
+	assert.Equal(t, "max", data)
+
+	// lowering the memory limit leaves the limit of memory and swap to memory.swap.max
+	assert.NoError(t, writeLegacyMemoryMax(nil, memory, "1048576"))
+	assert.Equal(t, "1048576", readFixture(t, dir, "memory.limit_in_bytes"))
+	assert.Equal(t, legacyUnlimitedBytes, readFixture(t, dir, "memory.memsw.limit_in_bytes"))
+
+	// the swap is on top of the memory limit
+	assert.NoError(t, writeLegacySwapMax(nil, memsw, "1048576"))
+	assert.Equal(t, "2097152", readFixture(t, dir, "memory.memsw.limit_in_bytes"))
+	data, err = readLegacySwapMax(nil, memsw)
+	assert.NoError(t, err)
+	assert.Equal(t, "1048576", data)
+
+	// raising the memory limit above memory and swap raises that first
+	assert.NoError(t, writeLegacyMemoryMax(nil, memory, "4194304"))
+	assert.Equal(t, "4194304", readFixture(t, dir, "memory.memsw.limit_in_bytes"))
+	assert.Equal(t, "4194304", readFixture(t, dir, "memory.limit_in_bytes"))
+
+	assert.NoError(t, writeLegacyMemoryMax(nil, memory, "max"))
+	assert.Equal(t, "-1", readFixture(t, dir, "memory.memsw.limit_in_bytes"))
+	assert.Equal(t, "-1", readFixture(t, dir, "memory.limit_in_bytes"))
+
+	// no swap limit without a memory limit
+	assert.NoError(t, os.WriteFile(memory, []byte(legacyUnlimitedBytes), 0644))
+	assert.NoError(t, writeLegacySwapMax(nil, memsw, "1048576"))
+	assert.Equal(t, "-1", readFixture(t, dir, "memory.memsw.limit_in_bytes"))
+}
+
+func TestLegacyIoMax(t *testing.T) {
+	dir := newLegacyFixture(t, map[string]string{
+		"blkio.throttle.read_bps_device":   "8:0 1048576\n8:16 100\n",
+		"blkio.throttle.write_bps_device":  "8:0 2048\n",
+		"blkio.throttle.read_iops_device":  "",
+		"blkio.throttle.write_iops_device": "8:16 10\n",
+	})
+	path := filepath.Join(dir, "blkio.throttle.read_bps_device")
+
+	data, err := readLegacyIoMax(nil, path)
+	assert.NoError(t, err)
+	assert.Equal(t, "8:0 rbps=1048576 wbps=2048 riops=max wiops=max\n8:16 rbps=100 wbps=max riops=max wiops=10", data)
+
+	assert.NoError(t, writeLegacyIoMax(nil, path, "8:32 rbps=max wbps=4096 riops=max wiops=20"))
+	assert.Equal(t, "8:32 0", readFixture(t, dir, "blkio.throttle.read_bps_device"))
+	assert.Equal(t, "8:32 4096", readFixture(t, dir, "blkio.throttle.write_bps_device"))
+	assert.Equal(t, "8:32 0", readFixture(t, dir, "blkio.throttle.read_iops_device"))
+	assert.Equal(t, "8:32 20", readFixture(t, dir, "blkio.throttle.write_iops_device"))
+
+	assert.NoError(t, os.WriteFile(path, []byte("sda 100\n"), 0644))
+	_, err = readLegacyIoMax(nil, path)
+	assert.Error(t, err)
+}
+
+func TestLegacyIoStat(t *testing.T) {
+	dir := newLegacyFixture(t, map[string]string{
+		"blkio.throttle.io_service_bytes": "8:0 Read 4096\n8:0 Write 8192\n8:0 Sync 0\n8:0 Async 12288\n8:0 Total 12288\n" +
+			"8:16 Read 512\n8:16 Write 0\n8:16 Total 512\nTotal 12800\n",
+		"blkio.throttle.io_serviced": "8:0 Read 1\n8:0 Write 2\n8:0 Total 3\n8:16 Read 1\n8:16 Total 1\nTotal 4\n",
+	})
+
+	data, err := readLegacyIoStat(nil, filepath.Join(dir, "blkio.throttle.io_service_bytes"))
+	assert.NoError(t, err)
+	assert.Equal(t, "8:0 rbytes=4096 wbytes=8192 rios=1 wios=2\n8:16 rbytes=512 wbytes=0 rios=1 wios=0", data)
+}
+
+func TestLegacyFreezeAndEvents(t *testing.T) {
+	dir := newLegacyFixture(t, map[string]string{"freezer.state": "THAWED\n", "cgroup.procs": "42\n"})
+	state := filepath.Join(dir, "freezer.state")
+
+	data, err := readLegacyFreeze(nil, state)
+	assert.NoError(t, err)
+	assert.Equal(t, "0", data)
+	data, err = readLegacyEvents(nil, state)
+	assert.NoError(t, err)
+	assert.Equal(t, "populated 1\nfrozen 0\n", data)
+
+	assert.NoError(t, writeLegacyFreeze(nil, state, "1"))
+	assert.Equal(t, "FROZEN", readFixture(t, dir, "freezer.state"))
+	data, err = readLegacyEvents(nil, state)
+	assert.NoError(t, err)
+	assert.Equal(t, "populated 1\nfrozen 1\n", data)
+
+	// a cgroup which is still freezing has been asked to freeze
+	assert.NoError(t, os.WriteFile(state, []byte("FREEZING\n"), 0644))
+	data, err = readLegacyFreeze(nil, state)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", data)
+}
+
+func TestLegacyMemoryEventsAndStat(t *testing.T) {
+	dir := newLegacyFixture(t, map[string]string{
+		"memory.oom_control": "oom_kill_disable 0\nunder_oom 0\noom_kill 2\n",
+		"memory.failcnt":     "5\n",
+		"memory.stat":        "cache 4096\nrss 8192\nshmem 0\nactive_file 1024\ninactive_file 3072\n",
+	})
+
+	data, err := readLegacyMemoryEvents(nil, filepath.Join(dir, "memory.oom_control"))
+	assert.NoError(t, err)
+	assert.Equal(t, "max 5\noom_kill 2\n", data)
+
+	data, err = readLegacyMemoryStat(nil, filepath.Join(dir, "memory.stat"))
+	assert.NoError(t, err)
+	assert.Equal(t, "anon 8192\nfile 4096\nshmem 0\nactive_file 1024\ninactive_file 3072\n", data)
+}
+
+func TestLegacyCpuStat(t *testing.T) {
+	dir := newLegacyFixture(t, map[string]string{
+		"cpuacct.usage": "3000000000\n",
+		"cpuacct.stat":  "user 150\nsystem 50\n",
+	})
+	// the cgroup does not exist in the cpu hierarchy, so there are no throttling statistics
+	f := &legacyFileSystem{cgroup: "tiny-docker-test/missing"}
+
+	data, err := readLegacyCpuStat(f, filepath.Join(dir, "cpuacct.usage"))
+	assert.NoError(t, err)
+	assert.Equal(t, "usage_usec 3000000\nuser_usec 1500000\nsystem_usec 500000\n", data)
+}
+
+func TestParseLegacyLimit(t *testing.T) {
+	for data, expected := range map[string]int64{
+		"max":                -1,
+		"1048576\n":          1048576,
+		legacyUnlimitedBytes: -1,
+	} {
+		bytes, err := parseLegacyLimit(data)
+		assert.NoError(t, err, data)
+		assert.Equal(t, expected, bytes, data)
+	}
+	_, err := parseLegacyLimit("1g")
+	assert.Error(t, err)
+}
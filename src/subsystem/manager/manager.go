@@ -1,49 +1,167 @@
 package manager
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
-	"github.com/0x822a5b87/tiny-docker/src/conf"
 	"github.com/0x822a5b87/tiny-docker/src/constant"
 	"github.com/0x822a5b87/tiny-docker/src/subsystem"
 	"github.com/0x822a5b87/tiny-docker/src/subsystem/cgroup"
 	"github.com/0x822a5b87/tiny-docker/src/subsystem/cpu"
+	"github.com/0x822a5b87/tiny-docker/src/subsystem/cpuset"
+	"github.com/0x822a5b87/tiny-docker/src/subsystem/io"
 	"github.com/0x822a5b87/tiny-docker/src/subsystem/memory"
-	"github.com/0x822a5b87/tiny-docker/src/util"
+	"github.com/0x822a5b87/tiny-docker/src/subsystem/pids"
 	"github.com/sirupsen/logrus"
 )
 
+// freezeTimeout bounds the wait for the kernel to freeze or thaw all processes of a cgroup.
+const freezeTimeout = 10 * time.Second
+
 type CgroupManager struct {
-	fs                 *CgroupFileSystem
-	procsSubsystem     *cgroup.ProcsValueSubsystem
-	cpuMaxSubsystem    *cpu.MaxValueSubsystem
+	fs              CgroupFileSystem
+	procsSubsystem  *cgroup.ProcsValueSubsystem
+	cpuMaxSubsystem *cpu.MaxValueSubsystem
+	// cpu.weight is missing if the cpu controller is not enabled
+	cpuWeightSubsystem *cpu.WeightValueSubsystem
 	memoryMaxSubsystem *memory.MaxValueSubsystem
+	// memory.low is missing if the memory controller is not enabled
+	memoryLowSubsystem *memory.LimitValueSubsystem
+	// pids.max is missing if the pids controller is not enabled
+	pidsMaxSubsystem *pids.MaxValueSubsystem
+	// cgroup.freeze is missing with kernels before 5.2 and in cgroup v1 without the freezer
+	freezeSubsystem *cgroup.FreezeValueSubsystem
+	// memory.high and memory.min have no counterpart in cgroup v1, where they are nil
+	memoryHighSubsystem *memory.LimitValueSubsystem
+	memoryMinSubsystem  *memory.LimitValueSubsystem
+	// the io subsystems are nil if the kernel does not provide their files
+	ioMaxSubsystem    *io.MaxValueSubsystem
+	ioWeightSubsystem *io.WeightValueSubsystem
+	// memory.swap.max is missing without swap accounting, memory.oom.group with kernels before 4.19
+	memorySwapMaxSubsystem  *memory.LimitValueSubsystem
+	memoryOomGroupSubsystem *memory.OomGroupValueSubsystem
+	// the cpuset subsystems are nil if the cpuset controller is not enabled
+	cpusetCpusSubsystem *cpuset.CpusValueSubsystem
+	cpusetMemsSubsystem *cpuset.MemsValueSubsystem
 }
 
-func NewCgroupManager(pid int) (*CgroupManager, error) {
-	path := util.GenContainerCgroupPath(conf.GlobalConfig.Cmd.Id)
-	fs := NewCgroupFileSystem(path, true)
+// NewCgroupManager creates the cgroup of a container and moves pid into it, containerCgroup is
+// relative to the root of a hierarchy.
+func NewCgroupManager(containerCgroup string, pid int) (*CgroupManager, error) {
+	fs := NewCgroupFileSystem(containerCgroup, true)
 	procsSubsystem, err := newSubsystem[*cgroup.ProcsValueSubsystem](fs, constant.CgroupProcs)
 	if err != nil {
 		return nil, err
 	}
 	err = procsSubsystem.Set(cgroup.ProcsItem(pid))
+	return loadCgroupManager(fs, procsSubsystem)
+}
+
+// LoadCgroupManager loads the cgroup of an existing container, mini-dockerd uses it to change a
+// container after it started.
+func LoadCgroupManager(containerCgroup string) (*CgroupManager, error) {
+	fs := NewCgroupFileSystem(containerCgroup, false)
+	procsSubsystem, err := newSubsystem[*cgroup.ProcsValueSubsystem](fs, constant.CgroupProcs)
+	if err != nil {
+		return nil, err
+	}
+	return loadCgroupManager(fs, procsSubsystem)
+}
 
+func loadCgroupManager(fs CgroupFileSystem, procsSubsystem *cgroup.ProcsValueSubsystem) (*CgroupManager, error) {
 	cpuMaxSubsystem, err := newSubsystem[*cpu.MaxValueSubsystem](fs, constant.CpuMax)
 	if err != nil {
 		return nil, err
 	}
 
+	cpuWeightSubsystem, err := newOptionalSubsystem[*cpu.WeightValueSubsystem](fs, constant.CpuWeight)
+	if err != nil {
+		return nil, err
+	}
+
 	memoryMaxValueSubsystem, err := newSubsystem[*memory.MaxValueSubsystem](fs, constant.MemoryMax)
 	if err != nil {
 		return nil, err
 	}
 
+	memoryHighSubsystem, err := newOptionalSubsystem[*memory.LimitValueSubsystem](fs, constant.MemoryHigh)
+	if err != nil {
+		return nil, err
+	}
+
+	memoryLowSubsystem, err := newOptionalSubsystem[*memory.LimitValueSubsystem](fs, constant.MemoryLow)
+	if err != nil {
+		return nil, err
+	}
+
+	memoryMinSubsystem, err := newOptionalSubsystem[*memory.LimitValueSubsystem](fs, constant.MemoryMin)
+	if err != nil {
+		return nil, err
+	}
+
+	memorySwapMaxSubsystem, err := newOptionalSubsystem[*memory.LimitValueSubsystem](fs, constant.MemorySwapMax)
+	if err != nil {
+		return nil, err
+	}
+
+	memoryOomGroupSubsystem, err := newOptionalSubsystem[*memory.OomGroupValueSubsystem](fs, constant.MemoryOomGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	pidsMaxSubsystem, err := newOptionalSubsystem[*pids.MaxValueSubsystem](fs, constant.PidsMax)
+	if err != nil {
+		return nil, err
+	}
+
+	freezeSubsystem, err := newOptionalSubsystem[*cgroup.FreezeValueSubsystem](fs, constant.CgroupFreeze)
+	if err != nil {
+		return nil, err
+	}
+
+	ioMaxSubsystem, err := newOptionalSubsystem[*io.MaxValueSubsystem](fs, constant.IoMax)
+	if err != nil {
+		return nil, err
+	}
+
+	ioWeightSubsystem, err := newOptionalSubsystem[*io.WeightValueSubsystem](fs, constant.IoWeight)
+	if err != nil {
+		return nil, err
+	}
+
+	cpusetCpusSubsystem, err := newOptionalSubsystem[*cpuset.CpusValueSubsystem](fs, constant.CpusetCpus)
+	if err != nil {
+		return nil, err
+	}
+
+	cpusetMemsSubsystem, err := newOptionalSubsystem[*cpuset.MemsValueSubsystem](fs, constant.CpusetMems)
+	if err != nil {
+		return nil, err
+	}
+
 	return &CgroupManager{
-		fs:                 fs,
-		procsSubsystem:     procsSubsystem,
-		cpuMaxSubsystem:    cpuMaxSubsystem,
-		memoryMaxSubsystem: memoryMaxValueSubsystem,
+		fs:                      fs,
+		procsSubsystem:          procsSubsystem,
+		cpuMaxSubsystem:         cpuMaxSubsystem,
+		cpuWeightSubsystem:      cpuWeightSubsystem,
+		memoryMaxSubsystem:      memoryMaxValueSubsystem,
+		memoryHighSubsystem:     memoryHighSubsystem,
+		memoryLowSubsystem:      memoryLowSubsystem,
+		memoryMinSubsystem:      memoryMinSubsystem,
+		memorySwapMaxSubsystem:  memorySwapMaxSubsystem,
+		memoryOomGroupSubsystem: memoryOomGroupSubsystem,
+		pidsMaxSubsystem:        pidsMaxSubsystem,
+		freezeSubsystem:         freezeSubsystem,
+		ioMaxSubsystem:          ioMaxSubsystem,
+		ioWeightSubsystem:       ioWeightSubsystem,
+		cpusetCpusSubsystem:     cpusetCpusSubsystem,
+		cpusetMemsSubsystem:     cpusetMemsSubsystem,
 	}, nil
 }
 
@@ -52,31 +170,220 @@ func (m *CgroupManager) SetMemoryMax(memoryLimit int) error {
 	return m.memoryMaxSubsystem.Set(item)
 }
 
+// SetMemoryHigh throttles the container and reclaims its memory hard once it uses more than bytes,
+// memory.Unlimited removes the limit.
+func (m *CgroupManager) SetMemoryHigh(bytes int64) error {
+	if m.memoryHighSubsystem == nil {
+		return constant.ErrUnsupportedAction.WrapMessage(constant.MemoryHigh)
+	}
+	return m.memoryHighSubsystem.Set(memory.LimitItem{Bytes: bytes})
+}
+
+// SetMemoryLow protects bytes of the memory of the container from reclaim as long as memory can be
+// reclaimed from unprotected cgroups.
+func (m *CgroupManager) SetMemoryLow(bytes int64) error {
+	if m.memoryLowSubsystem == nil {
+		return constant.ErrUnsupportedAction.WrapMessage(constant.MemoryLow)
+	}
+	return m.memoryLowSubsystem.Set(memory.LimitItem{Bytes: bytes})
+}
+
+// SetMemoryMin protects bytes of the memory of the container from reclaim in any case.
+func (m *CgroupManager) SetMemoryMin(bytes int64) error {
+	if m.memoryMinSubsystem == nil {
+		return constant.ErrUnsupportedAction.WrapMessage(constant.MemoryMin)
+	}
+	return m.memoryMinSubsystem.Set(memory.LimitItem{Bytes: bytes})
+}
+
+// SetMemorySwapMax limits the swap of the container alone, 0 disables swap.
+func (m *CgroupManager) SetMemorySwapMax(bytes int64) error {
+	if m.memorySwapMaxSubsystem == nil {
+		return constant.ErrUnsupportedAction.WrapMessage(constant.MemorySwapMax)
+	}
+	return m.memorySwapMaxSubsystem.Set(memory.LimitItem{Bytes: bytes})
+}
+
+// SetMemoryOomGroup makes the OOM killer kill all processes of the container together.
+func (m *CgroupManager) SetMemoryOomGroup(group bool) error {
+	if m.memoryOomGroupSubsystem == nil {
+		return constant.ErrUnsupportedAction.WrapMessage(constant.MemoryOomGroup)
+	}
+	return m.memoryOomGroupSubsystem.Set(memory.OomGroupItem(group))
+}
+
+// SetPidsMax limits the number of processes of the container, 0 removes the limit.
+func (m *CgroupManager) SetPidsMax(limit int64) error {
+	if m.pidsMaxSubsystem == nil {
+		return constant.ErrUnsupportedAction.WrapMessage(constant.PidsMax)
+	}
+	item := pids.MaxItem{Limit: limit}
+	return m.pidsMaxSubsystem.Set(item)
+}
+
+// SetIoMax throttles a block device, the rates which are 0 in limit are left as they are.
+func (m *CgroupManager) SetIoMax(limit io.DeviceLimit) error {
+	if m.ioMaxSubsystem == nil {
+		return constant.ErrUnsupportedAction.WrapMessage(constant.IoMax)
+	}
+	return m.ioMaxSubsystem.Set(io.MaxItem(limit))
+}
+
+// SetIoWeight sets the default io.weight of the container, from 1 to 10000.
+func (m *CgroupManager) SetIoWeight(weight uint64) error {
+	if m.ioWeightSubsystem == nil {
+		return constant.ErrUnsupportedAction.WrapMessage(constant.IoWeight)
+	}
+	return m.ioWeightSubsystem.Set(io.WeightItem(weight))
+}
+
 func (m *CgroupManager) SetCpuMax(quota, period int) error {
 	item := cpu.MaxItem{Quota: quota, Period: period}
 	return m.cpuMaxSubsystem.Set(item)
 }
 
+// SetCpuWeight sets cpu.weight of the container, from 1 to 10000.
+func (m *CgroupManager) SetCpuWeight(weight uint64) error {
+	if m.cpuWeightSubsystem == nil {
+		return constant.ErrUnsupportedAction.WrapMessage(constant.CpuWeight)
+	}
+	return m.cpuWeightSubsystem.Set(cpu.WeightItem(weight))
+}
+
+// SetCpuset pins the container to cpus and memory nodes given as lists like 0-3,6, an empty list
+// is left as it is.
+func (m *CgroupManager) SetCpuset(cpus, mems string) error {
+	for _, pair := range []struct {
+		name string
+		list string
+		ss   interface{ Set(cpuset.ListItem) error }
+	}{{constant.CpusetCpus, cpus, m.cpusetCpusSubsystem}, {constant.CpusetMems, mems, m.cpusetMemsSubsystem}} {
+		if pair.list == "" {
+			continue
+		}
+		if m.cpusetCpusSubsystem == nil || m.cpusetMemsSubsystem == nil {
+			return constant.ErrUnsupportedAction.WrapMessage(pair.name)
+		}
+		ids, err := cpuset.ParseList(pair.list)
+		if err != nil {
+			return err
+		}
+		if err = pair.ss.Set(cpuset.ListItem{Ids: ids}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Freeze stops all processes of the cgroup, it returns once the kernel reports them frozen.
+func (m *CgroupManager) Freeze() error {
+	return m.setFrozen(true)
+}
+
+// Thaw resumes the processes of a frozen cgroup.
+func (m *CgroupManager) Thaw() error {
+	return m.setFrozen(false)
+}
+
+func (m *CgroupManager) setFrozen(frozen bool) error {
+	if m.freezeSubsystem == nil {
+		return constant.ErrUnsupportedAction.WrapMessage(constant.CgroupFreeze)
+	}
+	if err := m.freezeSubsystem.Set(cgroup.FreezeItem(frozen)); err != nil {
+		return err
+	}
+	if err := Write(m.fs, m.freezeSubsystem); err != nil {
+		return err
+	}
+	return WaitEvents(m.fs, func(events *cgroup.EventsValue) bool {
+		return events.Frozen == frozen
+	}, freezeTimeout)
+}
+
 func (m *CgroupManager) DelProcsPid(pid cgroup.ProcsItem) error {
 	return m.procsSubsystem.Del(pid)
 }
 
 func (m *CgroupManager) Sync() error {
-	err := Write(m.fs, m.cpuMaxSubsystem)
+	err := m.SyncLimits()
 	if err != nil {
 		return err
 	}
 
-	err = Write(m.fs, m.memoryMaxSubsystem)
+	err = Write(m.fs, m.procsSubsystem)
 	if err != nil {
 		return err
 	}
 
-	err = Write(m.fs, m.procsSubsystem)
+	return nil
+}
+
+// SyncLimits writes the resource limits only, it updates the cgroup of a running container.
+func (m *CgroupManager) SyncLimits() error {
+	err := Write(m.fs, m.cpuMaxSubsystem)
+	if err != nil {
+		return err
+	}
+
+	if m.cpuWeightSubsystem != nil {
+		if err = Write(m.fs, m.cpuWeightSubsystem); err != nil {
+			return err
+		}
+	}
+
+	if m.cpusetMemsSubsystem != nil && !m.cpusetMemsSubsystem.Empty() {
+		if err = Write(m.fs, m.cpusetMemsSubsystem); err != nil {
+			return err
+		}
+	}
+
+	if m.cpusetCpusSubsystem != nil && !m.cpusetCpusSubsystem.Empty() {
+		if err = Write(m.fs, m.cpusetCpusSubsystem); err != nil {
+			return err
+		}
+	}
+
+	err = Write(m.fs, m.memoryMaxSubsystem)
 	if err != nil {
 		return err
 	}
 
+	for _, ss := range []*memory.LimitValueSubsystem{m.memoryHighSubsystem, m.memoryLowSubsystem, m.memoryMinSubsystem} {
+		if ss == nil {
+			continue
+		}
+		if err = Write(m.fs, ss); err != nil {
+			return err
+		}
+	}
+
+	if m.memorySwapMaxSubsystem != nil {
+		if err = Write(m.fs, m.memorySwapMaxSubsystem); err != nil {
+			return err
+		}
+	}
+
+	if m.memoryOomGroupSubsystem != nil {
+		if err = Write(m.fs, m.memoryOomGroupSubsystem); err != nil {
+			return err
+		}
+	}
+
+	if m.pidsMaxSubsystem != nil {
+		if err = Write(m.fs, m.pidsMaxSubsystem); err != nil {
+			return err
+		}
+	}
+
+	if m.ioMaxSubsystem != nil && !m.ioMaxSubsystem.Empty() {
+		if err = Write(m.fs, m.ioMaxSubsystem); err != nil {
+			return err
+		}
+	}
+
+	if m.ioWeightSubsystem != nil && !m.ioWeightSubsystem.Empty() {
+		return Write(m.fs, m.ioWeightSubsystem)
+	}
 	return nil
 }
 
@@ -90,11 +397,141 @@ func (m *CgroupManager) readCgroupProcs() (*cgroup.ProcsValue, error) {
 	return v, err
 }
 
-func newSubsystem[T subsystem.BaseSubsystem](fs *CgroupFileSystem, name string) (T, error) {
-	zeroSubsystem := subsystem.ZeroSubsystem{}
+// ReadMemoryEvents reads memory.events of a container, the cgroup outlives the container process
+// so it can be read after the container exited.
+func ReadMemoryEvents(containerCgroup string) (*memory.EventsValue, error) {
+	v := &memory.EventsValue{}
+	return v, readValue(containerCgroup, constant.MemoryEvents, v)
+}
+
+// ReadPidsCurrent reads pids.current of a container, the number of its processes and threads.
+func ReadPidsCurrent(containerCgroup string) (int64, error) {
+	return readPidsCurrent(NewCgroupFileSystem(containerCgroup, false))
+}
+
+func readPidsCurrent(fs CgroupFileSystem) (int64, error) {
+	v := &pids.CurrentValue{}
+	if err := readFileValue(fs, constant.PidsCurrent, v); err != nil {
+		return 0, err
+	}
+	return v.Count, nil
+}
+
+// Stats is a sample of the usage counters of a container.
+type Stats struct {
+	Cpu           cpu.StatValue
+	MemoryCurrent memory.CurrentValue
+	MemoryMax     memory.MaxValue
+	MemoryStat    memory.StatValue
+	// Io is empty if the io controller is not enabled
+	Io   io.StatValue
+	Pids pids.CurrentValue
+}
+
+// ReadStats reads the usage of a container from its cgroup.
+func ReadStats(containerCgroup string) (*Stats, error) {
+	stats := &Stats{}
+	for name, v := range map[string]subsystem.Value{
+		constant.CpuStat:       &stats.Cpu,
+		constant.MemoryCurrent: &stats.MemoryCurrent,
+		constant.MemoryMax:     &stats.MemoryMax,
+		constant.MemoryStat:    &stats.MemoryStat,
+		constant.PidsCurrent:   &stats.Pids,
+	} {
+		if err := readValue(containerCgroup, name, v); err != nil {
+			return nil, err
+		}
+	}
+	if err := readValue(containerCgroup, constant.IoStat, &stats.Io); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// ContainsProcess reports whether pid is one of the processes of the cgroup of a container, it
+// tells apart the process of a container from an unrelated one which reused its pid.
+func ContainsProcess(containerCgroup string, pid int) bool {
+	err, data := NewCgroupFileSystem(containerCgroup, false).Read(constant.CgroupProcs)
+	if err != nil {
+		return false
+	}
+	return slices.Contains(strings.Fields(data), strconv.Itoa(pid))
+}
+
+// readValue reads a file of the cgroup of a container into v.
+func readValue(containerCgroup string, name string, v subsystem.Value) error {
+	return readFileValue(NewCgroupFileSystem(containerCgroup, false), name, v)
+}
+
+func readFileValue(fs CgroupFileSystem, name string, v subsystem.Value) error {
 	err, data := fs.Read(name)
 	if err != nil {
-		return any(zeroSubsystem).(T), err
+		return err
+	}
+	return v.From(data)
+}
+
+// ValidateCpuset checks that the cpus and memory nodes of a container are ones its parent cgroup
+// may use, the kernel rejects others only when the container starts.
+func ValidateCpuset(parent string, cpus, mems string) error {
+	for _, pair := range []struct {
+		list      string
+		effective string
+	}{{cpus, constant.CpusetCpusEffective}, {mems, constant.CpusetMemsEffective}} {
+		if pair.list == "" {
+			continue
+		}
+		requested := cpuset.ListValue{}
+		if err := requested.From(pair.list); err != nil {
+			return constant.ErrMalformedArgs.Wrap(err)
+		}
+		available, err := readEffectiveCpuset(parent, pair.effective)
+		if err != nil {
+			return err
+		}
+		if !available.Contains(requested) {
+			return constant.ErrMalformedArgs.WrapMessage(
+				fmt.Sprintf("requested %s is not available, %s is %s", pair.list, pair.effective, available.Into()))
+		}
+	}
+	return nil
+}
+
+// readEffectiveCpuset reads an effective cpuset of the cgroup a container is created in, or of the
+// nearest ancestor that has it when the cgroup has not been created yet.
+func readEffectiveCpuset(parent string, name string) (*cpuset.ListValue, error) {
+	for path := filepath.Clean(parent); ; path = filepath.Dir(path) {
+		err, data := NewCgroupFileSystem(path, false).Read(name)
+		if err == nil {
+			v := &cpuset.ListValue{}
+			err = v.From(data)
+			return v, err
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		if path == "." {
+			return nil, constant.ErrUnsupportedAction.WrapMessage(name)
+		}
+	}
+}
+
+// newOptionalSubsystem loads a subsystem whose file does not exist with every kernel, the result
+// is nil if the file is missing.
+func newOptionalSubsystem[T subsystem.BaseSubsystem](fs CgroupFileSystem, name string) (T, error) {
+	if !fs.Exists(name) {
+		var none T
+		return none, nil
+	}
+	return newSubsystem[T](fs, name)
+}
+
+func newSubsystem[T subsystem.BaseSubsystem](fs CgroupFileSystem, name string) (T, error) {
+	// the zero value of T is a nil subsystem, there is no subsystem to return along with an error
+	var zero T
+	err, data := fs.Read(name)
+	if err != nil {
+		return zero, err
 	}
 
 	var ns T
@@ -108,17 +545,53 @@ func newSubsystem[T subsystem.BaseSubsystem](fs *CgroupFileSystem, name string)
 		v, e := memory.NewMaxValueSubsystem(data)
 		err = e
 		ns = any(v).(T)
+	case constant.MemoryHigh, constant.MemoryLow, constant.MemoryMin, constant.MemorySwapMax:
+		v, e := memory.NewLimitValueSubsystem(name, data)
+		err = e
+		ns = any(v).(T)
+	case constant.MemoryOomGroup:
+		v, e := memory.NewOomGroupValueSubsystem(data)
+		err = e
+		ns = any(v).(T)
 	case constant.CpuMax:
 		v, e := cpu.NewCpuMaxValueSubsystem(data)
 		err = e
 		ns = any(v).(T)
+	case constant.CpuWeight:
+		v, e := cpu.NewWeightValueSubsystem(data)
+		err = e
+		ns = any(v).(T)
+	case constant.CpusetCpus:
+		v, e := cpuset.NewCpusValueSubsystem(data)
+		err = e
+		ns = any(v).(T)
+	case constant.CpusetMems:
+		v, e := cpuset.NewMemsValueSubsystem(data)
+		err = e
+		ns = any(v).(T)
+	case constant.PidsMax:
+		v, e := pids.NewMaxValueSubsystem(data)
+		err = e
+		ns = any(v).(T)
+	case constant.IoMax:
+		v, e := io.NewMaxValueSubsystem(data)
+		err = e
+		ns = any(v).(T)
+	case constant.IoWeight:
+		v, e := io.NewWeightValueSubsystem(data)
+		err = e
+		ns = any(v).(T)
+	case constant.CgroupFreeze:
+		v, e := cgroup.NewFreezeValueSubsystem(data)
+		err = e
+		ns = any(v).(T)
 	default:
 		panic(fmt.Errorf("unknown subsystem {%s}", name))
 	}
 
 	if err != nil {
 		logrus.Errorf("[newSubsystem]: {%s}", err)
-		return any(zeroSubsystem).(T), err
+		return zero, err
 	}
 
 	return any(ns).(T), nil
@@ -0,0 +1,68 @@
+package manager
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0x822a5b87/tiny-docker/src/constant"
+	"github.com/0x822a5b87/tiny-docker/src/subsystem/cgroup"
+	"github.com/0x822a5b87/tiny-docker/src/subsystem/cpu"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestCgroup creates a cgroup of cgroup v2 in a temporary directory with the given files.
+func newTestCgroup(t *testing.T, files map[string]string) CgroupFileSystem {
+	dir := t.TempDir()
+	for name, data := range files {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(data), 0644))
+	}
+	return &unifiedFileSystem{path: dir}
+}
+
+func TestNewSubsystemMissingFile(t *testing.T) {
+	fs := newTestCgroup(t, nil)
+
+	// a missing file is an error, not a panic over the type of the subsystem
+	ss, err := newSubsystem[*cpu.MaxValueSubsystem](fs, constant.CpuMax)
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+	assert.Nil(t, ss)
+
+	freeze, err := newOptionalSubsystem[*cgroup.FreezeValueSubsystem](fs, constant.CgroupFreeze)
+	assert.NoError(t, err)
+	assert.Nil(t, freeze)
+}
+
+func TestFreezeUnsupported(t *testing.T) {
+	m := &CgroupManager{fs: newTestCgroup(t, nil)}
+	assert.True(t, errors.Is(m.Freeze(), constant.ErrUnsupportedAction))
+	assert.True(t, errors.Is(m.Thaw(), constant.ErrUnsupportedAction))
+}
+
+func TestPidsMaxUnsupported(t *testing.T) {
+	m := &CgroupManager{fs: newTestCgroup(t, nil)}
+	assert.True(t, errors.Is(m.SetPidsMax(100), constant.ErrUnsupportedAction))
+}
+
+func TestCpuWeightUnsupported(t *testing.T) {
+	m := &CgroupManager{fs: newTestCgroup(t, nil)}
+	assert.True(t, errors.Is(m.SetCpuWeight(100), constant.ErrUnsupportedAction))
+}
+
+func TestMemoryLowUnsupported(t *testing.T) {
+	m := &CgroupManager{fs: newTestCgroup(t, nil)}
+	assert.True(t, errors.Is(m.SetMemoryLow(1<<20), constant.ErrUnsupportedAction))
+}
+
+func TestReadPidsCurrent(t *testing.T) {
+	count, err := readPidsCurrent(newTestCgroup(t, map[string]string{constant.PidsCurrent: "7\n"}))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), count)
+
+	// the count is not used when the file could not be read or parsed
+	_, err = readPidsCurrent(newTestCgroup(t, nil))
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+	_, err = readPidsCurrent(newTestCgroup(t, map[string]string{constant.PidsCurrent: "many\n"}))
+	assert.Error(t, err)
+}
@@ -1,27 +1,23 @@
 package manager
 
 import (
-	"os"
-
 	"github.com/0x822a5b87/tiny-docker/src/subsystem"
-	"github.com/0x822a5b87/tiny-docker/src/util"
 	"github.com/sirupsen/logrus"
 )
 
-func Write[I subsystem.Item, V subsystem.Value](f *CgroupFileSystem, ss subsystem.Subsystem[I, V]) error {
-	cgroupPath, err := util.GetCgroupPath(ss.Name(), f.Path, f.AutoCreate)
+func Write[I subsystem.Item, V subsystem.Value](f CgroupFileSystem, ss subsystem.Subsystem[I, V]) error {
+	value, err := ss.Get()
 	if err != nil {
 		return err
 	}
 
-	value, err := ss.Get()
-	if err != nil {
-		return err
+	if lines, ok := any(value).(subsystem.LinesValue); ok {
+		return f.WriteLines(ss.Name(), lines.Lines())
 	}
 
-	data := []byte(value.Into())
-	if err = os.WriteFile(cgroupPath, data, 0644); err != nil {
-		logrus.Errorf("error write value : {%s}", string(data))
+	data := value.Into()
+	if err = f.Write(ss.Name(), data); err != nil {
+		logrus.Errorf("error write value : {%s}", data)
 		return err
 	}
 
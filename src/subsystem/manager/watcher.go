@@ -0,0 +1,185 @@
+package manager
+
+import (
+	"errors"
+	"sync"
+	"unsafe"
+
+	"github.com/0x822a5b87/tiny-docker/src/constant"
+	"github.com/0x822a5b87/tiny-docker/src/entity"
+	"github.com/0x822a5b87/tiny-docker/src/subsystem/cgroup"
+	"github.com/0x822a5b87/tiny-docker/src/subsystem/memory"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+)
+
+// EventWatcher watches memory.events and cgroup.events of the cgroups of containers with a single
+// inotify instance, the kernel generates a modify event on them whenever their content changes.
+// OomHandler is called whenever the OOM killer ran for a container. Once the last process of a
+// cgroup exited, memory.events is checked a last time and the cgroup is not watched any more.
+type EventWatcher struct {
+	fd         int
+	onOom      OomHandler
+	mu         sync.Mutex
+	files      map[int32]watchedFile
+	containers map[entity.ContainerId]*watchedCgroup
+}
+
+// OomHandler receives the memory events of a container whose oom or oom_kill counter increased.
+type OomHandler func(id entity.ContainerId, events memory.EventsValue)
+
+type watchedFile struct {
+	id   entity.ContainerId
+	name string
+}
+
+type watchedCgroup struct {
+	fs   CgroupFileSystem
+	wds  []int32
+	last memory.EventsValue
+}
+
+func NewEventWatcher(onOom OomHandler) (*EventWatcher, error) {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC)
+	if err != nil {
+		return nil, err
+	}
+	w := &EventWatcher{
+		fd:         fd,
+		onOom:      onOom,
+		files:      make(map[int32]watchedFile),
+		containers: make(map[entity.ContainerId]*watchedCgroup),
+	}
+	go w.loop()
+	return w, nil
+}
+
+// Watch starts watching the cgroup of a container. The counters are checked right away, so an OOM
+// kill of a container which ran while nobody watched it is reported as well. It fails with
+// ErrUnsupportedAction with cgroup v1, which has no files that can be watched.
+func (w *EventWatcher) Watch(id entity.ContainerId, containerCgroup string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if watched, ok := w.containers[id]; ok {
+		// the cgroup is created again with every start of the container
+		w.removeWatches(watched)
+	}
+	fs := NewCgroupFileSystem(containerCgroup, false)
+	watched := &watchedCgroup{fs: fs}
+	for _, name := range []string{constant.MemoryEvents, constant.CgroupEvents} {
+		p, err := fs.WatchPath(name)
+		if err != nil {
+			w.removeWatches(watched)
+			return err
+		}
+		wd, err := unix.InotifyAddWatch(w.fd, p, unix.IN_MODIFY)
+		if err != nil {
+			w.removeWatches(watched)
+			return err
+		}
+		w.files[int32(wd)] = watchedFile{id: id, name: name}
+		watched.wds = append(watched.wds, int32(wd))
+	}
+	w.containers[id] = watched
+	// the handler may take locks of the caller, it is called from another goroutine
+	go w.check(id, constant.MemoryEvents)
+	return nil
+}
+
+// Unwatch stops watching the cgroup of a container.
+func (w *EventWatcher) Unwatch(id entity.ContainerId) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if watched, ok := w.containers[id]; ok {
+		w.removeWatches(watched)
+		delete(w.containers, id)
+	}
+}
+
+// removeWatches must be called with mu held.
+func (w *EventWatcher) removeWatches(watched *watchedCgroup) {
+	for _, wd := range watched.wds {
+		_, _ = unix.InotifyRmWatch(w.fd, uint32(wd))
+		delete(w.files, wd)
+	}
+}
+
+func (w *EventWatcher) loop() {
+	buf := make([]byte, 4096)
+	for {
+		n, err := unix.Read(w.fd, buf)
+		if errors.Is(err, unix.EINTR) {
+			continue
+		}
+		if err != nil {
+			logrus.Errorf("error read cgroup events: %v", err)
+			return
+		}
+		for offset := 0; offset+unix.SizeofInotifyEvent <= n; {
+			event := (*unix.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+			offset += unix.SizeofInotifyEvent + int(event.Len)
+			w.handle(event)
+		}
+	}
+}
+
+func (w *EventWatcher) handle(event *unix.InotifyEvent) {
+	w.mu.Lock()
+	file, ok := w.files[event.Wd]
+	if ok && event.Mask&unix.IN_IGNORED != 0 {
+		// the cgroup was removed, which removed the watch as well
+		delete(w.files, event.Wd)
+	}
+	w.mu.Unlock()
+	if ok && event.Mask&unix.IN_MODIFY != 0 {
+		w.check(file.id, file.name)
+	}
+}
+
+// check reads a changed file of the cgroup of a container.
+func (w *EventWatcher) check(id entity.ContainerId, name string) {
+	w.mu.Lock()
+	watched, ok := w.containers[id]
+	w.mu.Unlock()
+	if !ok {
+		return
+	}
+	if name == constant.CgroupEvents {
+		w.checkPopulated(id, watched)
+		return
+	}
+	w.checkMemory(id, watched)
+}
+
+func (w *EventWatcher) checkPopulated(id entity.ContainerId, watched *watchedCgroup) {
+	err, data := watched.fs.Read(constant.CgroupEvents)
+	if err != nil {
+		return
+	}
+	events := &cgroup.EventsValue{}
+	if err = events.From(data); err != nil || events.Populated {
+		return
+	}
+	// the OOM kill of the last process may be counted after its exit is seen
+	w.checkMemory(id, watched)
+	w.Unwatch(id)
+}
+
+func (w *EventWatcher) checkMemory(id entity.ContainerId, watched *watchedCgroup) {
+	err, data := watched.fs.Read(constant.MemoryEvents)
+	if err != nil {
+		return
+	}
+	events := memory.EventsValue{}
+	if err = events.From(data); err != nil {
+		logrus.Warnf("error parse memory events of container {%s}: %v", id, err)
+		return
+	}
+	w.mu.Lock()
+	last := watched.last
+	watched.last = events
+	w.mu.Unlock()
+	if events.Oom > last.Oom || events.OomKill > last.OomKill {
+		w.onOom(id, events)
+	}
+}
@@ -0,0 +1,82 @@
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0x822a5b87/tiny-docker/src/constant"
+	"github.com/0x822a5b87/tiny-docker/src/entity"
+	"github.com/0x822a5b87/tiny-docker/src/subsystem/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestWatcher creates a watcher of a container without inotify, the files are checked by the
+// test instead of on their modify events.
+func newTestWatcher(t *testing.T, id entity.ContainerId, files map[string]string) (*EventWatcher, *[]memory.EventsValue) {
+	var ooms []memory.EventsValue
+	w := &EventWatcher{
+		onOom: func(_ entity.ContainerId, events memory.EventsValue) {
+			ooms = append(ooms, events)
+		},
+		files:      make(map[int32]watchedFile),
+		containers: map[entity.ContainerId]*watchedCgroup{id: {fs: newTestCgroup(t, files)}},
+	}
+	return w, &ooms
+}
+
+func writeEvents(t *testing.T, w *EventWatcher, id entity.ContainerId, name string, data string) {
+	dir := w.containers[id].fs.(*unifiedFileSystem).path
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(data), 0644))
+}
+
+func TestCheckMemory(t *testing.T) {
+	id := entity.ContainerId("test")
+	w, ooms := newTestWatcher(t, id, map[string]string{
+		constant.MemoryEvents: "low 0\nhigh 0\nmax 0\noom 0\noom_kill 0\n",
+	})
+
+	w.check(id, constant.MemoryEvents)
+	assert.Empty(t, *ooms)
+
+	// reaching the limit is not an OOM
+	writeEvents(t, w, id, constant.MemoryEvents, "low 0\nhigh 0\nmax 4\noom 0\noom_kill 0\n")
+	w.check(id, constant.MemoryEvents)
+	assert.Empty(t, *ooms)
+
+	writeEvents(t, w, id, constant.MemoryEvents, "low 0\nhigh 0\nmax 5\noom 1\noom_kill 0\n")
+	w.check(id, constant.MemoryEvents)
+	assert.Len(t, *ooms, 1)
+
+	writeEvents(t, w, id, constant.MemoryEvents, "low 0\nhigh 0\nmax 5\noom 1\noom_kill 1\n")
+	w.check(id, constant.MemoryEvents)
+	assert.Len(t, *ooms, 2)
+	assert.Equal(t, memory.EventsValue{Max: 5, Oom: 1, OomKill: 1}, (*ooms)[1])
+
+	// the counters did not change
+	w.check(id, constant.MemoryEvents)
+	assert.Len(t, *ooms, 2)
+
+	// the counters start at 0 again with the cgroup of the next start of the container
+	w.containers[id].last = memory.EventsValue{Oom: 3, OomKill: 3}
+	w.check(id, constant.MemoryEvents)
+	assert.Len(t, *ooms, 2)
+}
+
+func TestCheckMemoryOnExit(t *testing.T) {
+	id := entity.ContainerId("test")
+	w, ooms := newTestWatcher(t, id, map[string]string{
+		constant.MemoryEvents: "low 0\nhigh 0\nmax 0\noom 0\noom_kill 0\n",
+		constant.CgroupEvents: "populated 1\nfrozen 0\n",
+	})
+
+	w.check(id, constant.CgroupEvents)
+	assert.Contains(t, w.containers, id)
+
+	// the OOM kill of the last process is counted after it exited
+	writeEvents(t, w, id, constant.MemoryEvents, "low 0\nhigh 0\nmax 1\noom 1\noom_kill 1\n")
+	writeEvents(t, w, id, constant.CgroupEvents, "populated 0\nfrozen 0\n")
+	w.check(id, constant.CgroupEvents)
+	assert.Len(t, *ooms, 1)
+	assert.NotContains(t, w.containers, id)
+}
@@ -0,0 +1,51 @@
+package memory
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/0x822a5b87/tiny-docker/src/subsystem"
+)
+
+// EventsValue is the content of memory.events, it is read only so there is no subsystem for it.
+type EventsValue struct {
+	Low     int64
+	High    int64
+	Max     int64
+	Oom     int64
+	OomKill int64
+}
+
+func (e *EventsValue) From(s string) error {
+	for _, line := range strings.Split(strings.TrimSpace(s), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		count, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return err
+		}
+		switch fields[0] {
+		case "low":
+			e.Low = count
+		case "high":
+			e.High = count
+		case "max":
+			e.Max = count
+		case "oom":
+			e.Oom = count
+		case "oom_kill":
+			e.OomKill = count
+		}
+	}
+	return nil
+}
+
+func (e *EventsValue) Into() string {
+	return fmt.Sprintf("low %d\nhigh %d\nmax %d\noom %d\noom_kill %d\n", e.Low, e.High, e.Max, e.Oom, e.OomKill)
+}
+
+// add compiler check
+var _ subsystem.Value = (*EventsValue)(nil)
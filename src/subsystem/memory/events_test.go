@@ -0,0 +1,19 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventsValue(t *testing.T) {
+	v := &EventsValue{}
+	assert.NoError(t, v.From("low 0\nhigh 3\nmax 12\noom 2\noom_kill 1\noom_group_kill 0\n"))
+	assert.Equal(t, EventsValue{High: 3, Max: 12, Oom: 2, OomKill: 1}, *v)
+
+	parsed := &EventsValue{}
+	assert.NoError(t, parsed.From(v.Into()))
+	assert.Equal(t, v, parsed)
+
+	assert.Error(t, v.From("oom_kill x"))
+}
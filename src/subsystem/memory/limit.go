@@ -0,0 +1,165 @@
+package memory
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/0x822a5b87/tiny-docker/src/constant"
+	"github.com/0x822a5b87/tiny-docker/src/subsystem"
+)
+
+// Unlimited is the number of bytes of a limit which the kernel calls max.
+const Unlimited = math.MaxInt64
+
+type LimitItem LimitValue
+
+// LimitValue is the content of memory.high, memory.low, memory.min and memory.swap.max, a number
+// of bytes or max. Unlike MaxValue a limit of 0 is written as 0, which for memory.swap.max means
+// no swap at all.
+type LimitValue struct {
+	Bytes int64
+}
+
+func (l *LimitValue) From(s string) error {
+	s = strings.TrimSpace(s)
+	if s == constant.LiteralMax {
+		l.Bytes = Unlimited
+		return nil
+	}
+	bytes, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return err
+	}
+	if bytes < 0 {
+		return constant.ErrMalformedType
+	}
+	l.Bytes = bytes
+	return nil
+}
+
+func (l *LimitValue) Into() string {
+	if l.Bytes == Unlimited {
+		return constant.LiteralMax
+	}
+	return strconv.FormatInt(l.Bytes, 10)
+}
+
+// NewLimitValueSubsystem loads one of the files of LimitValue, the default of memory.high and
+// memory.swap.max is max while that of the protections memory.low and memory.min is 0.
+func NewLimitValueSubsystem(name string, data string) (*LimitValueSubsystem, error) {
+	v := &LimitValue{}
+	if err := v.From(data); err != nil {
+		return nil, err
+	}
+	def := int64(Unlimited)
+	if name == constant.MemoryLow || name == constant.MemoryMin {
+		def = 0
+	}
+	return &LimitValueSubsystem{
+		name:  name,
+		def:   def,
+		value: v,
+	}, nil
+}
+
+type LimitValueSubsystem struct {
+	name  string
+	def   int64
+	value *LimitValue
+}
+
+func (l *LimitValueSubsystem) Name() string {
+	return l.name
+}
+
+func (l *LimitValueSubsystem) Get() (*LimitValue, error) {
+	return l.value, nil
+}
+
+func (l *LimitValueSubsystem) Set(item LimitItem) error {
+	if item.Bytes < 0 {
+		return constant.ErrOutOfRange
+	}
+	l.value.Bytes = item.Bytes
+	return nil
+}
+
+func (l *LimitValueSubsystem) Del(item LimitItem) error {
+	l.value.Bytes = l.def
+	return nil
+}
+
+// Empty reports whether the limit is the default of the kernel.
+func (l *LimitValueSubsystem) Empty() bool {
+	return l.value == nil || l.value.Bytes == l.def
+}
+
+type OomGroupItem bool
+
+// OomGroupValue is the content of memory.oom.group, when it is set the OOM killer kills all
+// processes of the cgroup together instead of picking a single one.
+type OomGroupValue struct {
+	Group bool
+}
+
+func (o *OomGroupValue) From(s string) error {
+	switch strings.TrimSpace(s) {
+	case "0":
+		o.Group = false
+	case "1":
+		o.Group = true
+	default:
+		return constant.ErrMalformedType
+	}
+	return nil
+}
+
+func (o *OomGroupValue) Into() string {
+	if o.Group {
+		return "1"
+	}
+	return "0"
+}
+
+func NewOomGroupValueSubsystem(data string) (*OomGroupValueSubsystem, error) {
+	v := &OomGroupValue{}
+	if err := v.From(data); err != nil {
+		return nil, err
+	}
+	return &OomGroupValueSubsystem{
+		value: v,
+	}, nil
+}
+
+type OomGroupValueSubsystem struct {
+	value *OomGroupValue
+}
+
+func (o *OomGroupValueSubsystem) Name() string {
+	return constant.MemoryOomGroup
+}
+
+func (o *OomGroupValueSubsystem) Get() (*OomGroupValue, error) {
+	return o.value, nil
+}
+
+func (o *OomGroupValueSubsystem) Set(item OomGroupItem) error {
+	o.value.Group = bool(item)
+	return nil
+}
+
+func (o *OomGroupValueSubsystem) Del(item OomGroupItem) error {
+	o.value.Group = false
+	return nil
+}
+
+func (o *OomGroupValueSubsystem) Empty() bool {
+	return o.value == nil || !o.value.Group
+}
+
+// add compiler check
+var _ subsystem.Value = (*LimitValue)(nil)
+var _ subsystem.Subsystem[LimitItem, *LimitValue] = (*LimitValueSubsystem)(nil)
+var _ subsystem.Value = (*OomGroupValue)(nil)
+var _ subsystem.Subsystem[OomGroupItem, *OomGroupValue] = (*OomGroupValueSubsystem)(nil)
@@ -0,0 +1,79 @@
+package memory
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/0x822a5b87/tiny-docker/src/subsystem"
+)
+
+// CurrentValue is the content of memory.current, the memory the cgroup uses including the page
+// cache. It is read only so there is no subsystem for it.
+type CurrentValue struct {
+	Bytes uint64
+}
+
+func (c *CurrentValue) From(s string) error {
+	bytes, err := strconv.ParseUint(strings.TrimSpace(s), 10, 64)
+	if err != nil {
+		return err
+	}
+	c.Bytes = bytes
+	return nil
+}
+
+func (c *CurrentValue) Into() string {
+	return strconv.FormatUint(c.Bytes, 10)
+}
+
+// StatValue is the content of memory.stat, a breakdown of memory.current. Only the entries that
+// are looked at are kept.
+type StatValue struct {
+	Anon         uint64
+	File         uint64
+	KernelStack  uint64
+	Sock         uint64
+	Shmem        uint64
+	ActiveFile   uint64
+	InactiveFile uint64
+}
+
+func (s *StatValue) From(data string) error {
+	for _, line := range strings.Split(strings.TrimSpace(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		value, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return err
+		}
+		switch fields[0] {
+		case "anon":
+			s.Anon = value
+		case "file":
+			s.File = value
+		case "kernel_stack":
+			s.KernelStack = value
+		case "sock":
+			s.Sock = value
+		case "shmem":
+			s.Shmem = value
+		case "active_file":
+			s.ActiveFile = value
+		case "inactive_file":
+			s.InactiveFile = value
+		}
+	}
+	return nil
+}
+
+func (s *StatValue) Into() string {
+	return fmt.Sprintf("anon %d\nfile %d\nkernel_stack %d\nsock %d\nshmem %d\nactive_file %d\ninactive_file %d",
+		s.Anon, s.File, s.KernelStack, s.Sock, s.Shmem, s.ActiveFile, s.InactiveFile)
+}
+
+// add compiler check
+var _ subsystem.Value = (*CurrentValue)(nil)
+var _ subsystem.Value = (*StatValue)(nil)
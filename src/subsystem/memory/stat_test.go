@@ -0,0 +1,37 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCurrentValue(t *testing.T) {
+	v := &CurrentValue{}
+	assert.NoError(t, v.From("1048576\n"))
+	assert.Equal(t, uint64(1048576), v.Bytes)
+	assert.Equal(t, "1048576", v.Into())
+	assert.Error(t, v.From("max"))
+}
+
+func TestStatValue(t *testing.T) {
+	data := "anon 8192\nfile 4096\nkernel 512\nkernel_stack 16384\npagetables 0\nsock 128\nshmem 1024\n" +
+		"file_mapped 0\nactive_anon 0\ninactive_anon 8192\nactive_file 1024\ninactive_file 3072\n"
+	v := &StatValue{}
+	assert.NoError(t, v.From(data))
+	assert.Equal(t, StatValue{
+		Anon:         8192,
+		File:         4096,
+		KernelStack:  16384,
+		Sock:         128,
+		Shmem:        1024,
+		ActiveFile:   1024,
+		InactiveFile: 3072,
+	}, *v)
+
+	parsed := &StatValue{}
+	assert.NoError(t, parsed.From(v.Into()))
+	assert.Equal(t, v, parsed)
+
+	assert.Error(t, v.From("anon -1"))
+}
@@ -0,0 +1,30 @@
+package pids
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/0x822a5b87/tiny-docker/src/subsystem"
+)
+
+// CurrentValue is the content of pids.current, the number of processes and threads in the cgroup.
+// It is read only so there is no subsystem for it.
+type CurrentValue struct {
+	Count int64
+}
+
+func (c *CurrentValue) From(s string) error {
+	count, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	if err != nil {
+		return err
+	}
+	c.Count = count
+	return nil
+}
+
+func (c *CurrentValue) Into() string {
+	return strconv.FormatInt(c.Count, 10)
+}
+
+// add compiler check
+var _ subsystem.Value = (*CurrentValue)(nil)
@@ -0,0 +1,18 @@
+package pids
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCurrentValue(t *testing.T) {
+	v := &CurrentValue{}
+	assert.NoError(t, v.From("12\n"))
+	assert.Equal(t, int64(12), v.Count)
+	assert.Equal(t, "12", v.Into())
+
+	for _, data := range []string{"", "max", "twelve"} {
+		assert.Error(t, v.From(data), data)
+	}
+}
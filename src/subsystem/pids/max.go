@@ -0,0 +1,81 @@
+package pids
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/0x822a5b87/tiny-docker/src/constant"
+	"github.com/0x822a5b87/tiny-docker/src/subsystem"
+)
+
+type MaxItem MaxValue
+
+// MaxValue is the content of pids.max, the number of processes and threads the cgroup may hold.
+// A Limit of 0 means no limit, which the kernel calls max.
+type MaxValue struct {
+	Limit int64
+}
+
+func (m *MaxValue) From(s string) error {
+	s = strings.TrimSpace(s)
+	if s == "" || s == constant.LiteralMax {
+		m.Limit = 0
+		return nil
+	}
+	limit, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return err
+	}
+	if limit < 0 {
+		return constant.ErrMalformedType
+	}
+	m.Limit = limit
+	return nil
+}
+
+func (m *MaxValue) Into() string {
+	if m.Limit <= 0 {
+		return constant.LiteralMax
+	}
+	return strconv.FormatInt(m.Limit, 10)
+}
+
+func NewMaxValueSubsystem(data string) (*MaxValueSubsystem, error) {
+	v := &MaxValue{}
+	if err := v.From(data); err != nil {
+		return nil, err
+	}
+	return &MaxValueSubsystem{
+		value: v,
+	}, nil
+}
+
+type MaxValueSubsystem struct {
+	value *MaxValue
+}
+
+func (m *MaxValueSubsystem) Name() string {
+	return constant.PidsMax
+}
+
+func (m *MaxValueSubsystem) Get() (*MaxValue, error) {
+	return m.value, nil
+}
+
+func (m *MaxValueSubsystem) Set(max MaxItem) error {
+	m.value.Limit = max.Limit
+	return nil
+}
+
+func (m *MaxValueSubsystem) Del(max MaxItem) error {
+	m.value.Limit = 0
+	return nil
+}
+
+func (m *MaxValueSubsystem) Empty() bool {
+	return m.value == nil || m.value.Limit == 0
+}
+
+// add compiler check
+var _ subsystem.Value = (*MaxValue)(nil)
+var _ subsystem.Subsystem[MaxItem, *MaxValue] = (*MaxValueSubsystem)(nil)
@@ -0,0 +1,49 @@
+package pids
+
+import (
+	"testing"
+
+	"github.com/0x822a5b87/tiny-docker/src/constant"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaxValue(t *testing.T) {
+	v := &MaxValue{}
+	assert.NoError(t, v.From("max\n"))
+	assert.Equal(t, int64(0), v.Limit)
+	assert.Equal(t, constant.LiteralMax, v.Into())
+
+	// no limit is written as max
+	assert.Equal(t, constant.LiteralMax, (&MaxValue{Limit: -1}).Into())
+
+	assert.NoError(t, v.From("100\n"))
+	assert.Equal(t, int64(100), v.Limit)
+	assert.Equal(t, "100", v.Into())
+
+	parsed := &MaxValue{}
+	assert.NoError(t, parsed.From(v.Into()))
+	assert.Equal(t, v, parsed)
+
+	for _, data := range []string{"-1", "lots", "1.5", "max max"} {
+		assert.Error(t, v.From(data), data)
+	}
+}
+
+func TestMaxValueSubsystem(t *testing.T) {
+	ss, err := NewMaxValueSubsystem("max")
+	assert.NoError(t, err)
+	assert.True(t, ss.Empty())
+
+	assert.NoError(t, ss.Set(MaxItem{Limit: 64}))
+	assert.False(t, ss.Empty())
+	v, err := ss.Get()
+	assert.NoError(t, err)
+	assert.Equal(t, "64", v.Into())
+
+	assert.NoError(t, ss.Del(MaxItem{}))
+	assert.True(t, ss.Empty())
+	assert.Equal(t, constant.LiteralMax, v.Into())
+
+	_, err = NewMaxValueSubsystem("-5")
+	assert.Error(t, err)
+}
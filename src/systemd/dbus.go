@@ -0,0 +1,47 @@
+package systemd
+
+import (
+	"errors"
+	"os"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const defaultSystemBusAddress = "unix:path=/run/dbus/system_bus_socket"
+
+// Conn is a private connection to a D-Bus message bus to call the methods of systemd.
+type Conn struct {
+	conn *dbus.Conn
+}
+
+// SystemBusAddress returns the address of the system bus, which DBUS_SYSTEM_BUS_ADDRESS overrides.
+func SystemBusAddress() string {
+	if address := os.Getenv("DBUS_SYSTEM_BUS_ADDRESS"); address != "" {
+		return address
+	}
+	return defaultSystemBusAddress
+}
+
+// Dial connects to the bus at address, like unix:path=/run/dbus/system_bus_socket, authenticates
+// with the uid of the process and registers the connection with the bus.
+func Dial(address string) (*Conn, error) {
+	conn, err := dbus.Connect(address)
+	if err != nil {
+		return nil, err
+	}
+	return &Conn{conn: conn}, nil
+}
+
+func (c *Conn) Close() error {
+	return c.conn.Close()
+}
+
+// IsError reports whether err is the error reply name, like org.freedesktop.systemd1.NoSuchUnit.
+func IsError(err error, name string) bool {
+	var e dbus.Error
+	if errors.As(err, &e) {
+		return e.Name == name
+	}
+	var pe *dbus.Error
+	return errors.As(err, &pe) && pe.Name == name
+}
@@ -0,0 +1,171 @@
+package systemd
+
+import (
+	"bufio"
+	"encoding/binary"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+const standInGuid = "0123456789abcdef0123456789abcdef"
+
+// standInBus is a bus with systemd on it which records the calls it is sent.
+type standInBus struct {
+	listener net.Listener
+	calls    chan *dbus.Message
+}
+
+func newStandInBus(t *testing.T) *standInBus {
+	listener, err := net.Listen("unix", filepath.Join(t.TempDir(), "bus"))
+	assert.Nil(t, err)
+	bus := &standInBus{listener: listener, calls: make(chan *dbus.Message, 16)}
+	go bus.serve()
+	t.Cleanup(func() { _ = listener.Close() })
+	return bus
+}
+
+func (b *standInBus) address() string {
+	return "unix:path=" + b.listener.Addr().String() + ",guid=" + standInGuid
+}
+
+// auth accepts the EXTERNAL authentication without passing file descriptors.
+func (b *standInBus) auth(conn net.Conn, reader *bufio.Reader) bool {
+	if nul, err := reader.ReadByte(); err != nil || nul != 0 {
+		return false
+	}
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return false
+		}
+		var reply string
+		switch line = strings.TrimSpace(line); {
+		case line == "AUTH":
+			reply = "REJECTED EXTERNAL"
+		case line == "AUTH EXTERNAL":
+			// the bus asks for the identity, the client leaves it to the credentials of the socket
+			reply = "DATA"
+		case line == "DATA" || strings.HasPrefix(line, "AUTH EXTERNAL "):
+			reply = "OK " + standInGuid
+		case line == "NEGOTIATE_UNIX_FD":
+			reply = "ERROR"
+		case line == "BEGIN":
+			return true
+		default:
+			return false
+		}
+		if _, err = conn.Write([]byte(reply + "\r\n")); err != nil {
+			return false
+		}
+	}
+}
+
+func (b *standInBus) serve() {
+	conn, err := b.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer func() { _ = conn.Close() }()
+	reader := bufio.NewReader(conn)
+	if !b.auth(conn, reader) {
+		return
+	}
+	for {
+		call, err := dbus.DecodeMessage(reader)
+		if err != nil {
+			return
+		}
+		b.calls <- call
+		reply := &dbus.Message{
+			Type:    dbus.TypeMethodReply,
+			Headers: map[dbus.HeaderField]dbus.Variant{dbus.FieldReplySerial: dbus.MakeVariant(call.Serial())},
+		}
+		var member string
+		_ = call.Headers[dbus.FieldMember].Store(&member)
+		switch {
+		case member == "Hello":
+			reply.Body = []any{":1.1"}
+		case member == "StopUnit" && call.Body[0] == "gone.scope":
+			reply.Type = dbus.TypeError
+			reply.Headers[dbus.FieldErrorName] = dbus.MakeVariant(ErrNoSuchUnit)
+			reply.Body = []any{"Unit gone.scope not loaded."}
+		case member == "StopUnit" && call.Body[0] == "fail.scope":
+			reply.Type = dbus.TypeError
+			reply.Headers[dbus.FieldErrorName] = dbus.MakeVariant("org.freedesktop.DBus.Error.AccessDenied")
+			reply.Body = []any{"Access denied"}
+		default:
+			reply.Body = []any{dbus.ObjectPath("/org/freedesktop/systemd1/job/1")}
+		}
+		reply.Headers[dbus.FieldSignature] = dbus.MakeVariant(dbus.SignatureOf(reply.Body...))
+		// a signal before the reply must not be taken for it
+		signal := &dbus.Message{
+			Type: dbus.TypeSignal,
+			Headers: map[dbus.HeaderField]dbus.Variant{
+				dbus.FieldPath:      dbus.MakeVariant(systemdPath),
+				dbus.FieldInterface: dbus.MakeVariant(systemdManager),
+				dbus.FieldMember:    dbus.MakeVariant("JobNew"),
+			},
+		}
+		for _, m := range []*dbus.Message{signal, reply} {
+			if err = m.EncodeTo(conn, binary.LittleEndian); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func header[T any](m *dbus.Message, field dbus.HeaderField) T {
+	var v T
+	_ = m.Headers[field].Store(&v)
+	return v
+}
+
+func TestStartTransientScope(t *testing.T) {
+	bus := newStandInBus(t)
+	conn, err := Dial(bus.address())
+	assert.Nil(t, err)
+	defer func() { _ = conn.Close() }()
+	assert.Equal(t, "Hello", header[string](<-bus.calls, dbus.FieldMember))
+
+	err = conn.StartTransientScope("tiny-docker-1.scope", "tiny.slice", "tiny-docker container 1", 42)
+	assert.Nil(t, err)
+	call := <-bus.calls
+	assert.Equal(t, "StartTransientUnit", header[string](call, dbus.FieldMember))
+	assert.Equal(t, systemdManager, header[string](call, dbus.FieldInterface))
+	assert.Equal(t, systemdPath, header[dbus.ObjectPath](call, dbus.FieldPath))
+	assert.Equal(t, systemdDestination, header[string](call, dbus.FieldDestination))
+	assert.Equal(t, "ssa(sv)a(sa(sv))", header[dbus.Signature](call, dbus.FieldSignature).String())
+	assert.Equal(t, "tiny-docker-1.scope", call.Body[0])
+	assert.Equal(t, "fail", call.Body[1])
+	properties := call.Body[2].([][]any)
+	assert.Equal(t, []any{"Slice", dbus.MakeVariant("tiny.slice")}, properties[1])
+	assert.Equal(t, []any{"PIDs", dbus.MakeVariant([]uint32{42})}, properties[2])
+	assert.Equal(t, []any{"Delegate", dbus.MakeVariant(true)}, properties[3])
+	assert.Empty(t, call.Body[3])
+}
+
+func TestStopUnit(t *testing.T) {
+	bus := newStandInBus(t)
+	conn, err := Dial(bus.address())
+	assert.Nil(t, err)
+	defer func() { _ = conn.Close() }()
+	<-bus.calls
+
+	assert.Nil(t, conn.StopUnit("tiny-docker-1.scope"))
+	call := <-bus.calls
+	assert.Equal(t, []any{"tiny-docker-1.scope", "replace"}, call.Body)
+
+	// a unit which does not exist is already stopped
+	assert.Nil(t, conn.StopUnit("gone.scope"))
+	<-bus.calls
+
+	err = conn.StopUnit("fail.scope")
+	assert.True(t, IsError(err, "org.freedesktop.DBus.Error.AccessDenied"))
+	assert.False(t, IsError(err, ErrNoSuchUnit))
+	assert.Equal(t, "Access denied", err.Error())
+}
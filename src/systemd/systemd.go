@@ -0,0 +1,59 @@
+package systemd
+
+import "github.com/godbus/dbus/v5"
+
+const (
+	systemdDestination                 = "org.freedesktop.systemd1"
+	systemdPath        dbus.ObjectPath = "/org/freedesktop/systemd1"
+	systemdManager                     = "org.freedesktop.systemd1.Manager"
+)
+
+const (
+	ErrNoSuchUnit = "org.freedesktop.systemd1.NoSuchUnit"
+	ErrUnitExists = "org.freedesktop.systemd1.UnitExists"
+)
+
+// StartTransientScope asks systemd to create the scope unit in slice and to move pid into it. The
+// scope is delegated, so that the cgroup files in it may be written, and accounts for the
+// controllers a container is limited by. It returns as soon as the job is queued.
+func (c *Conn) StartTransientScope(unit string, slice string, description string, pid int) error {
+	properties := []property{
+		{"Description", dbus.MakeVariant(description)},
+		{"Slice", dbus.MakeVariant(slice)},
+		{"PIDs", dbus.MakeVariant([]uint32{uint32(pid)})},
+		{"Delegate", dbus.MakeVariant(true)},
+		{"CPUAccounting", dbus.MakeVariant(true)},
+		{"MemoryAccounting", dbus.MakeVariant(true)},
+		{"IOAccounting", dbus.MakeVariant(true)},
+		{"TasksAccounting", dbus.MakeVariant(true)},
+	}
+	return c.call("StartTransientUnit", unit, "fail", properties, []auxUnit{})
+}
+
+// StopUnit asks systemd to stop a unit, which kills the processes left in a scope. A unit which
+// does not exist is not an error.
+func (c *Conn) StopUnit(unit string) error {
+	err := c.call("StopUnit", unit, "replace")
+	if IsError(err, ErrNoSuchUnit) {
+		return nil
+	}
+	return err
+}
+
+// call invokes a method of the manager of systemd, which replies with the path of the job.
+func (c *Conn) call(method string, args ...any) error {
+	var job dbus.ObjectPath
+	return c.conn.Object(systemdDestination, systemdPath).Call(systemdManager+"."+method, 0, args...).Store(&job)
+}
+
+// property is a property of a unit, the a(sv) of StartTransientUnit.
+type property struct {
+	Name  string
+	Value dbus.Variant
+}
+
+// auxUnit is a unit started along with a transient unit, the a(sa(sv)) of StartTransientUnit.
+type auxUnit struct {
+	Name       string
+	Properties []property
+}
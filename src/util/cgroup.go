@@ -5,67 +5,167 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
 
 	"github.com/0x822a5b87/tiny-docker/src/constant"
-	"github.com/0x822a5b87/tiny-docker/src/entity"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
 )
 
-func GenContainerCgroupPath(id entity.ContainerId) string {
-	cgroupBasePath := constant.CgroupBasePath
-	cgroupServicePath := filepath.Join(cgroupBasePath, constant.CgroupServiceName)
-	if err := EnsureFilePathExist(cgroupServicePath); err != nil {
-		panic(err)
+// cgroupControllers are the controllers of cgroup v2 mini-dockerd enables for the containers.
+var cgroupControllers = []string{"cpu", "cpuset", "memory", "io", "pids"}
+
+// PrepareCgroupParent creates the cgroup the cgroups of the containers are created in, parent is
+// relative to the root of the unified hierarchy. A controller is only available in a cgroup if it
+// is enabled in every ancestor, so the controllers of the containers are enabled from the root down.
+// The controllers the host does not offer are left out, unless they are required.
+func PrepareCgroupParent(parent string, required []string) error {
+	parentPath := filepath.Join(constant.CgroupRootPath, parent)
+	if err := EnsureFilePathExist(parentPath); err != nil {
+		return err
 	}
-	controllers := []string{"cpu", "memory", "io"}
-	err := EnableCgroupControllers(controllers, cgroupServicePath)
-	if err != nil {
-		panic(err)
+	path := constant.CgroupRootPath
+	if err := EnableCgroupControllers(cgroupControllers, required, path); err != nil {
+		return err
+	}
+	for _, name := range strings.Split(filepath.Clean(parent), "/") {
+		if name == "." {
+			continue
+		}
+		path = filepath.Join(path, name)
+		if err := EnableCgroupControllers(cgroupControllers, required, path); err != nil {
+			return err
+		}
 	}
-	return fmt.Sprintf("%s/%s", cgroupServicePath, id)
+	return nil
+}
+
+type CgroupMode int
+
+const (
+	// CgroupUnified is a host which mounts cgroup v2 alone at /sys/fs/cgroup.
+	CgroupUnified CgroupMode = iota
+	// CgroupHybrid mounts the controllers with cgroup v1, and cgroup v2 without controllers at
+	// /sys/fs/cgroup/unified, which is what systemd does by default before v243.
+	CgroupHybrid
+	// CgroupLegacy mounts cgroup v1 alone.
+	CgroupLegacy
+)
+
+func (m CgroupMode) String() string {
+	switch m {
+	case CgroupUnified:
+		return "v2"
+	case CgroupHybrid:
+		return "v1 (hybrid)"
+	default:
+		return "v1"
+	}
+}
+
+var (
+	cgroupModeOnce sync.Once
+	cgroupMode     CgroupMode
+	cgroupV1Once   sync.Once
+	cgroupV1Mounts map[string]string
+)
+
+// DetectCgroupMode tells from the file system mounted at /sys/fs/cgroup how the host mounts cgroups,
+// the mode does not change while the host runs, so it is detected once.
+func DetectCgroupMode() CgroupMode {
+	cgroupModeOnce.Do(func() {
+		cgroupMode = CgroupLegacy
+		var st unix.Statfs_t
+		if err := unix.Statfs(constant.CgroupRootPath, &st); err == nil && st.Type == unix.CGROUP2_SUPER_MAGIC {
+			cgroupMode = CgroupUnified
+			return
+		}
+		err := unix.Statfs(filepath.Join(constant.CgroupRootPath, "unified"), &st)
+		if err == nil && st.Type == unix.CGROUP2_SUPER_MAGIC {
+			cgroupMode = CgroupHybrid
+		}
+	})
+	return cgroupMode
+}
+
+// CgroupV1Mounts returns the mount point of every controller of cgroup v1 by its name, several
+// controllers like cpu and cpuacct share a mount point if they are mounted together.
+func CgroupV1Mounts() map[string]string {
+	cgroupV1Once.Do(func() {
+		cgroupV1Mounts = make(map[string]string)
+		data, err := os.ReadFile("/proc/self/mountinfo")
+		if err != nil {
+			return
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			// the optional fields end with a single -, which is followed by the fs type, the
+			// source and the super block options, which name the controllers
+			fields, super, ok := strings.Cut(line, " - ")
+			if !ok {
+				continue
+			}
+			mount, superFields := strings.Fields(fields), strings.Fields(super)
+			if len(mount) < 5 || len(superFields) < 3 || superFields[0] != "cgroup" {
+				continue
+			}
+			for _, controller := range strings.Split(superFields[2], ",") {
+				if controller == "rw" || controller == "ro" {
+					continue
+				}
+				if _, ok := cgroupV1Mounts[controller]; !ok {
+					cgroupV1Mounts[controller] = mount[4]
+				}
+			}
+		}
+	})
+	return cgroupV1Mounts
 }
 
-// EnableCgroupControllers edit cgroup.subtree_control so that we can use cpu.max to manage CPU resources.
-func EnableCgroupControllers(controllers []string, cgroupBasePath string) error {
+// EnableCgroupControllers enables controllers for the children of the cgroup at cgroupBasePath
+// in its cgroup.subtree_control. The kernel rejects a write as a whole if one of its controllers is
+// not available, so the controllers which cgroup.controllers lists are enabled a write at a time,
+// and only failing to enable a required one is an error.
+func EnableCgroupControllers(controllers []string, required []string, cgroupBasePath string) error {
+	available, err := os.ReadFile(filepath.Join(cgroupBasePath, constant.CgroupControllers))
+	if err != nil {
+		return fmt.Errorf("read controllers failed: %w", err)
+	}
 	subtreeFile := filepath.Join(cgroupBasePath, constant.CgroupSubtreeControl)
-	current, err := os.ReadFile(subtreeFile)
+	enabled, err := os.ReadFile(subtreeFile)
 	if err != nil {
 		return fmt.Errorf("read subtree control failed: %w", err)
 	}
-	currentStr := strings.TrimSpace(string(current))
-	currentControllers := strings.Fields(currentStr)
 
-	var enableCmd []string
 	for _, c := range controllers {
-		exists := false
-		for _, item := range currentControllers {
-			if strings.TrimPrefix(item, "+") == c && strings.TrimPrefix(item, "-") == c {
-				exists = true
-				break
+		if slices.Contains(strings.Fields(string(enabled)), c) {
+			continue
+		}
+		if !slices.Contains(strings.Fields(string(available)), c) {
+			if slices.Contains(required, c) {
+				return constant.ErrUnsupportedAction.WrapMessage(fmt.Sprintf("controller %s is not available in %s", c, cgroupBasePath))
 			}
+			continue
 		}
-		if !exists {
-			enableCmd = append(enableCmd, "+"+c)
+		if err = writeSubtreeControl(subtreeFile, "+"+c); err != nil {
+			if slices.Contains(required, c) {
+				return fmt.Errorf("enable controller %s in %s failed: %w", c, cgroupBasePath, err)
+			}
+			logrus.Warnf("error enable controller %s in %s: %v", c, cgroupBasePath, err)
 		}
 	}
+	return nil
+}
 
-	if len(enableCmd) == 0 {
-		return nil
-	}
-
-	cmdStr := strings.Join(enableCmd, " ") + "\n"
+func writeSubtreeControl(subtreeFile string, cmd string) error {
 	f, err := os.OpenFile(subtreeFile, os.O_WRONLY|os.O_APPEND, 0644)
 	if err != nil {
-		return fmt.Errorf("open subtree control file failed: %w", err)
+		return err
 	}
 	defer func() { _ = f.Close() }()
-
-	_, err = f.WriteString(cmdStr)
-	if err != nil {
-		return fmt.Errorf("write subtree control failed: %w", err)
-	}
-
-	return nil
+	_, err = f.WriteString(cmd + "\n")
+	return err
 }
 
 func GetCgroupPath(name string, cgroupPath string, autoCreate bool) (string, error) {
@@ -82,3 +182,16 @@ func GetCgroupPath(name string, cgroupPath string, autoCreate bool) (string, err
 	}
 	return "", err
 }
+
+// BlockDeviceNumber returns the major and minor number of a block device, which is how cgroup
+// files like io.max refer to it.
+func BlockDeviceNumber(path string) (major, minor uint32, err error) {
+	var stat unix.Stat_t
+	if err = unix.Stat(path, &stat); err != nil {
+		return 0, 0, err
+	}
+	if stat.Mode&unix.S_IFMT != unix.S_IFBLK {
+		return 0, 0, fmt.Errorf("%s is not a block device", path)
+	}
+	return unix.Major(stat.Rdev), unix.Minor(stat.Rdev), nil
+}
@@ -0,0 +1,29 @@
+package util
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/0x822a5b87/tiny-docker/src/constant"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnableCgroupControllers(t *testing.T) {
+	dir := t.TempDir()
+	subtree := filepath.Join(dir, constant.CgroupSubtreeControl)
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, constant.CgroupControllers), []byte("cpu io memory pids\n"), 0644))
+	assert.NoError(t, os.WriteFile(subtree, []byte("memory\n"), 0644))
+
+	// cpuset is not offered and not required, memory is enabled already
+	err := EnableCgroupControllers([]string{"cpu", "cpuset", "memory", "pids"}, []string{"cpu", "memory"}, dir)
+	assert.NoError(t, err)
+	data, err := os.ReadFile(subtree)
+	assert.NoError(t, err)
+	// a regular file keeps the writes, the kernel would list the enabled controllers
+	assert.Equal(t, "memory\n+cpu\n+pids\n", string(data))
+
+	err = EnableCgroupControllers([]string{"cpu", "cpuset"}, []string{"cpuset"}, dir)
+	assert.True(t, errors.Is(err, constant.ErrUnsupportedAction))
+}
@@ -94,3 +94,14 @@ func UnTar(srcFile string, dstPath string) error {
 	}
 	return nil
 }
+
+// CopyDir copies the content of srcPath into dstPath, keeping the owners, modes and links.
+func CopyDir(srcPath string, dstPath string) error {
+	data, err := exec.Command("cp", "-a", filepath.Clean(srcPath)+"/.", dstPath).CombinedOutput()
+	if err != nil {
+		logrus.Errorf("copy error: src = {%s}, dst = {%s}, error = {%s}", srcPath, dstPath, err.Error())
+		logrus.Errorf("copy error info: {%s}", string(data))
+		return err
+	}
+	return nil
+}
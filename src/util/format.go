@@ -0,0 +1,83 @@
+package util
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/0x822a5b87/tiny-docker/src/entity"
+)
+
+func FormatTimeAgo(t time.Time) string {
+	duration := time.Since(t)
+
+	switch {
+	case duration < time.Hour:
+		minutes := int(duration.Minutes())
+		if minutes == 0 {
+			return "a few seconds ago"
+		}
+		return fmt.Sprintf("%d %s ago", minutes, Pluralize(minutes, "minute", "minutes"))
+	case duration < 24*time.Hour:
+		hours := int(duration.Hours())
+		return fmt.Sprintf("%d %s ago", hours, Pluralize(hours, "hour", "hours"))
+	case duration < 30*24*time.Hour:
+		days := int(duration.Hours() / 24)
+		return fmt.Sprintf("%d %s ago", days, Pluralize(days, "day", "days"))
+	default:
+		return t.Format("2006-01-02 15:04")
+	}
+}
+
+func FormatContainerStatus(c entity.Container) string {
+	if c.Status.Alive() {
+		startedAt := c.StartedAt
+		if startedAt == 0 {
+			startedAt = c.CreatedAt
+		}
+		status := "Up " + FormatTimeAgo(time.UnixMilli(startedAt))
+		if c.Status == entity.ContainerPaused {
+			status += " (Paused)"
+		}
+		return status
+	}
+	if c.Status == entity.ContainerCreated {
+		return "Created"
+	}
+
+	exitAt := time.UnixMilli(c.ExitAt)
+	exitAgo := FormatTimeAgo(exitAt)
+	status := fmt.Sprintf("Exited (%d) %s", c.ExitCode, exitAgo)
+	if c.Status == entity.ContainerRestarting {
+		status = fmt.Sprintf("Restarting (%d) %s", c.ExitCode, exitAgo)
+	}
+	if c.OOMKilled {
+		status += " (OOMKilled)"
+	}
+	return status
+}
+
+func Pluralize(count int, singular, plural string) string {
+	if count == 1 {
+		return singular
+	}
+	return plural
+}
+
+// FormatBinarySize formats a size with binary units like docker does for memory, e.g. 1.5MiB.
+func FormatBinarySize(size uint64) string {
+	return formatSize(float64(size), 1024, []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB"})
+}
+
+// FormatDecimalSize formats a size with decimal units like docker does for IO, e.g. 1.5MB.
+func FormatDecimalSize(size uint64) string {
+	return formatSize(float64(size), 1000, []string{"B", "kB", "MB", "GB", "TB", "PB"})
+}
+
+func formatSize(size float64, base float64, units []string) string {
+	i := 0
+	for size >= base && i < len(units)-1 {
+		size /= base
+		i++
+	}
+	return fmt.Sprintf("%.4g%s", size, units[i])
+}
@@ -0,0 +1,111 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	loopControlPath = "/dev/loop-control"
+	// loopAttachRetries bounds the attempts to take a free loop device which someone else takes first.
+	loopAttachRetries = 10
+)
+
+// AttachLoopDevice attaches file to a free loop device. The device is opened with autoclear, the
+// kernel detaches it once the returned file is closed and no mount of the device is left.
+func AttachLoopDevice(file string) (*os.File, error) {
+	backing, err := os.OpenFile(file, os.O_RDWR, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = backing.Close() }()
+	control, err := os.OpenFile(loopControlPath, os.O_RDWR, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = control.Close() }()
+
+	for i := 0; i < loopAttachRetries; i++ {
+		index, err := unix.IoctlRetInt(int(control.Fd()), unix.LOOP_CTL_GET_FREE)
+		if err != nil {
+			return nil, err
+		}
+		device, err := os.OpenFile(fmt.Sprintf("/dev/loop%d", index), os.O_RDWR, 0)
+		if err != nil {
+			return nil, err
+		}
+		err = unix.IoctlSetInt(int(device.Fd()), unix.LOOP_SET_FD, int(backing.Fd()))
+		if errors.Is(err, unix.EBUSY) {
+			// the device was taken between LOOP_CTL_GET_FREE and LOOP_SET_FD
+			_ = device.Close()
+			continue
+		}
+		if err != nil {
+			_ = device.Close()
+			return nil, err
+		}
+		info := &unix.LoopInfo64{Flags: unix.LO_FLAGS_AUTOCLEAR}
+		copy(info.File_name[:], file)
+		if err = unix.IoctlLoopSetStatus64(int(device.Fd()), info); err != nil {
+			_ = unix.IoctlSetInt(int(device.Fd()), unix.LOOP_CLR_FD, 0)
+			_ = device.Close()
+			return nil, err
+		}
+		return device, nil
+	}
+	return nil, fmt.Errorf("no free loop device for %s after %d attempts", file, loopAttachRetries)
+}
+
+// IsMountPoint reports whether something is mounted at path in the mount namespace of the caller.
+func IsMountPoint(path string) (bool, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return false, err
+	}
+	mountPoints, err := readMountPoints()
+	if err != nil {
+		return false, err
+	}
+	return slices.Contains(mountPoints, path), nil
+}
+
+// MountPointOf returns the mount point of the mount path is on in the mount namespace of the caller.
+func MountPointOf(path string) (string, error) {
+	path, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", err
+	}
+	mountPoints, err := readMountPoints()
+	if err != nil {
+		return "", err
+	}
+	longest := "/"
+	for _, mountPoint := range mountPoints {
+		if len(mountPoint) > len(longest) && (path == mountPoint || strings.HasPrefix(path, mountPoint+"/")) {
+			longest = mountPoint
+		}
+	}
+	return longest, nil
+}
+
+func readMountPoints() ([]string, error) {
+	data, err := os.ReadFile("/proc/self/mountinfo")
+	if err != nil {
+		return nil, err
+	}
+	var mountPoints []string
+	for _, line := range strings.Split(string(data), "\n") {
+		// the mount point is the fifth field, whose spaces are escaped as \040
+		fields := strings.Fields(line)
+		if len(fields) > 4 {
+			mountPoints = append(mountPoints, strings.ReplaceAll(fields[4], `\040`, " "))
+		}
+	}
+	return mountPoints, nil
+}
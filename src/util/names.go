@@ -0,0 +1,66 @@
+package util
+
+import (
+	"fmt"
+	"math/rand"
+	"regexp"
+)
+
+var (
+	adjectives = []string{
+		"admiring", "affectionate", "agitated", "amazing", "angry", "awesome", "blissful", "bold",
+		"boring", "brave", "busy", "charming", "clever", "compassionate", "competent", "confident",
+		"cool", "cranky", "crazy", "dazzling", "determined", "distracted", "dreamy", "eager",
+		"ecstatic", "elastic", "elated", "elegant", "eloquent", "epic", "exciting", "fervent",
+		"festive", "flamboyant", "focused", "friendly", "frosty", "funny", "gallant", "gifted",
+		"goofy", "gracious", "great", "happy", "hardcore", "heuristic", "hopeful", "hungry",
+		"infallible", "inspiring", "intelligent", "interesting", "jolly", "jovial", "keen", "kind",
+		"laughing", "loving", "lucid", "magical", "modest", "musing", "mystifying", "naughty",
+		"nervous", "nice", "nifty", "nostalgic", "objective", "optimistic", "peaceful", "pedantic",
+		"pensive", "practical", "priceless", "quirky", "quizzical", "recursing", "relaxed", "reverent",
+		"romantic", "sad", "serene", "sharp", "silly", "sleepy", "stoic", "strange", "stupefied",
+		"suspicious", "sweet", "tender", "thirsty", "trusting", "upbeat", "vibrant", "vigilant",
+		"vigorous", "wizardly", "wonderful", "xenodochial", "youthful", "zealous", "zen",
+	}
+
+	surnames = []string{
+		"agnesi", "albattani", "allen", "almeida", "archimedes", "ardinghelli", "aryabhata", "babbage",
+		"banach", "bardeen", "bartik", "bassi", "bell", "bhabha", "bhaskara", "blackwell", "bohr",
+		"booth", "borg", "bose", "boyd", "brahmagupta", "brattain", "brown", "carson", "cerf",
+		"chandrasekhar", "clarke", "colden", "cori", "cray", "curie", "darwin", "davinci", "dijkstra",
+		"dubinsky", "easley", "edison", "einstein", "elion", "engelbart", "euclid", "euler", "fermat",
+		"fermi", "feynman", "franklin", "galileo", "gates", "goldberg", "goldstine", "golick",
+		"goodall", "haibt", "hamilton", "hawking", "heisenberg", "hermann", "hodgkin", "hoover",
+		"hopper", "hugle", "hypatia", "jackson", "jang", "jennings", "jepsen", "johnson", "joliot",
+		"jones", "kalam", "kapitsa", "kare", "keldysh", "keller", "kepler", "khorana", "kilby",
+		"kirch", "knuth", "kowalevski", "lalande", "lamarr", "lamport", "leakey", "leavitt",
+		"lederberg", "lehmann", "lewin", "lichterman", "liskov", "lovelace", "lumiere", "mahavira",
+		"margulis", "matsumoto", "maxwell", "mayer", "mccarthy", "mcclintock", "mclean", "meitner",
+		"mendel", "mendeleev", "minsky", "mirzakhani", "montalcini", "moore", "morse", "napier",
+		"nash", "neumann", "newton", "nobel", "noether", "northcutt", "noyce", "panini", "pare",
+		"pascal", "pasteur", "payne", "perlman", "pike", "poincare", "poitras", "ptolemy", "raman",
+		"ramanujan", "ride", "ritchie", "robinson", "roentgen", "rosalind", "rubin", "saha",
+		"sammet", "shamir", "shannon", "shaw", "shirley", "shockley", "sinoussi", "snyder", "spence",
+		"stonebraker", "sutherland", "swanson", "swartz", "swirles", "taussig", "tesla", "tharp",
+		"thompson", "torvalds", "turing", "varahamihira", "vaughan", "villani", "visvesvaraya",
+		"volhard", "wescoff", "wiles", "williams", "wilson", "wing", "wozniak", "wright", "wu",
+		"yalow", "yonath", "zhukovsky",
+	}
+
+	validContainerName = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]+$`)
+)
+
+// GenerateContainerName returns a random name like "focused_turing", a number is appended when
+// retry is not 0 so that callers running out of unused names can still find one.
+func GenerateContainerName(retry int) string {
+	name := fmt.Sprintf("%s_%s", adjectives[rand.Intn(len(adjectives))], surnames[rand.Intn(len(surnames))])
+	if retry > 0 {
+		name = fmt.Sprintf("%s%d", name, rand.Intn(10))
+	}
+	return name
+}
+
+// IsValidContainerName follows the rule of docker: [a-zA-Z0-9][a-zA-Z0-9_.-]+
+func IsValidContainerName(name string) bool {
+	return validContainerName.MatchString(name)
+}
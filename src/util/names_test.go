@@ -0,0 +1,34 @@
+package util
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateContainerName(t *testing.T) {
+	for retry := 0; retry < 3; retry++ {
+		name := GenerateContainerName(retry)
+		assert.True(t, IsValidContainerName(name), name)
+		adjective, surname, ok := strings.Cut(name, "_")
+		assert.True(t, ok, name)
+		assert.Contains(t, adjectives, adjective)
+		if retry == 0 {
+			assert.Contains(t, surnames, surname)
+		} else {
+			// a digit is appended on retries
+			assert.Contains(t, surnames, surname[:len(surname)-1])
+			assert.Contains(t, "0123456789", surname[len(surname)-1:])
+		}
+	}
+}
+
+func TestIsValidContainerName(t *testing.T) {
+	for _, name := range []string{"web", "db1", "focused_turing", "my-app.v2", "0x", "A_b-c.d"} {
+		assert.True(t, IsValidContainerName(name), name)
+	}
+	for _, name := range []string{"", "a", "/web", "-web", "_web", ".web", "web/db", "web app", "web:1", "wéb"} {
+		assert.False(t, IsValidContainerName(name), name)
+	}
+}
@@ -309,7 +309,7 @@ func createVethPair(vethNs, vethHost string, pid int) error {
 		logrus.Errorf("[createVethPair]error link veth pair: %v", err)
 		return err
 	}
-	logrus.Info("Created veth pair: %s and %s\n", vethNs, vethHost)
+	logrus.Infof("Created veth pair: %s and %s", vethNs, vethHost)
 	return nil
 }
 
@@ -331,7 +331,7 @@ func setMaster(vethHostName string, bridgeName string) error {
 	}
 	err = netlink.LinkSetMaster(veth, bridge)
 	if err != nil {
-		logrus.Errorf("[setMaster]error set master: veth = %s, bridge = %s, err = %v", veth, bridge, err)
+		logrus.Errorf("[setMaster]error set master: veth = %s, bridge = %s, err = %v", veth, bridgeName, err)
 		return err
 	}
 
@@ -439,3 +439,16 @@ func exitNs(origins, newNs netns.NsHandle) error {
 	defer func() { _ = newNs.Close() }()
 	return netns.Set(origins)
 }
+
+// LinkStatistics returns the bytes received and transmitted by a link.
+func LinkStatistics(name string) (rx, tx uint64, err error) {
+	link, err := netlink.LinkByName(name)
+	if err != nil {
+		return 0, 0, err
+	}
+	stats := link.Attrs().Statistics
+	if stats == nil {
+		return 0, 0, nil
+	}
+	return stats.RxBytes, stats.TxBytes, nil
+}
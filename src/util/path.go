@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"syscall"
 
 	"github.com/sirupsen/logrus"
@@ -117,3 +118,47 @@ func UnmountOverlayFS(mergedDir string) error {
 	logrus.Infof("unmount overlay success: %s", mergedDir)
 	return nil
 }
+
+// maxSymlinks is the number of symlinks SecureJoin follows before it gives up, like the kernel.
+const maxSymlinks = 40
+
+// SecureJoin joins path to root like filepath.Join, but resolves the symlinks in path as if root
+// was /, so that a symlink of an image cannot make the result leave root. The parts of path which
+// do not exist are joined as they are.
+func SecureJoin(root string, path string) (string, error) {
+	resolved := "/"
+	remaining := filepath.Clean("/" + path)
+	links := 0
+	for remaining != "" {
+		var name string
+		name, remaining, _ = strings.Cut(strings.TrimPrefix(remaining, "/"), "/")
+		switch name {
+		case "", ".":
+			continue
+		case "..":
+			resolved = filepath.Dir(resolved)
+			continue
+		}
+		next := filepath.Join(resolved, name)
+		info, err := os.Lstat(filepath.Join(root, next))
+		if os.IsNotExist(err) || (err == nil && info.Mode()&os.ModeSymlink == 0) {
+			resolved = next
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		if links++; links > maxSymlinks {
+			return "", &os.PathError{Op: "securejoin", Path: path, Err: syscall.ELOOP}
+		}
+		target, err := os.Readlink(filepath.Join(root, next))
+		if err != nil {
+			return "", err
+		}
+		if filepath.IsAbs(target) {
+			resolved = "/"
+		}
+		remaining = target + "/" + remaining
+	}
+	return filepath.Join(root, resolved), nil
+}
@@ -0,0 +1,59 @@
+package util
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecureJoin(t *testing.T) {
+	root := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, "etc"), 0755))
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, "var/lib"), 0755))
+	for link, target := range map[string]string{
+		"abs":      "/etc",
+		"host":     "/tmp",
+		"etc/rel":  "../var/lib",
+		"escape":   "../../../../etc",
+		"var/up":   "../../..",
+		"dangling": "/missing/dir",
+		"chain":    "abs",
+		"loop1":    "loop2",
+		"loop2":    "loop1",
+		"self":     "self",
+	} {
+		assert.NoError(t, os.Symlink(target, filepath.Join(root, link)))
+	}
+
+	valid := map[string]string{
+		"/":              "/",
+		"etc":            "/etc",
+		"/etc/passwd":    "/etc/passwd",
+		"/new/dir":       "/new/dir",
+		"/abs/passwd":    "/etc/passwd",
+		"/host":          "/tmp",
+		"/etc/rel/data":  "/var/lib/data",
+		"/escape/passwd": "/etc/passwd",
+		"/var/up/etc":    "/etc",
+		"/../../etc":     "/etc",
+		"/var/../../etc": "/etc",
+		"/dangling/file": "/missing/dir/file",
+		"/chain/passwd":  "/etc/passwd",
+		// .. is cleaned before symlinks are resolved, like with filepath.Join
+		"/etc/rel/../data": "/etc/data",
+	}
+	for path, expected := range valid {
+		joined, err := SecureJoin(root, path)
+		assert.NoError(t, err, path)
+		assert.Equal(t, filepath.Join(root, expected), joined, path)
+	}
+
+	for _, path := range []string{"/loop1", "/self/file", "/etc/../loop2/x"} {
+		_, err := SecureJoin(root, path)
+		assert.True(t, errors.Is(err, syscall.ELOOP), path)
+	}
+}
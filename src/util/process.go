@@ -2,16 +2,82 @@ package util
 
 import (
 	"fmt"
+	"os"
+	"strconv"
+	"strings"
 	"syscall"
+	"unsafe"
 
 	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
 )
 
+// si_code of SIGCHLD, see sigaction(2)
+const (
+	cldExited = 1
+	cldKilled = 2
+	cldDumped = 3
+)
+
+// sigchldInfo is the 64-bit layout of siginfo_t filled in by waitid(2), unix.Siginfo keeps the
+// fields of SIGCHLD opaque.
+type sigchldInfo struct {
+	Signo  int32
+	Errno  int32
+	Code   int32
+	_      int32
+	Pid    int32
+	Uid    uint32
+	Status int32
+}
+
 func KillProcessByPID(pid int, signal int) error {
-	err := syscall.Kill(pid, syscall.SIGKILL)
+	err := syscall.Kill(pid, syscall.Signal(signal))
 	if err != nil {
 		return fmt.Errorf("syscall kill process %d failed: %w", pid, err)
 	}
 	logrus.Infof("process %d killed successfully with signal %d", pid, signal)
 	return nil
 }
+
+// ExitStatus converts the wait status of a process into an exit code and the signal which
+// terminated it, a process killed by a signal exits with 128 + signal like it does in a shell.
+func ExitStatus(ws syscall.WaitStatus) (exitCode int, signal int) {
+	if ws.Signaled() {
+		return 128 + int(ws.Signal()), int(ws.Signal())
+	}
+	return ws.ExitStatus(), 0
+}
+
+// WaitPidfd reaps the exited child referred to by pidfd and returns its exit code and the signal
+// which terminated it, see ExitStatus. The child must have exited already.
+func WaitPidfd(pidfd int) (exitCode int, signal int, err error) {
+	var info unix.Siginfo
+	if err = unix.Waitid(unix.P_PIDFD, pidfd, &info, unix.WEXITED|unix.WNOHANG, nil); err != nil {
+		return 0, 0, err
+	}
+	child := (*sigchldInfo)(unsafe.Pointer(&info))
+	switch child.Code {
+	case cldExited:
+		return int(child.Status), 0, nil
+	case cldKilled, cldDumped:
+		return 128 + int(child.Status), int(child.Status), nil
+	default:
+		return 0, 0, fmt.Errorf("unexpected si_code %d of child %d", child.Code, child.Pid)
+	}
+}
+
+// ParentPid reads the pid of the parent of a process from /proc/<pid>/stat.
+func ParentPid(pid int) (int, error) {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return 0, err
+	}
+	// the command in the second field may contain spaces and parentheses
+	stat := string(data)
+	fields := strings.Fields(stat[strings.LastIndexByte(stat, ')')+1:])
+	if len(fields) < 2 {
+		return 0, fmt.Errorf("malformed stat of process %d", pid)
+	}
+	return strconv.Atoi(fields[1])
+}
@@ -0,0 +1,32 @@
+package util
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/0x822a5b87/tiny-docker/src/constant"
+	"golang.org/x/sys/unix"
+)
+
+// maxSignal is SIGRTMAX on linux
+const maxSignal = 64
+
+// ParseSignal accepts a signal by its number or its name, with or without the SIG prefix, e.g.
+// 15, TERM or SIGTERM.
+func ParseSignal(s string) (unix.Signal, error) {
+	if n, err := strconv.Atoi(s); err == nil {
+		if n <= 0 || n > maxSignal {
+			return 0, constant.ErrInvalidSignal.WrapMessage(s)
+		}
+		return unix.Signal(n), nil
+	}
+	name := strings.ToUpper(s)
+	if !strings.HasPrefix(name, "SIG") {
+		name = "SIG" + name
+	}
+	sig := unix.SignalNum(name)
+	if sig == 0 {
+		return 0, constant.ErrInvalidSignal.WrapMessage(s)
+	}
+	return sig, nil
+}
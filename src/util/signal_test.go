@@ -0,0 +1,32 @@
+package util
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/0x822a5b87/tiny-docker/src/constant"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/sys/unix"
+)
+
+func TestParseSignal(t *testing.T) {
+	for s, expected := range map[string]unix.Signal{
+		"9":       unix.SIGKILL,
+		"15":      unix.SIGTERM,
+		"64":      unix.Signal(64),
+		"TERM":    unix.SIGTERM,
+		"SIGTERM": unix.SIGTERM,
+		"sigkill": unix.SIGKILL,
+		"hup":     unix.SIGHUP,
+		"SIGUSR1": unix.SIGUSR1,
+	} {
+		sig, err := ParseSignal(s)
+		assert.NoError(t, err, s)
+		assert.Equal(t, expected, sig, s)
+	}
+
+	for _, s := range []string{"", "0", "-1", "-15", "65", "SIGFOO", "FOO", "SIG", "1.5", "TERM "} {
+		_, err := ParseSignal(s)
+		assert.True(t, errors.Is(err, constant.ErrInvalidSignal), s)
+	}
+}
@@ -0,0 +1,59 @@
+package volume
+
+import (
+	"os"
+
+	"github.com/0x822a5b87/tiny-docker/src/entity"
+)
+
+// VolumeDriver keeps the data of volumes. Whatever a driver stores a volume in, the data is
+// found at the Mountpoint of the volume while it is mounted, which is where the containers
+// using the volume bind mount it from.
+type VolumeDriver interface {
+	Create(name string, options map[string]string) (*entity.Volume, error)
+	// Mount makes the data of a volume available at its Mountpoint, it is called once a
+	// container uses the volume and again when mini-dockerd starts.
+	Mount(volume *entity.Volume) error
+	// Unmount is called once no container uses the volume anymore.
+	Unmount(volume *entity.Volume) error
+	Remove(volume *entity.Volume) error
+	Capabilities() Capabilities
+}
+
+type Capabilities struct {
+	// Scope is local for the volumes only this host sees, like with docker.
+	Scope string
+	// Options are the options Create takes.
+	Options []string
+}
+
+// LocalDriver keeps the data of a volume in a directory on the host.
+type LocalDriver struct{}
+
+func (driver *LocalDriver) Create(name string, options map[string]string) (*entity.Volume, error) {
+	volume := &entity.Volume{
+		Name:       name,
+		Driver:     entity.VolumeLocal,
+		Mountpoint: Mountpoint(name),
+	}
+	if err := os.MkdirAll(volume.Mountpoint, 0755); err != nil {
+		return nil, err
+	}
+	return volume, nil
+}
+
+func (driver *LocalDriver) Mount(volume *entity.Volume) error {
+	return nil
+}
+
+func (driver *LocalDriver) Unmount(volume *entity.Volume) error {
+	return nil
+}
+
+func (driver *LocalDriver) Remove(volume *entity.Volume) error {
+	return os.RemoveAll(volume.Mountpoint)
+}
+
+func (driver *LocalDriver) Capabilities() Capabilities {
+	return Capabilities{Scope: "local"}
+}
@@ -0,0 +1,134 @@
+package volume
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/0x822a5b87/tiny-docker/src/conf"
+	"github.com/0x822a5b87/tiny-docker/src/constant"
+	"github.com/0x822a5b87/tiny-docker/src/entity"
+	"github.com/0x822a5b87/tiny-docker/src/subsystem"
+	"github.com/0x822a5b87/tiny-docker/src/util"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
+)
+
+// loopSizeOption is the size of the image of a volume, like 1g.
+const loopSizeOption = "size"
+
+// LoopDriver keeps the data of a volume in an ext4 image of a fixed size, which is mounted
+// through a loop device. A container filling the volume runs out of space in the image instead
+// of on the host. The image is sparse, so it only takes the space of what is written to it.
+type LoopDriver struct{}
+
+func (driver *LoopDriver) Create(name string, options map[string]string) (*entity.Volume, error) {
+	size, err := loopSize(options)
+	if err != nil {
+		return nil, err
+	}
+	volume := &entity.Volume{
+		Name:       name,
+		Driver:     entity.VolumeLoop,
+		Mountpoint: Mountpoint(name),
+		Options:    options,
+	}
+	if err = driver.createImage(volume, size); err != nil {
+		_ = driver.Remove(volume)
+		return nil, err
+	}
+	return volume, nil
+}
+
+func (driver *LoopDriver) createImage(volume *entity.Volume, size int64) error {
+	image := loopImage(volume.Name)
+	if err := util.EnsureFilePathExist(filepath.Dir(image)); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(image, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	err = f.Truncate(size)
+	_ = f.Close()
+	if err != nil {
+		return err
+	}
+	data, err := exec.Command("mkfs.ext4", "-q", "-F", "-m", "0", image).CombinedOutput()
+	if err != nil {
+		logrus.Errorf("mkfs error: image = {%s}, error = {%s}, info = {%s}", image, err.Error(), string(data))
+		return fmt.Errorf("mkfs.ext4 %s: %w", image, err)
+	}
+	if err = os.MkdirAll(volume.Mountpoint, 0755); err != nil {
+		return err
+	}
+
+	// mkfs.ext4 leaves lost+found behind, which would keep the content of the image from being
+	// copied into a fresh volume
+	if err = driver.Mount(volume); err != nil {
+		return err
+	}
+	err = os.Remove(filepath.Join(volume.Mountpoint, "lost+found"))
+	if unmountErr := driver.Unmount(volume); err == nil {
+		err = unmountErr
+	}
+	return err
+}
+
+func (driver *LoopDriver) Mount(volume *entity.Volume) error {
+	mounted, err := util.IsMountPoint(volume.Mountpoint)
+	if err != nil || mounted {
+		return err
+	}
+	device, err := util.AttachLoopDevice(loopImage(volume.Name))
+	if err != nil {
+		return err
+	}
+	// the mount keeps the loop device attached once it is closed
+	defer func() { _ = device.Close() }()
+	if err = unix.Mount(device.Name(), volume.Mountpoint, "ext4", 0, ""); err != nil {
+		return fmt.Errorf("mount %s at %s: %w", device.Name(), volume.Mountpoint, err)
+	}
+	logrus.Infof("mount volume {%s} from %s", volume.Name, device.Name())
+	return nil
+}
+
+func (driver *LoopDriver) Unmount(volume *entity.Volume) error {
+	mounted, err := util.IsMountPoint(volume.Mountpoint)
+	if err != nil || !mounted {
+		return err
+	}
+	return unix.Unmount(volume.Mountpoint, 0)
+}
+
+func (driver *LoopDriver) Remove(volume *entity.Volume) error {
+	if err := driver.Unmount(volume); err != nil {
+		return err
+	}
+	if err := os.Remove(loopImage(volume.Name)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return os.RemoveAll(volume.Mountpoint)
+}
+
+func (driver *LoopDriver) Capabilities() Capabilities {
+	return Capabilities{Scope: "local", Options: []string{loopSizeOption}}
+}
+
+func loopImage(name string) string {
+	return filepath.Join(conf.RuntimeVolumeLoopPath.Get(), name+".img")
+}
+
+func loopSize(options map[string]string) (int64, error) {
+	value, ok := options[loopSizeOption]
+	if !ok {
+		return 0, constant.ErrInvalidVolume.WrapMessage("the loop driver needs --opt size")
+	}
+	size, err := subsystem.SizeToBytes(value)
+	if err != nil || size <= 0 || size == math.MaxInt64 {
+		return 0, constant.ErrInvalidVolume.WrapMessage(fmt.Sprintf("invalid size %s", value))
+	}
+	return size, nil
+}
@@ -0,0 +1,67 @@
+package volume
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/0x822a5b87/tiny-docker/src/constant"
+	"github.com/0x822a5b87/tiny-docker/src/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+// isMounted tells a mount point by the device, which differs from the one of its parent.
+func isMounted(path string) bool {
+	var st, parent syscall.Stat_t
+	if syscall.Stat(path, &st) != nil || syscall.Stat(filepath.Dir(path), &parent) != nil {
+		return false
+	}
+	return st.Dev != parent.Dev
+}
+
+func TestLoopSize(t *testing.T) {
+	size, err := loopSize(map[string]string{"size": "1g"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1<<30), size)
+
+	for _, options := range []map[string]string{nil, {"size": "0"}, {"size": "max"}, {"size": "1x"}} {
+		_, err = loopSize(options)
+		assert.ErrorIs(t, err, constant.ErrInvalidVolume, options)
+	}
+}
+
+func TestLoopDriver(t *testing.T) {
+	if _, err := os.Stat("/dev/loop-control"); err != nil || os.Geteuid() != 0 {
+		t.Skip("loop devices need root")
+	}
+	if _, err := exec.LookPath("mkfs.ext4"); err != nil {
+		t.Skip("mkfs.ext4 is missing")
+	}
+	volumes := newTestVolumes(t)
+	volume, err := volumes.CreateVolume("bounded", entity.VolumeLoop, map[string]string{"size": "8m"})
+	assert.NoError(t, err)
+	assert.Equal(t, entity.VolumeLoop, volume.Driver)
+	assert.FileExists(t, loopImage("bounded"))
+	// a fresh volume is empty and left unmounted until a container uses it
+	assert.False(t, isMounted(volume.Mountpoint))
+
+	_, err = volumes.Acquire("bounded", false, "c1")
+	assert.NoError(t, err)
+	defer func() { _ = volumes.Release("bounded", "c1", false) }()
+	assert.True(t, isMounted(volume.Mountpoint))
+	entries, err := os.ReadDir(volume.Mountpoint)
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+
+	// the volume is full long before the host is
+	err = os.WriteFile(filepath.Join(volume.Mountpoint, "big"), make([]byte, 16<<20), 0644)
+	assert.ErrorIs(t, err, syscall.ENOSPC)
+
+	assert.NoError(t, volumes.Release("bounded", "c1", false))
+	assert.False(t, isMounted(volume.Mountpoint))
+	_, err = volumes.RemoveVolume("bounded")
+	assert.NoError(t, err)
+	assert.NoFileExists(t, loopImage("bounded"))
+}
@@ -0,0 +1,12 @@
+package volume
+
+import (
+	"github.com/0x822a5b87/tiny-docker/src/entity"
+)
+
+type VolumeStore interface {
+	GetAll() ([]*entity.Volume, error)
+	Get(name string) (*entity.Volume, error)
+	Update(volume *entity.Volume) error
+	Delete(name string) error
+}
@@ -0,0 +1,97 @@
+package volume
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/0x822a5b87/tiny-docker/src/conf"
+	"github.com/0x822a5b87/tiny-docker/src/constant"
+	"github.com/0x822a5b87/tiny-docker/src/entity"
+	"github.com/0x822a5b87/tiny-docker/src/util"
+	"github.com/sirupsen/logrus"
+)
+
+func NewFileVolumeStore() (VolumeStore, error) {
+	store := &FileVolumeStore{
+		mutex:   sync.RWMutex{},
+		volumes: make(map[string]*entity.Volume),
+	}
+	volumeBasePath := conf.RuntimeVolumePath.Get()
+	if err := util.EnsureFilePathExist(volumeBasePath); err != nil {
+		return nil, err
+	}
+	filesInDir, err := util.ReadAllFilesInDir(volumeBasePath)
+	if err != nil {
+		logrus.Errorf("ReadAllFilesInDir error: %s", err)
+		return nil, err
+	}
+	for _, data := range filesInDir {
+		volume := &entity.Volume{}
+		if err = json.Unmarshal(data, volume); err != nil {
+			logrus.Errorf("error unmarshal volume from file: %s", err)
+			continue
+		}
+		store.volumes[volume.Name] = volume
+	}
+
+	return store, nil
+}
+
+type FileVolumeStore struct {
+	mutex   sync.RWMutex
+	volumes map[string]*entity.Volume
+}
+
+func (store *FileVolumeStore) GetAll() ([]*entity.Volume, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	volumes := make([]*entity.Volume, 0, len(store.volumes))
+	for _, volume := range store.volumes {
+		volumes = append(volumes, volume)
+	}
+	return volumes, nil
+}
+
+func (store *FileVolumeStore) Get(name string) (*entity.Volume, error) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	volume, ok := store.volumes[name]
+	if !ok {
+		return nil, constant.ErrResourceNotFound
+	}
+	return volume, nil
+}
+
+func (store *FileVolumeStore) Update(volume *entity.Volume) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	data, err := json.Marshal(volume)
+	if err != nil {
+		return err
+	}
+	if err = os.WriteFile(store.getVolumeFile(volume.Name), data, 0644); err != nil {
+		return err
+	}
+	store.volumes[volume.Name] = volume
+	return nil
+}
+
+func (store *FileVolumeStore) Delete(name string) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	if _, ok := store.volumes[name]; !ok {
+		return constant.ErrResourceNotFound
+	}
+	if err := os.Remove(store.getVolumeFile(name)); err != nil {
+		return err
+	}
+	delete(store.volumes, name)
+	return nil
+}
+
+func (store *FileVolumeStore) getVolumeFile(name string) string {
+	volumeBasePath := conf.RuntimeVolumePath.Get()
+	return filepath.Join(volumeBasePath, name)
+}
@@ -0,0 +1,257 @@
+package volume
+
+import (
+	"cmp"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/0x822a5b87/tiny-docker/src/conf"
+	"github.com/0x822a5b87/tiny-docker/src/constant"
+	"github.com/0x822a5b87/tiny-docker/src/entity"
+	"github.com/0x822a5b87/tiny-docker/src/util"
+	"github.com/sirupsen/logrus"
+)
+
+// Mountpoint returns the directory holding the data of a volume, the container process resolves
+// the named volumes it mounts with it.
+func Mountpoint(name string) string {
+	return filepath.Join(conf.RuntimeVolumeDataPath.Get(), name)
+}
+
+func NewVolumes() (*Volumes, error) {
+	volumeStore, err := NewFileVolumeStore()
+	if err != nil {
+		return nil, err
+	}
+	if err = util.EnsureFilePathExist(conf.RuntimeVolumeDataPath.Get()); err != nil {
+		return nil, err
+	}
+	v := &Volumes{
+		Mutex:       sync.Mutex{},
+		volumeStore: volumeStore,
+		volumeDrivers: map[string]VolumeDriver{
+			entity.VolumeLocal: &LocalDriver{},
+			entity.VolumeLoop:  &LoopDriver{},
+		},
+	}
+	v.mountVolumes()
+	return v, nil
+}
+
+// Volumes keeps the named volumes along with the containers using them. A volume is referenced
+// by every container which mounts it from its creation to its removal, and cannot be removed
+// while it is referenced.
+type Volumes struct {
+	sync.Mutex
+	volumeStore   VolumeStore
+	volumeDrivers map[string]VolumeDriver
+}
+
+// CreateVolume creates a volume with a driver, the local one unless driverName is given. A
+// random name is chosen for the volume when name is empty.
+func (v *Volumes) CreateVolume(name string, driverName string, options map[string]string) (*entity.Volume, error) {
+	v.Lock()
+	defer v.Unlock()
+	if name == "" {
+		name, _ = conf.GenUUID()
+	}
+	if _, err := v.volumeStore.Get(name); err == nil {
+		return nil, constant.ErrVolumeExists.WrapMessage(name)
+	}
+	return v.createVolume(name, driverName, options, false)
+}
+
+// GetDriver returns a volume driver by its name.
+func (v *Volumes) GetDriver(driverName string) (VolumeDriver, error) {
+	driver, ok := v.volumeDrivers[driverName]
+	if !ok {
+		return nil, constant.ErrInvalidVolume.WrapMessage(fmt.Sprintf("unknown volume driver %s", driverName))
+	}
+	return driver, nil
+}
+
+func (v *Volumes) GetVolume(name string) (*entity.Volume, error) {
+	volume, err := v.volumeStore.Get(name)
+	if err != nil {
+		return nil, constant.ErrVolumeNotFound.WrapMessage(name)
+	}
+	return volume, nil
+}
+
+// GetVolumes returns the volumes sorted by name.
+func (v *Volumes) GetVolumes() ([]*entity.Volume, error) {
+	volumes, err := v.volumeStore.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(volumes, func(a, b *entity.Volume) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return volumes, nil
+}
+
+// RemoveVolume removes a volume along with its data, unless a container uses it.
+func (v *Volumes) RemoveVolume(name string) (*entity.Volume, error) {
+	v.Lock()
+	defer v.Unlock()
+	volume, err := v.GetVolume(name)
+	if err != nil {
+		return nil, err
+	}
+	if volume.InUse() {
+		return nil, constant.ErrVolumeInUse.WrapMessage(fmt.Sprintf("%s is used by %v", name, volume.Containers))
+	}
+	return volume, v.removeVolume(volume)
+}
+
+// PruneVolumes removes the volumes no container uses, only the anonymous ones unless all is set.
+func (v *Volumes) PruneVolumes(all bool) ([]*entity.Volume, error) {
+	v.Lock()
+	defer v.Unlock()
+	volumes, err := v.GetVolumes()
+	if err != nil {
+		return nil, err
+	}
+	pruned := make([]*entity.Volume, 0)
+	for _, volume := range volumes {
+		if volume.InUse() || !(all || volume.Anonymous) {
+			continue
+		}
+		if err = v.removeVolume(volume); err != nil {
+			return pruned, err
+		}
+		pruned = append(pruned, volume)
+	}
+	return pruned, nil
+}
+
+// Acquire references a volume for container id, creating the volume when it does not exist yet
+// like docker run does.
+func (v *Volumes) Acquire(name string, anonymous bool, id entity.ContainerId) (*entity.Volume, error) {
+	v.Lock()
+	defer v.Unlock()
+	volume, err := v.volumeStore.Get(name)
+	if err != nil {
+		if volume, err = v.createVolume(name, entity.VolumeLocal, nil, anonymous); err != nil {
+			return nil, err
+		}
+	}
+	if slices.Contains(volume.Containers, id) {
+		return volume, nil
+	}
+	driver, err := v.GetDriver(volume.Driver)
+	if err != nil {
+		return nil, err
+	}
+	if !volume.InUse() {
+		if err = driver.Mount(volume); err != nil {
+			return nil, err
+		}
+	}
+	// the stored volume may be read meanwhile, so it is replaced rather than modified
+	acquired := *volume
+	acquired.Containers = append(slices.Clone(volume.Containers), id)
+	if err = v.volumeStore.Update(&acquired); err != nil {
+		if !volume.InUse() {
+			_ = driver.Unmount(volume)
+		}
+		return nil, err
+	}
+	return &acquired, nil
+}
+
+// Release drops the reference of container id to a volume, an anonymous volume is removed as
+// well once it is unused if removeAnonymous is set.
+func (v *Volumes) Release(name string, id entity.ContainerId, removeAnonymous bool) error {
+	v.Lock()
+	defer v.Unlock()
+	volume, err := v.volumeStore.Get(name)
+	if err != nil {
+		// removed already by an earlier attempt
+		return nil
+	}
+	released := *volume
+	released.Containers = slices.DeleteFunc(slices.Clone(volume.Containers), func(c entity.ContainerId) bool {
+		return c == id
+	})
+	if volume.InUse() && !released.InUse() {
+		driver, err := v.GetDriver(volume.Driver)
+		if err != nil {
+			return err
+		}
+		if err = driver.Unmount(volume); err != nil {
+			return err
+		}
+	}
+	if removeAnonymous && released.Anonymous && !released.InUse() {
+		return v.removeVolume(&released)
+	}
+	if len(released.Containers) == len(volume.Containers) {
+		return nil
+	}
+	return v.volumeStore.Update(&released)
+}
+
+func (v *Volumes) createVolume(name string, driverName string, options map[string]string, anonymous bool) (*entity.Volume, error) {
+	if !conf.IsValidVolumeName(name) {
+		return nil, constant.ErrInvalidVolume.WrapMessage(fmt.Sprintf("%s is not a valid volume name", name))
+	}
+	driverName = cmp.Or(driverName, entity.VolumeLocal)
+	driver, err := v.GetDriver(driverName)
+	if err != nil {
+		return nil, err
+	}
+	for option := range options {
+		if !slices.Contains(driver.Capabilities().Options, option) {
+			return nil, constant.ErrInvalidVolume.WrapMessage(fmt.Sprintf("unknown option %s of volume driver %s", option, driverName))
+		}
+	}
+	volume, err := driver.Create(name, options)
+	if err != nil {
+		return nil, err
+	}
+	volume.CreatedAt = time.Now().UnixMilli()
+	volume.Anonymous = anonymous
+	if err = v.volumeStore.Update(volume); err != nil {
+		_ = driver.Remove(volume)
+		return nil, err
+	}
+	logrus.Infof("Create volume {%s} with driver {%s}", name, volume.Driver)
+	return volume, nil
+}
+
+func (v *Volumes) removeVolume(volume *entity.Volume) error {
+	driver, err := v.GetDriver(volume.Driver)
+	if err != nil {
+		return err
+	}
+	if err = driver.Remove(volume); err != nil {
+		return err
+	}
+	if err = v.volumeStore.Delete(volume.Name); err != nil {
+		return err
+	}
+	logrus.Infof("Remove volume {%s}", volume.Name)
+	return nil
+}
+
+// mountVolumes mounts the volumes in use again, their mounts are gone after the host rebooted.
+func (v *Volumes) mountVolumes() {
+	volumes, _ := v.volumeStore.GetAll()
+	for _, volume := range volumes {
+		if !volume.InUse() {
+			continue
+		}
+		driver, err := v.GetDriver(volume.Driver)
+		if err == nil {
+			err = driver.Mount(volume)
+		}
+		if err != nil {
+			logrus.Errorf("error mount volume {%s}: %v", volume.Name, err)
+		}
+	}
+}
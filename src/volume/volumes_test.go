@@ -0,0 +1,97 @@
+package volume
+
+import (
+	"os"
+	"testing"
+
+	"github.com/0x822a5b87/tiny-docker/src/conf"
+	"github.com/0x822a5b87/tiny-docker/src/constant"
+	"github.com/0x822a5b87/tiny-docker/src/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestVolumes(t *testing.T) *Volumes {
+	conf.GlobalConfig = conf.Config{
+		Meta: conf.MetaConfig{Name: "test"},
+		Fs:   conf.FsConfig{Root: t.TempDir()},
+	}
+	conf.Environ()
+	volumes, err := NewVolumes()
+	assert.NoError(t, err)
+	return volumes
+}
+
+func TestVolumes(t *testing.T) {
+	volumes := newTestVolumes(t)
+	volume, err := volumes.CreateVolume("data", "", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.VolumeLocal, volume.Driver)
+	assert.Equal(t, Mountpoint("data"), volume.Mountpoint)
+	assert.DirExists(t, volume.Mountpoint)
+	_, err = volumes.CreateVolume("data", "", nil)
+	assert.ErrorIs(t, err, constant.ErrVolumeExists)
+	_, err = volumes.CreateVolume("-data", "", nil)
+	assert.ErrorIs(t, err, constant.ErrInvalidVolume)
+	_, err = volumes.CreateVolume("other", "nfs", nil)
+	assert.ErrorIs(t, err, constant.ErrInvalidVolume)
+	_, err = volumes.CreateVolume("other", "", map[string]string{"size": "1g"})
+	assert.ErrorIs(t, err, constant.ErrInvalidVolume)
+
+	// the volume survives a restart of mini-dockerd
+	volumes, err = NewVolumes()
+	assert.NoError(t, err)
+	volume, err = volumes.GetVolume("data")
+	assert.NoError(t, err)
+	assert.Equal(t, "data", volume.Name)
+	_, err = volumes.GetVolume("missing")
+	assert.ErrorIs(t, err, constant.ErrVolumeNotFound)
+
+	removed, err := volumes.RemoveVolume("data")
+	assert.NoError(t, err)
+	assert.Equal(t, "data", removed.Name)
+	assert.NoDirExists(t, removed.Mountpoint)
+	all, err := volumes.GetVolumes()
+	assert.NoError(t, err)
+	assert.Empty(t, all)
+}
+
+func TestVolumesReference(t *testing.T) {
+	volumes := newTestVolumes(t)
+	volume, err := volumes.Acquire("data", false, "c1")
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.ContainerId{"c1"}, volume.Containers)
+	volume, err = volumes.Acquire("data", false, "c2")
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.ContainerId{"c1", "c2"}, volume.Containers)
+	_, err = volumes.Acquire("anonymous", true, "c1")
+	assert.NoError(t, err)
+
+	_, err = volumes.RemoveVolume("data")
+	assert.ErrorIs(t, err, constant.ErrVolumeInUse)
+	pruned, err := volumes.PruneVolumes(true)
+	assert.NoError(t, err)
+	assert.Empty(t, pruned)
+
+	assert.NoError(t, volumes.Release("data", "c1", true))
+	assert.NoError(t, volumes.Release("anonymous", "c1", true))
+	_, err = volumes.GetVolume("anonymous")
+	assert.ErrorIs(t, err, constant.ErrVolumeNotFound)
+	assert.NoError(t, volumes.Release("data", "c2", true))
+	volume, err = volumes.GetVolume("data")
+	assert.NoError(t, err)
+	assert.False(t, volume.InUse())
+
+	// a named volume is only pruned with all
+	_, err = volumes.Acquire("other", true, "c3")
+	assert.NoError(t, err)
+	assert.NoError(t, volumes.Release("other", "c3", false))
+	pruned, err = volumes.PruneVolumes(false)
+	assert.NoError(t, err)
+	assert.Len(t, pruned, 1)
+	assert.Equal(t, "other", pruned[0].Name)
+	pruned, err = volumes.PruneVolumes(true)
+	assert.NoError(t, err)
+	assert.Len(t, pruned, 1)
+	_, err = os.Stat(Mountpoint("data"))
+	assert.True(t, os.IsNotExist(err))
+}